@@ -0,0 +1,328 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/expression"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+	"time"
+)
+
+const (
+	AssignmentCollection = "assignments"
+	ResourceType         = "resourceType"
+	ResourceId           = "resourceId"
+	AssignmentTagId      = "tagId"
+	CreatedAt            = "createdAt"
+	ResourceTypeParam    = "type"
+	ResourceIdParam      = "id"
+	TagIdParam           = "tagId"
+	TagsQueryParam       = "tags"
+)
+
+// @Summary Assign tags to a resource
+// @ID assign-tags
+// @Description Applies the given tags to the resource. Tags already applied are left untouched.
+// @Accept  json
+// @Produce  json
+// @Param type path string true "Resource type"
+// @Param id path string true "Resource ID"
+// @Param tags body model.AssignTagsRequest true "Tags to assign"
+// @Success 204 "Tags assigned"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:write permission"
+// @Failure 404 {object} model.EmptyBody "Tag not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /resources/{type}/{id}/tags [post]
+func (handler *TagHandler) AssignTags(c *gin.Context) {
+	var req model.AssignTagsRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
+	if len(req.TagIds) == 0 {
+		setErrorResponse("tagIds may not be empty", http.StatusBadRequest, c)
+		return
+	}
+
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	resourceType := c.Params.ByName(ResourceTypeParam)
+	resourceId := c.Params.ByName(ResourceIdParam)
+	logger.Info.Printf("Received request to assign tags %v to resource \"%v/%v\" for organisationId \"%v\"", req.TagIds, resourceType, resourceId, organisationId)
+
+	for _, id := range req.TagIds {
+		if !bson.IsObjectIdHex(id) {
+			setErrorResponse("invalid tag id: "+id, http.StatusBadRequest, c)
+			return
+		}
+	}
+
+	// all the tags must belong to the organisation and be visible to the account before anything is assigned
+	for _, id := range req.TagIds {
+		if _, ok := handler.findOrganisationTag(c, bson.ObjectIdHex(id), organisationId, ReadAccess); !ok {
+			return
+		}
+	}
+
+	for _, id := range req.TagIds {
+		query := assignmentQuery(organisationId, resourceType, resourceId)
+		query[AssignmentTagId] = bson.ObjectIdHex(id)
+		err := handler.tenant(c).Upsert(DatabaseName, AssignmentCollection, query, bson.M{"$setOnInsert": bson.M{CreatedAt: time.Now()}})
+		if err != nil {
+			setRepositoryError(err, "Failed to assign tags", c)
+			return
+		}
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get the tags of a resource
+// @ID get-resource-tags
+// @Accept  json
+// @Produce  json
+// @Param type path string true "Resource type"
+// @Param id path string true "Resource ID"
+// @Param Accept-Language header string false "Preferred languages of the tag names"
+// @Success 200 {object} model.GetAllTagResponse "ok"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /resources/{type}/{id}/tags [get]
+func (handler *TagHandler) GetResourceTags(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	resourceType := c.Params.ByName(ResourceTypeParam)
+	resourceId := c.Params.ByName(ResourceIdParam)
+	logger.Info.Printf("Received request to retrieve tags of resource \"%v/%v\" for organisationId \"%v\"", resourceType, resourceId, organisationId)
+
+	assignments, err := handler.tenant(c).FindAssignments(DatabaseName, AssignmentCollection, assignmentQuery(organisationId, resourceType, resourceId))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+
+	query := readable(c, live(bson.M{"_id": bson.M{"$in": model.AssignedTagIds(assignments)}, OrganisationId: organisationId}))
+	results, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, query)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.Convert(localise(c, results)))
+}
+
+// @Summary Remove a tag from a resource
+// @ID unassign-tag
+// @Accept  json
+// @Produce  json
+// @Param type path string true "Resource type"
+// @Param id path string true "Resource ID"
+// @Param tagId path string true "Tag ID"
+// @Success 204 "Tag removed"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:write permission"
+// @Failure 404 {object} model.EmptyBody "Tag not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /resources/{type}/{id}/tags/{tagId} [delete]
+func (handler *TagHandler) UnassignTag(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	resourceType := c.Params.ByName(ResourceTypeParam)
+	resourceId := c.Params.ByName(ResourceIdParam)
+	id := c.Params.ByName(TagIdParam)
+	logger.Info.Printf("Received request to remove tag \"%v\" from resource \"%v/%v\" for organisationId \"%v\"", id, resourceType, resourceId, organisationId)
+
+	if !bson.IsObjectIdHex(id) {
+		setErrorResponse("invalid tag id: "+id, http.StatusBadRequest, c)
+		return
+	}
+
+	// the tag must belong to the organisation and be visible to the account, as when it is assigned
+	if _, ok := handler.findOrganisationTag(c, bson.ObjectIdHex(id), organisationId, ReadAccess); !ok {
+		return
+	}
+
+	query := assignmentQuery(organisationId, resourceType, resourceId)
+	query[AssignmentTagId] = bson.ObjectIdHex(id)
+	if err := handler.tenant(c).RemoveAll(DatabaseName, AssignmentCollection, query); err != nil {
+		setRepositoryError(err, "Failed to remove tag", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Find resources by tags
+// @ID find-resources
+// @Description Returns the resources whose tags match the boolean expression, e.g. (urgent AND finance) AND NOT archived.
+// @Description Terms are tag names or tag ids, names containing spaces or operators must be double quoted. The resources
+// @Description may also be filtered by the key of their key/value tags, in which case the expression is optional.
+// @Accept  json
+// @Produce  json
+// @Param tags query string false "Tag expression, required unless a key is given"
+// @Param key query string false "Key of one of the key/value tags of the resources"
+// @Param value query string false "Value of the key/value tag, along with the key"
+// @Param type query string false "Resource type"
+// @Param limit query int false "Maximum number of resources returned"
+// @Param cursor query string false "Cursor returned with the previous page"
+// @Success 200 {object} model.FindResourcesResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /resources [get]
+func (handler *TagHandler) FindResources(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to find resources tagged \"%v\" for organisationId \"%v\"", c.Query(TagsQueryParam), organisationId)
+
+	// the expression may be left out when filtering by key
+	keyed := c.Query(KeyParam) != ""
+	var expr expression.Node
+	if c.Query(TagsQueryParam) != "" || !keyed {
+		var err error
+		if expr, err = expression.Parse(c.Query(TagsQueryParam)); err != nil {
+			setErrorResponse(err.Error(), http.StatusBadRequest, c)
+			return
+		}
+	}
+	keyQuery := readable(c, live(bson.M{OrganisationId: organisationId}))
+	if !keyFilter(c, keyQuery) {
+		return
+	}
+
+	limit, err := queryLimit(c)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	query := bson.M{OrganisationId: organisationId}
+	if resourceType := c.Query(ResourceTypeParam); resourceType != "" {
+		query[ResourceType] = resourceType
+	}
+
+	filters := make([]bson.M, 0)
+	if expr != nil {
+		ids, err := handler.resolveTags(c, organisationId, expr.Terms())
+		if err != nil {
+			setRepositoryError(err, "Failed to retrieve data from the database", c)
+			return
+		}
+		filters = append(filters, expr.Bson(repository.ResourceTagsField, ids))
+	}
+	if keyed {
+		// any of the tags with the key, or with the key and value
+		tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, keyQuery)
+		if err != nil {
+			setRepositoryError(err, "Failed to retrieve data from the database", c)
+			return
+		}
+		ids := make([]bson.ObjectId, 0)
+		for _, tag := range tags {
+			ids = append(ids, tag.Id)
+		}
+		filters = append(filters, bson.M{repository.ResourceTagsField: bson.M{"$in": ids}})
+	}
+	tagsQuery := filters[0]
+	if len(filters) > 1 {
+		tagsQuery = bson.M{"$and": filters}
+	}
+
+	// resume after the last resource of the previous page
+	if cursor := c.Query(CursorParam); cursor != "" {
+		after, err := decodeResourceCursor(cursor)
+		if err != nil {
+			setErrorResponse("invalid cursor", http.StatusBadRequest, c)
+			return
+		}
+		tagsQuery = bson.M{"$and": []bson.M{tagsQuery, {"_id": bson.M{"$gt": bson.D{{Name: ResourceType, Value: after.ResourceType}, {Name: ResourceId, Value: after.ResourceId}}}}}}
+	}
+
+	// fetch one extra resource to know whether there is a next page
+	resources, err := handler.tenant(c).FindResources(DatabaseName, AssignmentCollection, query, tagsQuery, limit+1)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+
+	response := model.FindResourcesResponse{Resources: resources}
+	if len(resources) > limit {
+		response.Resources = resources[:limit]
+		response.NextCursor = encodeResourceCursor(resources[limit-1])
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Resolves the terms of an expression to tag ids. Terms which are valid object ids are taken as tag ids, the others
+// are looked up by name, compared as NameKey does. Both only resolve to the tags of the organisation visible to the
+// account of the request, the other terms matching no tag.
+func (handler *TagHandler) resolveTags(c *gin.Context, organisationId string, terms []string) (map[string][]bson.ObjectId, error) {
+	ids := make(map[string][]bson.ObjectId)
+	// the terms naming a tag are keyed by normalised name, so that "urgent" finds the tag "Urgent"
+	names := make(map[string][]string)
+	keys := make([]string, 0)
+	oids := make(map[bson.ObjectId][]string)
+	idList := make([]bson.ObjectId, 0)
+	for _, term := range terms {
+		if bson.IsObjectIdHex(term) {
+			oid := bson.ObjectIdHex(term)
+			if _, ok := oids[oid]; !ok {
+				idList = append(idList, oid)
+			}
+			oids[oid] = append(oids[oid], term)
+			continue
+		}
+		key := model.NameKey(term)
+		if _, ok := names[key]; !ok {
+			keys = append(keys, key)
+		}
+		names[key] = append(names[key], term)
+	}
+	if len(keys) == 0 && len(idList) == 0 {
+		return ids, nil
+	}
+
+	query := bson.M{OrganisationId: organisationId, "$or": []bson.M{{"_id": bson.M{"$in": idList}}, {NormalisedName: bson.M{"$in": keys}}}}
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, readable(c, live(query)))
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		for _, term := range oids[tag.Id] {
+			ids[term] = []bson.ObjectId{tag.Id}
+		}
+		for _, term := range names[tag.NormalisedName] {
+			ids[term] = append(ids[term], tag.Id)
+		}
+	}
+	return ids, nil
+}
+
+// Builds the query matching the assignments of the given resource.
+func assignmentQuery(organisationId string, resourceType string, resourceId string) bson.M {
+	return bson.M{OrganisationId: organisationId, ResourceType: resourceType, ResourceId: resourceId}
+}
+
+// Encodes the resource into an opaque cursor.
+func encodeResourceCursor(resource model.ResourceRef) string {
+	content, _ := json.Marshal(resource)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// Decodes a cursor created by encodeResourceCursor.
+func decodeResourceCursor(cursor string) (model.ResourceRef, error) {
+	var resource model.ResourceRef
+	content, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return resource, err
+	}
+	err = json.Unmarshal(content, &resource)
+	return resource, err
+}
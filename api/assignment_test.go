@@ -0,0 +1,262 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"testing"
+)
+
+// Assigns two tags to a resource and queries them back.
+func TestAssignTagsSuccess(t *testing.T) {
+
+	t.Logf("Given I create two tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		id1 := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Urgent"), Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		id2 := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Finance"), Colour: "Blue"}, router, t, test.Token2, test.OrgID1)
+		resource := "/resources/document/" + bson.NewObjectId().Hex() + "/tags"
+
+		t.Logf("\tWhen Sending Assign tags request to endpoint:  \"%s\"", resource)
+		{
+			w := assignTags(router, resource, []string{id1, id2}, test.OrgID1)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusNoContent)
+
+			// assigning the same tag twice is a no-op
+			assignTags(router, resource, []string{id1}, test.OrgID1)
+
+			checkResourceTags(router, resource, test.OrgID1, []string{id1, id2}, t)
+		}
+	}
+}
+
+// Removes a tag from a resource.
+func TestUnassignTagSuccess(t *testing.T) {
+
+	t.Logf("Given I assign two tags to a resource")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		id1 := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Urgent"), Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		id2 := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Finance"), Colour: "Blue"}, router, t, test.Token2, test.OrgID1)
+		resource := "/resources/document/" + bson.NewObjectId().Hex() + "/tags"
+		assignTags(router, resource, []string{id1, id2}, test.OrgID1)
+
+		t.Logf("\tWhen Sending Remove tag request to endpoint:  \"%s\"", resource+"/"+id1)
+		{
+			req, err := test.HttpRequest(nil, resource+"/"+id1, http.MethodDelete, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusNoContent)
+
+			checkResourceTags(router, resource, test.OrgID1, []string{id2}, t)
+		}
+	}
+}
+
+// Deleting a tag hides it from every resource it was applied to, the assignments are kept until the tag is purged.
+func TestDeleteTagHidesAssignments(t *testing.T) {
+
+	t.Logf("Given I assign a tag to a resource")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Urgent"), Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		resource := "/resources/document/" + bson.NewObjectId().Hex() + "/tags"
+		assignTags(router, resource, []string{id}, test.OrgID1)
+
+		t.Logf("\tWhen Sending Delete tag request to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			req, err := test.HttpRequest(nil, "/tags/"+id, http.MethodDelete, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusNoContent)
+
+			checkResourceTags(router, resource, test.OrgID1, []string{}, t)
+
+			assignments, _ := Repository.FindAssignments(DatabaseName, AssignmentCollection, bson.M{AssignmentTagId: bson.ObjectIdHex(id)})
+			if len(assignments) == 1 {
+				t.Logf("\t\tThe tag assignments should have been kept. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag assignments should have been kept. %v %v", test.BallotX, assignments)
+			}
+		}
+	}
+}
+
+// Attempt to assign a tag not belonging to the organisation result in not found error.
+func TestAssignTagNotBelongingToTheUser(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Urgent"), Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		resource := "/resources/document/" + bson.NewObjectId().Hex() + "/tags"
+
+		t.Logf("\tWhen Sending Assign tags request to endpoint:  \"%s\" with a different organisationId", resource)
+		{
+			w := assignTags(router, resource, []string{id}, test.OrgID2)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusNotFound)
+		}
+	}
+}
+
+// Attempt to assign a malformed tag id results in bad request.
+func TestAssignTagsWithInvalidTagId(t *testing.T) {
+
+	t.Logf("Given the tag service is up and running")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		resource := "/resources/document/" + bson.NewObjectId().Hex() + "/tags"
+
+		t.Logf("\tWhen Sending Assign tags request with an invalid tag id to endpoint:  \"%s\"", resource)
+		{
+			w := assignTags(router, resource, []string{"not-an-id"}, test.OrgID1)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			expectedResponse := model.ErrorResponse{Code: http.StatusBadRequest, Message: "invalid tag id: not-an-id"}
+
+			// check body response
+			test.CheckResponseMessage(response, expectedResponse, t, w)
+		}
+	}
+}
+
+// helper function
+func assignTags(router *gin.Engine, resource string, ids []string, orgId string) *httptest.ResponseRecorder {
+	req, _ := test.HttpRequest(model.AssignTagsRequest{TagIds: ids}, resource, http.MethodPost, test.Token2, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// helper function
+func checkResourceTags(router *gin.Engine, resource string, orgId string, expectedIds []string, t *testing.T) {
+	req, _ := test.HttpRequest(nil, resource, http.MethodGet, test.Token2, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response model.GetAllTagResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	if len(response.Tags) != len(expectedIds) {
+		t.Errorf("\t\tThe resource should have \"%d\" tags. %v %v", len(expectedIds), test.BallotX, response.Tags)
+		return
+	}
+	for _, id := range expectedIds {
+		if getCreatedTags(id, response.Tags).Id == id {
+			t.Logf("\t\tTag [\"%s\"] should have been assigned to the resource. %v", id, test.CheckMark)
+		} else {
+			t.Errorf("\t\tTag [\"%s\"] should have been assigned to the resource. %v", id, test.BallotX)
+		}
+	}
+}
+
+// Finds the resources matching a tag expression, one page at a time.
+func TestFindResourcesSuccess(t *testing.T) {
+
+	t.Logf("Given I tag three documents")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		urgent := test.CreateTag(model.CreateTagRequest{Name: "Urgent", Colour: "Red"}, router, t, test.Token2, test.OrgID2)
+		finance := test.CreateTag(model.CreateTagRequest{Name: "Finance", Colour: "Blue"}, router, t, test.Token2, test.OrgID2)
+		archived := test.CreateTag(model.CreateTagRequest{Name: "Archived", Colour: "Grey"}, router, t, test.Token2, test.OrgID2)
+		resourceType := "invoice-" + bson.NewObjectId().Hex()
+		assignTags(router, "/resources/"+resourceType+"/1/tags", []string{urgent, finance}, test.OrgID2)
+		assignTags(router, "/resources/"+resourceType+"/2/tags", []string{urgent, finance, archived}, test.OrgID2)
+		assignTags(router, "/resources/"+resourceType+"/3/tags", []string{urgent, finance}, test.OrgID2)
+		assignTags(router, "/resources/"+resourceType+"/4/tags", []string{urgent}, test.OrgID2)
+
+		t.Logf("\tWhen Sending Find resources request to endpoint:  \"%s\"", "\\resources")
+		{
+			url := "/resources?limit=1&type=" + resourceType + "&tags=" + neturl.QueryEscape("(Urgent AND "+finance+") AND NOT Archived")
+			first := findResources(router, url, t)
+			if len(first.Resources) == 1 && first.Resources[0].ResourceId == "1" && first.NextCursor != "" {
+				t.Logf("\t\tThe first page should contain the first document. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe first page should contain the first document. %v %v", test.BallotX, first)
+			}
+
+			second := findResources(router, url+"&cursor="+first.NextCursor, t)
+			if len(second.Resources) == 1 && second.Resources[0].ResourceId == "3" && second.NextCursor == "" {
+				t.Logf("\t\tThe last page should contain the third document. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe last page should contain the third document. %v %v", test.BallotX, second)
+			}
+		}
+
+		t.Logf("\tWhen naming the tags in another case")
+		{
+			url := "/resources?type=" + resourceType + "&tags=" + neturl.QueryEscape("urgent AND NOT ARCHIVED")
+			response := findResources(router, url, t)
+			if len(response.Resources) == 3 {
+				t.Logf("\t\tThe tags should be found whatever the case of their names. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tags should be found whatever the case of their names. %v %v", test.BallotX, response)
+			}
+		}
+	}
+}
+
+// Attempt to find resources with an invalid expression results in bad request.
+func TestFindResourcesWithInvalidExpression(t *testing.T) {
+
+	t.Logf("Given the tag service is up and running")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		t.Logf("\tWhen Sending Find resources request with an invalid expression to endpoint:  \"%s\"", "\\resources")
+		{
+			req, err := test.HttpRequest(nil, "/resources?tags="+neturl.QueryEscape("Urgent AND"), http.MethodGet, test.Token2, test.OrgID2)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+	}
+}
+
+// helper function
+func findResources(router *gin.Engine, url string, t *testing.T) model.FindResourcesResponse {
+	req, _ := test.HttpRequest(nil, url, http.MethodGet, test.Token2, test.OrgID2)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusOK)
+
+	var response model.FindResourcesResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	return response
+}
@@ -0,0 +1,686 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+	"time"
+)
+
+const MaxBatchSize = 500
+
+// @Summary Create tags in batch
+// @ID batch-create-tags
+// @Description Creates up to 500 tags. Each tag is validated as by the create endpoint and gets its own status, the
+// @Description valid tags are created even when others are rejected. The parent of a tag must already exist, the tags
+// @Description of a batch getting their ids once created they cannot be the parents of one another.
+// @Accept  json
+// @Produce  json
+// @Param tags body model.BatchCreateRequest true "New tags"
+// @Success 200 {object} model.BatchResponse "Status of each tag, 201 when created"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:write permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/batchCreate [post]
+func (handler *TagHandler) BatchCreateTags(c *gin.Context) {
+	var req model.BatchCreateRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+	if !validBatchSize(c, len(req.Tags)) {
+		return
+	}
+
+	accountId := c.Request.Header.Get(AccountIDField)
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to create %d tags for accountId \"%v\" and organisationId \"%v\"", len(req.Tags), accountId, organisationId)
+
+	palette, ok := handler.findPalette(c, organisationId)
+	if !ok {
+		return
+	}
+	tags, ok := handler.organisationTags(c, organisationId)
+	if !ok {
+		return
+	}
+	names := nameIndex(tags)
+	keys, err := handler.organisationKeys(c, organisationId)
+	if err != nil {
+		return
+	}
+	schema, ok := handler.findSchema(c, organisationId)
+	if !ok {
+		return
+	}
+
+	results := make([]model.BatchResult, len(req.Tags))
+	docs := make([]*model.PendingTagDAO, 0)
+	created := make([]model.HistoryDAO, 0)
+	positions := make([]int, 0)
+	for i, item := range req.Tags {
+		results[i] = model.BatchResult{Index: i}
+		if item.Key == "" && tagNameEmptyString(item.Name) {
+			results[i] = batchFailure(i, http.StatusBadRequest, "tag name may not be empty")
+			continue
+		}
+		name, value, err := keyedTag(keys, item.Key, item.Value, item.Name)
+		if err != nil {
+			results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+			continue
+		}
+
+		tag := model.TagDAO{Id: bson.NewObjectId(), Name: model.NormaliseName(name), NormalisedName: model.NameKey(name), AccountId: accountId, OrganisationId: organisationId, Version: 1, UpdatedAt: time.Now(), Key: item.Key, Value: value}
+		if existing, ok := names[tag.NormalisedName]; ok {
+			results[i] = batchFailure(i, http.StatusConflict, NameConflictMessage)
+			results[i].ExistingId = visibleTagId(c, tags, existing)
+			continue
+		}
+
+		colour, err := tagColour(palette, item.Colour, name)
+		if err != nil {
+			results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+			continue
+		}
+		tag.Colour = colour
+
+		tag.Attributes = model.MergeAttributes(nil, item.Attributes)
+		if errs := schema.Validate(tag.Attributes); len(errs) > 0 {
+			results[i] = attributesFailure(i, errs)
+			continue
+		}
+		if tag.Translations, err = model.NormaliseTranslations(item.Translations); err != nil {
+			results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+			continue
+		}
+		if tag.Visibility, err = tagVisibility(item.Visibility); err != nil {
+			results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+			continue
+		}
+
+		// the parent tag must exist within the organisation, the tags of the batch being unknown to the client until
+		// they are created
+		if item.ParentId != "" {
+			parentId, err := visibleParent(c, tags, tag.Id, item.ParentId)
+			if err != nil {
+				results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+				continue
+			}
+			tag.ParentId = parentId
+		}
+
+		// the following items may not reuse the name
+		names[tag.NormalisedName] = tag.Id
+		results[i].Id = tag.Id.Hex()
+		record := historyOf(c, model.OperationCreate, nil, &tag)
+		docs = append(docs, withCreationEvent(tag, record))
+		created = append(created, record)
+		positions = append(positions, i)
+	}
+
+	if len(docs) > 0 {
+		failed, err := handler.insertTags(c, docs)
+		if err != nil {
+			setRepositoryError(err, "Insert failed", c)
+			return
+		}
+		records := make([]model.HistoryDAO, 0)
+		for p, i := range positions {
+			results[i].Status = http.StatusCreated
+			if err, ok := failed[p]; ok {
+				results[i] = handler.batchWriteFailure(c, i, err, organisationId, created[p].After.NormalisedName, "Insert failed")
+				continue
+			}
+			records = append(records, created[p])
+		}
+		handler.recordChanges(c, records...)
+	}
+	logger.Info.Printf("%d tags successfully created", len(docs))
+	c.JSON(http.StatusOK, model.BatchResponse{Results: results})
+}
+
+// @Summary Update tags in batch
+// @ID batch-update-tags
+// @Description Updates up to 500 tags, only the fields present are updated. Each tag is validated as by the patch
+// @Description endpoint and gets its own status, the valid tags are updated even when others are rejected.
+// @Description A tag given with the version of its ETag is only updated while it still has it, with the status 412
+// @Description otherwise.
+// @Accept  json
+// @Produce  json
+// @Param tags body model.BatchUpdateRequest true "Fields to update"
+// @Success 200 {object} model.BatchResponse "Status of each tag, 200 when updated"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:write permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/batchUpdate [post]
+func (handler *TagHandler) BatchUpdateTags(c *gin.Context) {
+	var req model.BatchUpdateRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+	if !validBatchSize(c, len(req.Tags)) {
+		return
+	}
+
+	accountId := c.Request.Header.Get(AccountIDField)
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to update %d tags for accountId \"%v\" and organisationId \"%v\"", len(req.Tags), accountId, organisationId)
+
+	ids := make([]string, len(req.Tags))
+	for i, item := range req.Tags {
+		ids[i] = item.Id
+	}
+	results, found, ok := handler.batchTags(c, ids, organisationId, accountId)
+	if !ok {
+		return
+	}
+	versions := make(map[int]int)
+	for i, item := range req.Tags {
+		if item.Version != nil {
+			versions[i] = *item.Version
+		}
+	}
+	checkBatchVersions(results, found, versions)
+
+	palette, ok := handler.findPalette(c, organisationId)
+	if !ok {
+		return
+	}
+	tags, ok := handler.organisationTags(c, organisationId)
+	if !ok {
+		return
+	}
+	names := nameIndex(tags)
+	definitions, err := handler.organisationKeys(c, organisationId)
+	if err != nil {
+		return
+	}
+	schema, ok := handler.findSchema(c, organisationId)
+	if !ok {
+		return
+	}
+
+	updated := make([]model.TagDAO, 0)
+	updates := make([]interface{}, 0)
+	changed := make([]model.HistoryDAO, 0)
+	keys := make([]string, 0)
+	positions := make([]int, 0)
+	for i, item := range req.Tags {
+		tag, ok := found[i]
+		if !ok {
+			continue
+		}
+		if item.Name == nil && item.Colour == nil && item.ParentId == nil && item.Value == nil && item.Attributes == nil && item.Translations == nil {
+			results[i] = batchFailure(i, http.StatusBadRequest, "no fields to update")
+			continue
+		}
+
+		fields := bson.M{}
+		if item.Name != nil || item.Value != nil {
+			if item.Name != nil && tagNameEmptyString(*item.Name) {
+				results[i] = batchFailure(i, http.StatusBadRequest, "tag name may not be empty")
+				continue
+			}
+			if err := renameTag(definitions, &tag, item.Name, item.Value, fields); err != nil {
+				results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+				continue
+			}
+			if existing, ok := names[tag.NormalisedName]; ok && existing != tag.Id {
+				results[i] = batchFailure(i, http.StatusConflict, NameConflictMessage)
+				results[i].ExistingId = visibleTagId(c, tags, existing)
+				continue
+			}
+		}
+		if item.Colour != nil {
+			colour, err := tagColour(palette, *item.Colour, tag.Name)
+			if err != nil {
+				results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+				continue
+			}
+			tag.Colour = colour
+			fields[TagColour] = colour
+		}
+		if item.ParentId != nil {
+			// the tag may not be moved under one of its own descendants
+			var parentId bson.ObjectId
+			if *item.ParentId != "" {
+				var err error
+				if parentId, err = visibleParent(c, tags, tag.Id, *item.ParentId); err != nil {
+					results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+					continue
+				}
+			}
+			tag.ParentId = parentId
+			fields[ParentId] = parentId
+		}
+		if item.Attributes != nil {
+			tag.Attributes = model.MergeAttributes(tag.Attributes, item.Attributes)
+			fields[TagAttributes] = tag.Attributes
+		}
+		if errs := schema.Validate(tag.Attributes); len(errs) > 0 {
+			results[i] = attributesFailure(i, errs)
+			continue
+		}
+		if item.Translations != nil {
+			translations, err := model.MergeTranslations(tag.Translations, item.Translations)
+			if err != nil {
+				results[i] = batchFailure(i, http.StatusBadRequest, err.Error())
+				continue
+			}
+			tag.Translations = translations
+			fields[TagTranslations] = translations
+		}
+
+		// the following items may not reuse the name
+		names[tag.NormalisedName] = tag.Id
+		tag.Version++
+		before := found[i]
+		record := historyOf(c, model.OperationUpdate, &before, &tag)
+		updated = append(updated, tag)
+		updates = append(updates, withEvent(tagUpdate(fields), record))
+		changed = append(changed, record)
+		keys = append(keys, tag.NormalisedName)
+		positions = append(positions, i)
+	}
+
+	if len(updates) > 0 {
+		// the tags given with a version are only updated while they still have it, one at a time as with If-Match,
+		// the others are updated together
+		bulkIds := make([]bson.ObjectId, 0)
+		bulkUpdates := make([]interface{}, 0)
+		bulkPositions := make([]int, 0)
+		for p, i := range positions {
+			if _, ok := versions[i]; !ok {
+				bulkIds = append(bulkIds, updated[p].Id)
+				bulkUpdates = append(bulkUpdates, updates[p])
+				bulkPositions = append(bulkPositions, p)
+			}
+		}
+		failed := make(map[int]error)
+		if len(bulkIds) > 0 {
+			bulkFailed, err := handler.writeTags(c, bson.M{}, bulkIds, bulkUpdates)
+			if err != nil {
+				setRepositoryError(err, "Update failed", c)
+				return
+			}
+			for b, err := range bulkFailed {
+				failed[bulkPositions[b]] = err
+			}
+		}
+		for p, i := range positions {
+			if _, ok := versions[i]; ok {
+				if err := handler.writeVersion(c, found[i], updates[p].(bson.M)); err != nil {
+					failed[p] = err
+				}
+			}
+		}
+
+		records := make([]model.HistoryDAO, 0)
+		for p, i := range positions {
+			results[i].Status = http.StatusOK
+			if err, ok := failed[p]; ok {
+				if _, versioned := versions[i]; versioned && staleVersion(err) {
+					results[i] = batchFailure(i, http.StatusPreconditionFailed, PreconditionFailMessage)
+				} else {
+					results[i] = handler.batchWriteFailure(c, i, err, organisationId, keys[p], "Update failed")
+				}
+				continue
+			}
+			records = append(records, changed[p])
+		}
+		handler.recordChanges(c, records...)
+	}
+	for i := range results {
+		results[i].Id = ids[i]
+	}
+	logger.Info.Printf("%d tags successfully updated", len(updates))
+	c.JSON(http.StatusOK, model.BatchResponse{Results: results})
+}
+
+// @Summary Delete tags in batch
+// @ID batch-delete-tags
+// @Description Deletes up to 500 tags. Each tag gets its own status, the tags which can be deleted are deleted even
+// @Description when others are rejected. Child tags also deleted in the batch do not prevent the deletion of their parent.
+// @Description The deleted tags are moved to the trash.
+// @Description A tag given with the version of its ETag is only deleted while it still has it, with the status 412
+// @Description otherwise.
+// @Accept  json
+// @Produce  json
+// @Param ids body model.BatchDeleteRequest true "Tag IDs"
+// @Param children query string false "What happens to the child tags: reject (default), cascade or reparent"
+// @Success 200 {object} model.BatchResponse "Status of each tag, 204 when deleted"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:write permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/batchDelete [post]
+func (handler *TagHandler) BatchDeleteTags(c *gin.Context) {
+	var req model.BatchDeleteRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+	if !validBatchSize(c, len(req.Ids)) {
+		return
+	}
+
+	policy := c.DefaultQuery(ChildrenParam, RejectChildren)
+	if !validChildrenPolicy(policy) {
+		setErrorResponse("children must be one of reject, cascade or reparent", http.StatusBadRequest, c)
+		return
+	}
+
+	accountId := c.Request.Header.Get(AccountIDField)
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to delete %d tags for accountId \"%v\" and organisationId \"%v\"", len(req.Ids), accountId, organisationId)
+
+	results, found, ok := handler.batchTags(c, req.Ids, organisationId, accountId)
+	if !ok {
+		return
+	}
+	versions := make(map[int]int)
+	for i, id := range req.Ids {
+		if version, ok := req.Versions[id]; ok {
+			versions[i] = version
+		}
+	}
+	checkBatchVersions(results, found, versions)
+	tags, ok := handler.organisationTags(c, organisationId)
+	if !ok {
+		return
+	}
+
+	byId := make(map[bson.ObjectId]model.TagDAO)
+	for _, tag := range tags {
+		byId[tag.Id] = tag
+	}
+	deleted := make(map[bson.ObjectId]bool)
+	for _, tag := range found {
+		deleted[tag.Id] = true
+	}
+
+	removed := make([]bson.ObjectId, 0)
+	positions := make([]int, 0)
+	descendants := make(map[int][]bson.ObjectId)
+	reparented := make([]int, 0)
+	records := make([]model.HistoryDAO, 0)
+	for i := range req.Ids {
+		tag, ok := found[i]
+		if !ok {
+			continue
+		}
+
+		// apply the policy to the child tags which are not deleted in the same batch
+		children := make([]bson.ObjectId, 0)
+		for _, child := range tags {
+			if child.ParentId == tag.Id && !deleted[child.Id] {
+				children = append(children, child.Id)
+			}
+		}
+		if len(children) > 0 {
+			switch policy {
+			case CascadeChildren:
+				ids := model.DescendantIds(tags, tag.Id)
+				if !writableIds(byId, ids, organisationId, accountId) {
+					results[i] = batchFailure(i, http.StatusForbidden, ReadOnlyChildrenMessage)
+					continue
+				}
+				descendants[i] = ids
+
+			case ReparentChildren:
+				if !writableIds(byId, children, organisationId, accountId) {
+					results[i] = batchFailure(i, http.StatusForbidden, ReadOnlyChildrenMessage)
+					continue
+				}
+				// the children are only moved once the tag is in the trash, they stay put when it cannot be deleted
+				reparented = append(reparented, i)
+
+			default:
+				results[i] = batchFailure(i, http.StatusConflict, "The tag has child tags")
+				continue
+			}
+		}
+		removed = append(removed, tag.Id)
+		positions = append(positions, i)
+	}
+
+	if len(removed) > 0 {
+		deletedAt := time.Now()
+
+		// the tags given with a version are only moved to the trash while they still have it, one at a time as with
+		// If-Match, and their descendants are left alone when they no longer have it
+		trashing := make(map[bson.ObjectId]bool)
+		stale := make(map[bson.ObjectId]bool)
+		inTrash := make(map[bson.ObjectId]bool)
+		for _, i := range positions {
+			if _, ok := versions[i]; !ok {
+				continue
+			}
+			tag := found[i]
+			record := historyOf(c, model.OperationDelete, &tag, trashed(tag, deletedAt))
+			if err := handler.writeVersion(c, tag, withEvent(trashUpdate(deletedAt), record)); err != nil {
+				if staleVersion(err) {
+					results[i] = batchFailure(i, http.StatusPreconditionFailed, PreconditionFailMessage)
+				} else {
+					results[i] = repositoryFailure(i, err, "Delete failed")
+				}
+				stale[tag.Id] = true
+				continue
+			}
+			trashing[tag.Id] = true
+			inTrash[tag.Id] = true
+			results[i].Status = http.StatusNoContent
+			records = append(records, record)
+		}
+
+		// the other tags and the descendants of the cascaded ones go to the trash together, a tag being listed once
+		// even when it is also a descendant of another tag of the batch
+		trashedIds := make([]bson.ObjectId, 0)
+		trashedPositions := make([]int, 0)
+		for _, i := range positions {
+			if _, ok := versions[i]; !ok {
+				trashing[found[i].Id] = true
+				trashedIds = append(trashedIds, found[i].Id)
+				trashedPositions = append(trashedPositions, i)
+			}
+		}
+		for _, i := range positions {
+			if stale[found[i].Id] {
+				continue
+			}
+			for _, id := range descendants[i] {
+				if !trashing[id] && !stale[id] {
+					trashing[id] = true
+					trashedIds = append(trashedIds, id)
+					trashedPositions = append(trashedPositions, -1)
+				}
+			}
+		}
+		if len(trashedIds) > 0 {
+			changes := make([]model.HistoryDAO, len(trashedIds))
+			updates := make([]interface{}, len(trashedIds))
+			for p, id := range trashedIds {
+				before := byId[id]
+				changes[p] = historyOf(c, model.OperationDelete, &before, trashed(before, deletedAt))
+				updates[p] = withEvent(trashUpdate(deletedAt), changes[p])
+			}
+			failed, err := handler.writeTags(c, bson.M{}, trashedIds, updates)
+			if err != nil {
+				// the changes already made are recorded all the same
+				records = append(records, handler.reparentBatchChildren(c, tags, inTrash, found, reparented)...)
+				handler.recordChanges(c, records...)
+				setRepositoryError(err, "Delete failed", c)
+				return
+			}
+			for p, id := range trashedIds {
+				if i := trashedPositions[p]; i >= 0 {
+					results[i].Status = http.StatusNoContent
+					if err, ok := failed[p]; ok {
+						results[i] = repositoryFailure(i, err, "Delete failed")
+					}
+				}
+				if _, ok := failed[p]; !ok {
+					inTrash[id] = true
+					records = append(records, changes[p])
+				}
+			}
+		}
+		records = append(records, handler.reparentBatchChildren(c, tags, inTrash, found, reparented)...)
+	}
+	handler.recordChanges(c, records...)
+	for i := range results {
+		results[i].Id = req.Ids[i]
+	}
+	logger.Info.Printf("%d tags successfully deleted", len(removed))
+	c.JSON(http.StatusOK, model.BatchResponse{Results: results})
+}
+
+// Moves the children of the tags of a batch deleted with the reparent policy up to their closest ancestor which is not in
+// the trash, or to the root of the tree. The tags which could not be moved to the trash keep their children, and the
+// children which were moved to the trash are left alone. Returns the records of the change.
+func (handler *TagHandler) reparentBatchChildren(c *gin.Context, tags []model.TagDAO, inTrash map[bson.ObjectId]bool, found map[int]model.TagDAO, positions []int) []model.HistoryDAO {
+	byId := make(map[bson.ObjectId]model.TagDAO)
+	for _, tag := range tags {
+		byId[tag.Id] = tag
+	}
+	records := make([]model.HistoryDAO, 0)
+	for _, i := range positions {
+		tag := found[i]
+		if !inTrash[tag.Id] {
+			continue
+		}
+		parentId := tag.ParentId
+		for parentId != "" && inTrash[parentId] {
+			parentId = byId[parentId].ParentId
+		}
+		changes := make([]model.HistoryDAO, 0)
+		for _, child := range tags {
+			if child.ParentId == tag.Id && !inTrash[child.Id] {
+				before, after := child, child
+				after.ParentId = parentId
+				after.Version++
+				changes = append(changes, historyOf(c, model.OperationUpdate, &before, &after))
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		reparented, err := handler.writeChanges(c, live(bson.M{}), changes, func() bson.M { return tagUpdate(bson.M{ParentId: parentId}) })
+		if err != nil {
+			logger.Error.Printf("Failed to reparent the children of tag \"%v\": %v", tag.Id.Hex(), err.Error())
+			continue
+		}
+		records = append(records, reparented...)
+	}
+	return records
+}
+
+// Queries the tags of a batch and checks the account may change them. Returns the result of each item, holding
+// the failure of the items which cannot be processed, and the tags of the others keyed by position. The error
+// response is written when the tags cannot be read.
+func (handler *TagHandler) batchTags(c *gin.Context, ids []string, organisationId string, accountId string) ([]model.BatchResult, map[int]model.TagDAO, bool) {
+	results := make([]model.BatchResult, len(ids))
+	oids := make([]bson.ObjectId, 0)
+	for i, id := range ids {
+		results[i] = model.BatchResult{Index: i}
+		if bson.IsObjectIdHex(id) {
+			oids = append(oids, bson.ObjectIdHex(id))
+		}
+	}
+
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, live(bson.M{"_id": bson.M{"$in": oids}}))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return nil, nil, false
+	}
+	byId := make(map[string]model.TagDAO)
+	for _, tag := range tags {
+		byId[tag.Id.Hex()] = tag
+	}
+
+	found := make(map[int]model.TagDAO)
+	seen := make(map[string]bool)
+	for i, id := range ids {
+		tag, ok := byId[id]
+		switch {
+		case !bson.IsObjectIdHex(id):
+			results[i] = batchFailure(i, http.StatusBadRequest, "invalid tag id: "+id)
+		case seen[id]:
+			results[i] = batchFailure(i, http.StatusBadRequest, "duplicate tag id: "+id)
+		case !ok:
+			results[i] = batchFailure(i, http.StatusNotFound, "tag not found")
+		default:
+			if status, message := denial(tag, organisationId, accountId, WriteAccess); status != 0 {
+				results[i] = batchFailure(i, status, message)
+			} else {
+				found[i] = tag
+			}
+		}
+		seen[id] = true
+	}
+	return results, found, true
+}
+
+// Queries all the tags of the organisation. The error response is written when the tags cannot be read.
+func (handler *TagHandler) organisationTags(c *gin.Context, organisationId string) ([]model.TagDAO, bool) {
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, live(bson.M{OrganisationId: organisationId}))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return nil, false
+	}
+	return tags, true
+}
+
+// Returns the result of an item whose write failed, a duplicate name being reported as for the single item endpoints.
+func (handler *TagHandler) batchWriteFailure(c *gin.Context, index int, err error, organisationId string, key string, message string) model.BatchResult {
+	if errors.Is(err, repository.ErrConflict) {
+		result := batchFailure(index, http.StatusConflict, NameConflictMessage)
+		result.ExistingId = handler.existingTagId(c, organisationId, key)
+		return result
+	}
+	return repositoryFailure(index, err, message)
+}
+
+// Returns the ids of the tags keyed by normalised name.
+func nameIndex(tags []model.TagDAO) map[string]bson.ObjectId {
+	names := make(map[string]bson.ObjectId)
+	for _, tag := range tags {
+		names[model.NameKey(tag.Name)] = tag.Id
+	}
+	return names
+}
+
+func batchFailure(index int, status int, message string) model.BatchResult {
+	return model.BatchResult{Index: index, Status: status, Message: message}
+}
+
+// Returns the result of an item failed by the repository, with the status of the single item endpoints.
+func repositoryFailure(index int, err error, message string) model.BatchResult {
+	logger.Error.Println(err.Error())
+	status, message := repositoryStatus(err, message)
+	return batchFailure(index, status, message)
+}
+
+// Checks the number of items of a batch. The error response is written when there are none or too many.
+func validBatchSize(c *gin.Context, size int) bool {
+	if size == 0 || size > MaxBatchSize {
+		setErrorResponse(fmt.Sprintf("a batch must have between 1 and %d items", MaxBatchSize), http.StatusBadRequest, c)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Creates three tags in one request, one of them being invalid.
+func TestBatchCreateTagsSuccess(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		existing := test.UniqueName("Dinner")
+		existingId := test.CreateTag(model.CreateTagRequest{Name: existing, Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Batch create request to endpoint:  \"%s\"", "\\tags\\batchCreate")
+		{
+			body := model.BatchCreateRequest{Tags: []model.CreateTagRequest{
+				{Name: test.UniqueName("Lunch"), Colour: "Blue"},
+				{Name: " ", Colour: "Blue"},
+				{Name: existing, Colour: "Green"},
+				{Name: test.UniqueName("Brunch")},
+			}}
+			response := batch(router, "/tags/batchCreate", body, t)
+
+			checkBatchStatuses(response, []int{http.StatusCreated, http.StatusBadRequest, http.StatusConflict, http.StatusCreated}, t)
+			if len(response.Results) == 4 && response.Results[2].ExistingId == existingId {
+				t.Logf("\t\tThe duplicate should reference the existing tag. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe duplicate should reference the existing tag:  \"%v\". %v", response, test.BallotX)
+			}
+
+			if len(response.Results) == 4 {
+				id := response.Results[0].Id
+				checkStoredTag(router, id, model.Tag{Id: id, Name: body.Tags[0].Name, Colour: "Blue", AccountId: test.AccountID2, OrganisationId: test.OrgID1, Visibility: model.VisibilityOrganisation}, t)
+			}
+		}
+	}
+}
+
+// Creates the parents in a first batch and their children in a second one, the tags of a batch not being parents of
+// one another.
+func TestBatchCreateTagsWithParents(t *testing.T) {
+
+	t.Logf("Given I create a tag in batch")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		parents := batch(router, "/tags/batchCreate", model.BatchCreateRequest{Tags: []model.CreateTagRequest{{Name: test.UniqueName("Travel")}}}, t)
+		checkBatchStatuses(parents, []int{http.StatusCreated}, t)
+
+		t.Logf("\tWhen Sending Batch create request for its children to endpoint:  \"%s\"", "\\tags\\batchCreate")
+		{
+			body := model.BatchCreateRequest{Tags: []model.CreateTagRequest{
+				{Name: test.UniqueName("Flights"), ParentId: parents.Results[0].Id},
+				{Name: test.UniqueName("Hotels"), ParentId: bson.NewObjectId().Hex()},
+			}}
+			response := batch(router, "/tags/batchCreate", body, t)
+
+			checkBatchStatuses(response, []int{http.StatusCreated, http.StatusBadRequest}, t)
+			if tag, err := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(response.Results[0].Id)); err == nil && tag.ParentId.Hex() == parents.Results[0].Id {
+				t.Logf("\t\tThe child should have been created under its parent. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe child should have been created under its parent. %v", test.BallotX)
+			}
+		}
+	}
+}
+
+// Updates two tags in one request, one of them belonging to another organisation.
+func TestBatchUpdateTagsSuccess(t *testing.T) {
+
+	t.Logf("Given I create tags in two organisations")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		name := test.UniqueName("Dinner")
+		id := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		other := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token2, test.OrgID2)
+
+		t.Logf("\tWhen Sending Batch update request to endpoint:  \"%s\"", "\\tags\\batchUpdate")
+		{
+			colour := "Green"
+			body := model.BatchUpdateRequest{Tags: []model.BatchUpdateItem{
+				{Id: id, PatchTagRequest: model.PatchTagRequest{Colour: &colour}},
+				{Id: other, PatchTagRequest: model.PatchTagRequest{Colour: &colour}},
+				{Id: bson.NewObjectId().Hex(), PatchTagRequest: model.PatchTagRequest{Colour: &colour}},
+				{Id: "not-an-id", PatchTagRequest: model.PatchTagRequest{Colour: &colour}},
+			}}
+			response := batch(router, "/tags/batchUpdate", body, t)
+
+			checkBatchStatuses(response, []int{http.StatusOK, http.StatusNotFound, http.StatusNotFound, http.StatusBadRequest}, t)
+			checkStoredTag(router, id, model.Tag{Id: id, Name: name, Colour: colour, AccountId: test.AccountID2, OrganisationId: test.OrgID1, Visibility: model.VisibilityOrganisation}, t)
+		}
+	}
+}
+
+// Updates and deletes tags in batch given the versions they were read with, one of them being stale.
+func TestBatchTagsWithVersions(t *testing.T) {
+
+	t.Logf("Given I create two tags and rename one of them")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		name := test.UniqueName("Dinner")
+		id := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		modified := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Lunch"), Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		colour := "Blue"
+		batch(router, "/tags/batchUpdate", model.BatchUpdateRequest{Tags: []model.BatchUpdateItem{{Id: modified, PatchTagRequest: model.PatchTagRequest{Colour: &colour}}}}, t)
+
+		t.Logf("\tWhen Sending Batch update request with the versions read to endpoint:  \"%s\"", "\\tags\\batchUpdate")
+		{
+			colour := "Green"
+			version := 1
+			body := model.BatchUpdateRequest{Tags: []model.BatchUpdateItem{
+				{Id: id, Version: &version, PatchTagRequest: model.PatchTagRequest{Colour: &colour}},
+				{Id: modified, Version: &version, PatchTagRequest: model.PatchTagRequest{Colour: &colour}},
+			}}
+			response := batch(router, "/tags/batchUpdate", body, t)
+
+			checkBatchStatuses(response, []int{http.StatusOK, http.StatusPreconditionFailed}, t)
+			checkStoredTag(router, id, model.Tag{Id: id, Name: name, Colour: colour, AccountId: test.AccountID2, OrganisationId: test.OrgID1, Visibility: model.VisibilityOrganisation}, t)
+		}
+
+		t.Logf("\tWhen Sending Batch delete request with the versions read to endpoint:  \"%s\"", "\\tags\\batchDelete")
+		{
+			body := model.BatchDeleteRequest{Ids: []string{id, modified}, Versions: map[string]int{id: 2, modified: 1}}
+			response := batch(router, "/tags/batchDelete", body, t)
+
+			checkBatchStatuses(response, []int{http.StatusNoContent, http.StatusPreconditionFailed}, t)
+			if tag, err := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(modified)); err == nil && tag.DeletedAt == nil {
+				t.Logf("\t\tThe modified tag should have been kept. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe modified tag should have been kept. %v", test.BallotX)
+			}
+		}
+	}
+}
+
+// Deletes a parent and its child in one request with the default reject policy.
+func TestBatchDeleteTagsSuccess(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		travel, flights, business := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Batch delete request to endpoint:  \"%s\"", "\\tags\\batchDelete")
+		{
+			body := model.BatchDeleteRequest{Ids: []string{flights, business, travel}}
+			response := batch(router, "/tags/batchDelete", body, t)
+
+			// the children are deleted in the same batch, so their parents are not rejected
+			checkBatchStatuses(response, []int{http.StatusNoContent, http.StatusNoContent, http.StatusNoContent}, t)
+
+			for _, id := range []string{travel, flights, business} {
+				if tag, err := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(id)); err == nil && tag.DeletedAt != nil {
+					t.Logf("\t\tThe tag \"%s\" should have been moved to the trash. %v", id, test.CheckMark)
+				} else {
+					t.Errorf("\t\tThe tag \"%s\" should have been moved to the trash. %v", id, test.BallotX)
+				}
+			}
+		}
+	}
+}
+
+// Deletes a parent and its child in one request with the cascade policy, the child also being a descendant of the parent.
+func TestBatchDeleteTagsCascadingToDeletedChild(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		travel, flights, business := createTaxonomy(router, t)
+		hotels := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Hotels"), Colour: "Blue", ParentId: travel}, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Batch delete request with the cascade policy to endpoint:  \"%s\"", "\\tags\\batchDelete")
+		{
+			body := model.BatchDeleteRequest{Ids: []string{travel, flights}}
+			response := batch(router, "/tags/batchDelete?children=cascade", body, t)
+
+			checkBatchStatuses(response, []int{http.StatusNoContent, http.StatusNoContent}, t)
+
+			for _, id := range []string{travel, flights, business, hotels} {
+				entries := getHistory(router, "/tags/"+id+"/history", test.OrgID1, t).Entries
+				if len(entries) == 2 && entries[0].Operation == model.OperationDelete && entries[1].Operation == model.OperationCreate {
+					t.Logf("\t\tThe tag \"%s\" should have been moved to the trash once. %v", id, test.CheckMark)
+				} else {
+					t.Errorf("\t\tThe tag \"%s\" should have been moved to the trash once:  \"%v\". %v", id, entries, test.BallotX)
+				}
+			}
+		}
+	}
+}
+
+// Deletes a tag in batch with the reparent policy given a stale version, its children being left in place.
+func TestBatchDeleteTagsReparentingWithStaleVersion(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		_, flights, business := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Batch delete request with the reparent policy and a stale version to endpoint:  \"%s\"", "\\tags\\batchDelete")
+		{
+			body := model.BatchDeleteRequest{Ids: []string{flights}, Versions: map[string]int{flights: 2}}
+			response := batch(router, "/tags/batchDelete?children=reparent", body, t)
+
+			checkBatchStatuses(response, []int{http.StatusPreconditionFailed}, t)
+			if tag, err := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(business)); err == nil && tag.ParentId.Hex() == flights && tag.Version == 1 {
+				t.Logf("\t\tThe child should have been left in place. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe child should have been left in place. %v", test.BallotX)
+			}
+		}
+	}
+}
+
+// Attempt to send an empty batch results in bad request.
+func TestBatchDeleteTagsWithNoIds(t *testing.T) {
+
+	t.Logf("Given the tag service is up and running")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		t.Logf("\tWhen Sending Batch delete request with no ids to endpoint:  \"%s\"", "\\tags\\batchDelete")
+		{
+			req, err := test.HttpRequest(model.BatchDeleteRequest{Ids: []string{}}, "/tags/batchDelete", http.MethodPost, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+	}
+}
+
+// helper function
+func batch(router *gin.Engine, url string, body interface{}, t *testing.T) model.BatchResponse {
+	req, _ := test.HttpRequest(body, url, http.MethodPost, test.Token2, test.OrgID1)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusOK)
+
+	var response model.BatchResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	return response
+}
+
+// helper function
+func checkBatchStatuses(response model.BatchResponse, expected []int, t *testing.T) {
+	if len(response.Results) != len(expected) {
+		t.Errorf("\t\tThe response should have \"%d\" results. %v %v", len(expected), test.BallotX, response)
+		return
+	}
+	for i, status := range expected {
+		if response.Results[i].Index == i && response.Results[i].Status == status {
+			t.Logf("\t\tItem %d should have the status \"%d\". %v", i, status, test.CheckMark)
+		} else {
+			t.Errorf("\t\tItem %d should have the status \"%d\":  \"%v\". %v", i, status, response.Results[i], test.BallotX)
+		}
+	}
+}
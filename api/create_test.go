@@ -19,7 +19,7 @@ func TestCreateTagSuccess(t *testing.T) {
 			handler := NewTagHandler(Repository)
 			router := handler.CreateRouter()
 
-			body := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
+			body := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
 			req, err := test.HttpRequest(body, "/tags", http.MethodPost, test.Token1, test.OrgID1)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
@@ -52,7 +52,7 @@ func TestCreateRouteWithInvalidCreateTagRequest(t *testing.T) {
 			router := handler.CreateRouter()
 
 			w := httptest.NewRecorder()
-			body := model.TagDAO{Name: "Dinner"}
+			body := model.TagDAO{Colour: "Red"}
 			req, err := test.HttpRequest(body, "/tags", http.MethodPost, test.Token1, test.OrgID1)
 			req.Header.Add("Authorization", "DummyToken")
 			router.ServeHTTP(w, req)
@@ -92,7 +92,7 @@ func TestCreateRouteWithInvalidOrganisationId(t *testing.T) {
 			router := controller.CreateRouter()
 
 			w := httptest.NewRecorder()
-			body := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
+			body := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
 			req, err := test.HttpRequest(body, "/tags", http.MethodPost, test.Token1, "")
 			router.ServeHTTP(w, req)
 
@@ -152,7 +152,7 @@ func TestCreateRouteWithInvalidToken(t *testing.T) {
 			router := controller.CreateRouter()
 
 			w := httptest.NewRecorder()
-			body := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
+			body := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
 			req, err := test.HttpRequest(body, "/tags", http.MethodPost, test.InvalidToken, test.OrgID1)
 			router.ServeHTTP(w, req)
 
@@ -17,7 +17,7 @@ func TestDeleteTagSuccess(t *testing.T) {
 		controller := NewTagHandler(Repository)
 		router := controller.CreateRouter()
 
-		tag := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
+		tag := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
 		id := test.CreateTag(tag, router, t, test.Token2, test.OrgID1)
 
 		t.Logf("\t\ttWhen Sending Delete tag request to endpoint:  \"%s\"", "\\tags\\"+id)
@@ -57,7 +57,7 @@ func TestDeleteTagIdNotFound(t *testing.T) {
 	}
 }
 
-// Attempt to delete a tag not belonging to the organisation result in conflict error.
+// Attempt to delete a tag not belonging to the organisation result in not found error.
 func TestDeleteTagNotBelongingToTheUser(t *testing.T) {
 
 	t.Logf("Given I create a tag")
@@ -65,7 +65,7 @@ func TestDeleteTagNotBelongingToTheUser(t *testing.T) {
 		controller := NewTagHandler(Repository)
 		router := controller.CreateRouter()
 
-		tag := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
+		tag := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
 		id := test.CreateTag(tag, router, t, test.Token2, test.OrgID1)
 
 		t.Logf("\t\tWhen Sending Delete tag request to endpoint:  \"%s\" with a different organisationId", "\\tags\\"+id)
@@ -77,7 +77,7 @@ func TestDeleteTagNotBelongingToTheUser(t *testing.T) {
 			test.Ok(err, t)
 
 			// Assert response code status
-			test.CheckStatus(w, t, http.StatusConflict)
+			test.CheckStatus(w, t, http.StatusNotFound)
 		}
 	}
 }
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+	"strconv"
+)
+
+const (
+	RetryAfterHeader   = "Retry-After"
+	RetryAfterSeconds  = 5
+	ConflictMessage    = "The change conflicts with an existing document"
+	UnavailableMessage = "The database is unavailable, please retry later"
+)
+
+// Returns the status and message reporting the error of the repository: a malformed id or a missing organisation is
+// a bad request, a missing document is not found, a unique index violation is a conflict and an unreachable database
+// is unavailable. Any other error is an internal error, reported with the given message.
+func repositoryStatus(err error, message string) (int, string) {
+	switch {
+	case errors.Is(err, repository.ErrInvalidID), errors.Is(err, repository.ErrNoTenant):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound, "not found"
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusConflict, ConflictMessage
+	case errors.Is(err, repository.ErrUnavailable):
+		return http.StatusServiceUnavailable, UnavailableMessage
+	}
+	return http.StatusInternalServerError, message
+}
+
+// Writes the error response for the error of the repository, see repositoryStatus. The client is told when to retry
+// while the database is unavailable.
+func setRepositoryError(err error, message string, c *gin.Context) {
+	logger.Error.Println(err.Error())
+	status, message := repositoryStatus(err, message)
+	switch status {
+	case http.StatusNotFound:
+		c.JSON(http.StatusNotFound, model.EmptyBody{})
+	case http.StatusServiceUnavailable:
+		c.Header(RetryAfterHeader, strconv.Itoa(RetryAfterSeconds))
+		setErrorResponse(message, status, c)
+	default:
+		setErrorResponse(message, status, c)
+	}
+}
+
+// Parses the id given in the request, the error response is written when it is malformed.
+func parseId(c *gin.Context, id string) (bson.ObjectId, bool) {
+	oid, err := repository.ParseID(id)
+	if err != nil {
+		setRepositoryError(err, "", c)
+		return "", false
+	}
+	return oid, true
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"github.com/BetaProjectWave/jwt-go-plugin"
 	gintrace "github.com/DataDog/dd-trace-go/contrib/gin-gonic/gin"
 	"github.com/DataDog/dd-trace-go/tracer"
@@ -15,7 +17,9 @@ import (
 	"github.com/tag-service/repository"
 	cfg "github.com/tag-service/vault"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -29,6 +33,12 @@ const (
 	OrganisationId           = "organisationId"
 	AccountId                = "accountId"
 	TagId                    = "id"
+	TagName                  = "name"
+	TagColour                = "colour"
+	LimitParam               = "limit"
+	CursorParam              = "cursor"
+	DefaultLimit             = 50
+	MaxLimit                 = 200
 	DataDogAgentHostEnv      = "DD_AGENT_HOST"
 	DataDogAgentHostFallback = "localhost"
 	DataDogServiceNameEnv    = "DD_SERVICE_NAME"
@@ -36,11 +46,20 @@ const (
 )
 
 type TagHandler struct {
-	repo repository.Repository
+	// reaches the data of every organisation, for the jobs working across them. The requests go through tenant.
+	unscoped repository.Repository
+	// identifies the instance of the service holding the leases of the background jobs
+	instance string
 }
 
 func NewTagHandler(repo repository.Repository) *TagHandler {
-	return &TagHandler{repo}
+	return &TagHandler{unscoped: repo, instance: bson.NewObjectId().Hex()}
+}
+
+// Returns the repository bound to the organisation and the account of the request, so that the data of the other
+// organisations is out of reach of the handlers.
+func (handler *TagHandler) tenant(c *gin.Context) repository.TenantRepository {
+	return repository.NewTenantRepository(handler.unscoped, repository.Tenant{OrganisationId: c.Request.Header.Get(OrganisationIDField), AccountId: c.Request.Header.Get(AccountIDField)})
 }
 
 // @Summary Creates new tag
@@ -51,7 +70,10 @@ func NewTagHandler(repo repository.Repository) *TagHandler {
 // @Param new-tag body model.CreateTagRequest true "New tag"
 // @Success 201 {object} model.CreateTagResponse "Tag created"
 // @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:write permission"
+// @Failure 409 {object} model.ErrorResponse "A tag with the same name already exists"
 // @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
 // @Router /tags [post]
 func (handler *TagHandler) CreateTag(c *gin.Context) {
 	var req model.CreateTagRequest
@@ -63,8 +85,14 @@ func (handler *TagHandler) CreateTag(c *gin.Context) {
 		return
 	}
 
+	// either the name or the key of the tag is required
+	if req.Name == "" && req.Key == "" {
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
 	// return bad request if tag is empty
-	if tagNameEmptyString(req) {
+	if req.Key == "" && tagNameEmptyString(req.Name) {
 		setErrorResponse("tag name may not be empty", http.StatusBadRequest, c)
 		return
 	}
@@ -73,39 +101,108 @@ func (handler *TagHandler) CreateTag(c *gin.Context) {
 	organisationId := c.Request.Header.Get(OrganisationIDField)
 	logger.Info.Printf("Received request to create tag with name \"%v\" for accountId \"%v\" and organisationId \"%v", req.Name, accountId, organisationId)
 
-	tag := model.TagDAO{Id: bson.NewObjectId(), Name: req.Name, Colour: req.Colour, AccountId: accountId, OrganisationId: organisationId}
+	// the name of a key/value tag is made of its key and value
+	keys := map[string]model.KeyDAO{}
+	if req.Key != "" {
+		var err error
+		if keys, err = handler.organisationKeys(c, organisationId); err != nil {
+			return
+		}
+	}
+	name, value, err := keyedTag(keys, req.Key, req.Value, req.Name)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	colour, ok := handler.resolveColour(c, organisationId, req.Colour, name)
+	if !ok {
+		return
+	}
+
+	attributes := model.MergeAttributes(nil, req.Attributes)
+	if !handler.validateAttributes(c, organisationId, attributes) {
+		return
+	}
+	translations, err := model.NormaliseTranslations(req.Translations)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+	visibility, err := tagVisibility(req.Visibility)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	tag := model.TagDAO{Id: bson.NewObjectId(), Name: model.NormaliseName(name), NormalisedName: model.NameKey(name), Colour: colour, AccountId: accountId, OrganisationId: organisationId, Version: 1, UpdatedAt: time.Now(), Key: req.Key, Value: value, Attributes: attributes, Translations: translations, Visibility: visibility}
+
+	// the parent tag must exist within the organisation
+	if req.ParentId != "" {
+		parentId, ok := handler.validateParent(c, organisationId, tag.Id, req.ParentId)
+		if !ok {
+			return
+		}
+		tag.ParentId = parentId
+	}
 	logger.Info.Printf("Tag \"%v\" successfully created", tag.Id.Hex())
-	err := handler.repo.Insert(DatabaseName, DatabaseCollection, &tag)
+	record := historyOf(c, model.OperationCreate, nil, &tag)
+	err = handler.insertTag(c, withCreationEvent(tag, record))
+	if errors.Is(err, repository.ErrConflict) {
+		handler.nameConflict(c, organisationId, tag.NormalisedName)
+		return
+	}
 	if err != nil {
-		logger.Error.Println(err.Error())
-		setErrorResponse("Insert failed", http.StatusInternalServerError, c)
+		setRepositoryError(err, "Insert failed", c)
 		return
 	}
 
+	handler.recordChanges(c, record)
+
 	// if all good create success response
 	c.Writer.Header().Set(ContentType, JSONMimeType)
+	c.Header(ETagHeader, etag(tag))
 	c.JSON(http.StatusCreated, model.CreateTagResponse{Id: tag.Id.Hex()})
 }
 
 // @Summary Get tags
 // @ID get-tags
+// @Description Returns all the tags unless one of the paging parameters is set, in which case one page is returned.
 // @Accept  json
 // @Produce  json
+// @Param limit query int false "Maximum number of tags returned"
+// @Param cursor query string false "Cursor returned with the previous page"
+// @Param sort query string false "Sort order: name (default), -name, createdAt or -createdAt"
+// @Param count query bool false "Include the total number of tags"
+// @Param key query string false "Only the key/value tags with this key"
+// @Param value query string false "Only the key/value tags with this value, along with the key"
+// @Param Accept-Language header string false "Preferred languages of the tag names"
 // @Success 200 {object} model.GetAllTagResponse	"ok"
 // @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
 // @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
 // @Router /tags [get]
 func (handler *TagHandler) GetAllTags(c *gin.Context) {
 	accountId := c.Request.Header.Get(AccountIDField)
 	organisationId := c.Request.Header.Get(OrganisationIDField)
 	logger.Info.Printf("Received retrieve all tags request for accountId \"%s\" and organisationId \"%v", accountId, organisationId)
-	results, err := handler.repo.FindAll(DatabaseName, DatabaseCollection, bson.M{AccountId: accountId, OrganisationId: organisationId})
+	query := readable(c, live(bson.M{OrganisationId: organisationId}))
+	if !keyFilter(c, query) {
+		return
+	}
+
+	if pagingRequested(c) {
+		handler.getTagsPage(c, query)
+		return
+	}
+
+	results, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, query)
 	if err != nil {
-		logger.Error.Println("Failed to retrieve data from the database")
-		setErrorResponse("Failed to retrieve data from the database", http.StatusInternalServerError, c)
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
 		return
 	}
-	c.JSON(http.StatusOK, model.Convert(results))
+	c.JSON(http.StatusOK, model.Convert(localise(c, results)))
 }
 
 // @Summary Get tag by ID
@@ -113,33 +210,240 @@ func (handler *TagHandler) GetAllTags(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id path string true "Tag ID"
+// @Param Accept-Language header string false "Preferred languages of the tag names"
 // @Success 200 {object} model.Tag "ok"
+// @Failure 400 {object} model.ErrorResponse "Invalid tag id"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
 // @Failure 404 {object} model.EmptyBody "Tag not found"
 // @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
 // @Router /tags/{id} [get]
 func (handler *TagHandler) GetTag(c *gin.Context) {
 	accountId := c.Request.Header.Get(AccountIDField)
 	id := c.Params.ByName("id")
 	organisationId := c.Request.Header.Get(OrganisationIDField)
 	logger.Info.Printf("Received request to retrieve tag \"%v\" from accountId \"%v\" for organisationId \"%v", id, accountId, organisationId)
-	oid := bson.ObjectIdHex(id)
-	result, err := handler.repo.Find(DatabaseName, DatabaseCollection, oid)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
+	result, ok := handler.findOrganisationTag(c, oid, organisationId, ReadAccess)
+	if !ok {
+		return
+	}
+	c.Header(ETagHeader, etag(result))
+	c.JSON(http.StatusOK, model.ConvertToTag(localise(c, []model.TagDAO{result})[0]))
+}
+
+// @Summary Replace tag by ID
+// @ID update-tag
+// @Description Replaces the name, colour and attributes of the given tag
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tag ID"
+// @Param tag body model.UpdateTagRequest true "Updated tag"
+// @Param If-Match header string false "ETag of the tag as last read"
+// @Success 200 {object} model.Tag "Tag updated"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The tag is read-only, or the role of the account does not grant the tags:write permission"
+// @Failure 404 {object} model.EmptyBody "Tag not found"
+// @Failure 409 {object} model.ErrorResponse "A tag with the same name already exists"
+// @Failure 412 {object} model.ErrorResponse "The tag has been modified since it was read"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/{id} [put]
+func (handler *TagHandler) UpdateTag(c *gin.Context) {
+	var req model.UpdateTagRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
+	// return bad request if tag is empty
+	if tagNameEmptyString(req.Name) {
+		setErrorResponse("tag name may not be empty", http.StatusBadRequest, c)
+		return
+	}
+
+	accountId := c.Request.Header.Get(AccountIDField)
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(TagId)
+	logger.Info.Printf("Received request to update tag \"%v\" for accountId \"%v\" and organisationId \"%v\"", id, accountId, organisationId)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
+
+	// query the tag and check it belongs to the organisation and has not been modified since the client read it
+	tag, ok := handler.findOrganisationTag(c, oid, organisationId, WriteAccess)
+	if !ok || !checkIfMatch(c, tag) {
+		return
+	}
+	before := tag
+
+	// the name of a key/value tag follows its value
+	if tag.Key != "" && model.NameKey(req.Name) != tag.NormalisedName {
+		setErrorResponse(KeyedNameMessage, http.StatusBadRequest, c)
+		return
+	}
+
+	// the tag may not be moved under one of its own descendants
+	tag.ParentId = ""
+	if req.ParentId != "" {
+		parentId, ok := handler.validateParent(c, organisationId, tag.Id, req.ParentId)
+		if !ok {
+			return
+		}
+		tag.ParentId = parentId
+	}
+
+	colour, ok := handler.resolveColour(c, organisationId, req.Colour, req.Name)
+	if !ok {
+		return
+	}
+
+	tag.Attributes = model.MergeAttributes(nil, req.Attributes)
+	if !handler.validateAttributes(c, organisationId, tag.Attributes) {
+		return
+	}
+	translations, err := model.NormaliseTranslations(req.Translations)
 	if err != nil {
-		c.JSON(http.StatusNotFound, model.EmptyBody{})
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
 		return
 	}
-	c.JSON(http.StatusOK, model.ConvertToTag(result))
+	tag.Translations = translations
+
+	tag.Name = model.NormaliseName(req.Name)
+	tag.NormalisedName = model.NameKey(req.Name)
+	tag.Colour = colour
+	handler.updateTag(c, before, tag, bson.M{TagName: tag.Name, NormalisedName: tag.NormalisedName, TagColour: tag.Colour, ParentId: tag.ParentId, TagAttributes: tag.Attributes, TagTranslations: tag.Translations})
+}
+
+// @Summary Partially update tag by ID
+// @ID patch-tag
+// @Description Updates only the fields present in the payload
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tag ID"
+// @Param tag body model.PatchTagRequest true "Fields to update"
+// @Param If-Match header string false "ETag of the tag as last read"
+// @Success 200 {object} model.Tag "Tag updated"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The tag is read-only, or the role of the account does not grant the tags:write permission"
+// @Failure 404 {object} model.EmptyBody "Tag not found"
+// @Failure 409 {object} model.ErrorResponse "A tag with the same name already exists"
+// @Failure 412 {object} model.ErrorResponse "The tag has been modified since it was read"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/{id} [patch]
+func (handler *TagHandler) PatchTag(c *gin.Context) {
+	var req model.PatchTagRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
+	if req.Name == nil && req.Colour == nil && req.ParentId == nil && req.Value == nil && req.Attributes == nil && req.Translations == nil {
+		setErrorResponse("no fields to update", http.StatusBadRequest, c)
+		return
+	}
+
+	// return bad request if tag is being renamed to an empty string
+	if req.Name != nil && tagNameEmptyString(*req.Name) {
+		setErrorResponse("tag name may not be empty", http.StatusBadRequest, c)
+		return
+	}
+
+	accountId := c.Request.Header.Get(AccountIDField)
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(TagId)
+	logger.Info.Printf("Received request to patch tag \"%v\" for accountId \"%v\" and organisationId \"%v\"", id, accountId, organisationId)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
+
+	// query the tag and check it belongs to the organisation and has not been modified since the client read it
+	tag, ok := handler.findOrganisationTag(c, oid, organisationId, WriteAccess)
+	if !ok || !checkIfMatch(c, tag) {
+		return
+	}
+	before := tag
+
+	fields := bson.M{}
+	if req.Name != nil || req.Value != nil {
+		keys := map[string]model.KeyDAO{}
+		if tag.Key != "" {
+			var err error
+			if keys, err = handler.organisationKeys(c, organisationId); err != nil {
+				return
+			}
+		}
+		if err := renameTag(keys, &tag, req.Name, req.Value, fields); err != nil {
+			setErrorResponse(err.Error(), http.StatusBadRequest, c)
+			return
+		}
+	}
+	if req.Colour != nil {
+		colour, ok := handler.resolveColour(c, organisationId, *req.Colour, tag.Name)
+		if !ok {
+			return
+		}
+		tag.Colour = colour
+		fields[TagColour] = tag.Colour
+	}
+	if req.ParentId != nil {
+		// the tag may not be moved under one of its own descendants
+		tag.ParentId = ""
+		if *req.ParentId != "" {
+			parentId, ok := handler.validateParent(c, organisationId, tag.Id, *req.ParentId)
+			if !ok {
+				return
+			}
+			tag.ParentId = parentId
+		}
+		fields[ParentId] = tag.ParentId
+	}
+	// the tag is validated even when its attributes are left untouched, the schema may have changed since it was written
+	if req.Attributes != nil {
+		tag.Attributes = model.MergeAttributes(tag.Attributes, req.Attributes)
+		fields[TagAttributes] = tag.Attributes
+	}
+	if !handler.validateAttributes(c, organisationId, tag.Attributes) {
+		return
+	}
+	if req.Translations != nil {
+		translations, err := model.MergeTranslations(tag.Translations, req.Translations)
+		if err != nil {
+			setErrorResponse(err.Error(), http.StatusBadRequest, c)
+			return
+		}
+		tag.Translations = translations
+		fields[TagTranslations] = translations
+	}
+	handler.updateTag(c, before, tag, fields)
 }
 
 // @Summary Delete tag by ID
 // @ID delete-tag
+// @Description Moves the tag to the trash, from which it can be restored until it is purged
 // @Accept  json
 // @Produce  json
 // @Param id path string true "Tag ID"
+// @Param children query string false "What happens to the child tags: reject (default), cascade or reparent"
+// @Param If-Match header string false "ETag of the tag as last read"
 // @Success 204 "Tag deleted"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The tag or one of its descendants is read-only, or the role of the account does not grant the tags:write permission"
 // @Failure 404 {object} model.ErrorResponse "Tag not found"
-// @Failure 409 {object} model.ErrorResponse "The given tag id does not belong to the user"
+// @Failure 409 {object} model.ErrorResponse "The tag has child tags"
+// @Failure 412 {object} model.ErrorResponse "The tag has been modified since it was read"
 // @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
 // @Router /tags/{id} [delete]
 func (handler *TagHandler) DeleteTag(c *gin.Context) {
 
@@ -148,28 +452,66 @@ func (handler *TagHandler) DeleteTag(c *gin.Context) {
 
 	id := c.Params.ByName(TagId)
 	logger.Info.Printf("Received request to delete tag for given accountId \"%v\", organisationId \"%v\" and tagId \"%v\"", accountId, organisationId, id)
-	oid := bson.ObjectIdHex(id)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
 
-	// query the tag
-	result, errQ := handler.repo.Find(DatabaseName, DatabaseCollection, oid)
-	if errQ != nil {
-		c.JSON(http.StatusNotFound, model.EmptyBody{})
+	policy := c.DefaultQuery(ChildrenParam, RejectChildren)
+	if !validChildrenPolicy(policy) {
+		setErrorResponse("children must be one of reject, cascade or reparent", http.StatusBadRequest, c)
 		return
 	}
 
-	// the tag does not belong to the organisation.
-	if result.OrganisationId != organisationId {
-		logger.Error.Println("The given tag id does not belong to the organisation")
-		setErrorResponse("The given tag id does not belong to the organisation", http.StatusConflict, c)
+	// query the tag and check it belongs to the organisation and has not been modified since the client read it
+	tag, ok := handler.findOrganisationTag(c, oid, organisationId, WriteAccess)
+	if !ok || !checkIfMatch(c, tag) {
 		return
 	}
 
-	// remove the tag
-	err := handler.repo.Delete(DatabaseName, DatabaseCollection, oid)
+	// check the policy can be applied to the child tags, which are only changed once their parent is in the trash
+	descendants, children, ok := handler.checkChildren(c, tag, policy)
+	if !ok {
+		return
+	}
+
+	// move the tag to the trash, unless it has been modified since the client read it
+	deletedAt := time.Now()
+	record := historyOf(c, model.OperationDelete, &tag, trashed(tag, deletedAt))
+	_, err := handler.writeTag(c, tag, withEvent(trashUpdate(deletedAt), record))
+	if errors.Is(err, repository.ErrNotFound) && conditional(c) {
+		setErrorResponse(PreconditionFailMessage, http.StatusPreconditionFailed, c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, model.EmptyBody{})
+		setRepositoryError(err, "Delete failed", c)
 		return
 	}
+
+	records := []model.HistoryDAO{record}
+
+	// the descendants go to the trash along with the tag when cascading
+	if len(descendants) > 0 {
+		changes := make([]model.HistoryDAO, len(descendants))
+		for i, descendant := range descendants {
+			changes[i] = historyOf(c, model.OperationDelete, &descendants[i], trashed(descendant, deletedAt))
+		}
+		trashedDescendants, err := handler.writeChanges(c, bson.M{}, changes, func() bson.M { return trashUpdate(deletedAt) })
+		if err != nil {
+			logger.Error.Printf("Failed to delete the descendants of tag \"%v\": %v", id, err.Error())
+		}
+		records = append(records, trashedDescendants...)
+	}
+
+	// the children move up when reparenting
+	if len(children) > 0 {
+		reparented, err := handler.reparentChildren(c, tag, children)
+		if err != nil {
+			logger.Error.Printf("Failed to reparent the children of tag \"%v\": %v", id, err.Error())
+		}
+		records = append(records, reparented...)
+	}
+	handler.recordChanges(c, records...)
 	logger.Info.Printf("Tag successfully deleted \"%v\"", id)
 	c.Status(http.StatusNoContent)
 }
@@ -196,22 +538,164 @@ func (handler *TagHandler) CreateRouter() *gin.Engine {
 	router.Use(gin.Recovery())
 	router.Use(gintrace.MiddlewareTracer(cfg.GetEnv(DataDogServiceNameEnv, ServiceName), t))
 
-	router.GET("/tags", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.GetAllTags)
-	router.GET("/tags/:id", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.GetTag)
-	router.DELETE("/tags/:id", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.DeleteTag)
+	router.GET("/tags", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.GetAllTags)
+	router.GET("/tags/:id", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), subRoutes{"tree": handler.GetTagTree, "search": handler.SearchTags, "trash": handler.GetTrash, "history": handler.GetHistory, "events": handler.StreamEvents, "changes": handler.GetChanges, "stats": handler.GetStats, "export": handler.ExportTags}.dispatch(handler.GetTag))
+	router.GET("/tags/:id/descendants", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.GetTagDescendants)
+	router.GET("/tags/:id/history", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.GetTagHistory)
+	router.GET("/tags/:id/stats", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.GetTagStats)
+	router.PUT("/tags/:id/visibility", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), handler.UpdateVisibility)
+	router.PUT("/tags/:id", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), handler.UpdateTag)
+	router.PATCH("/tags/:id", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), handler.PatchTag)
+	router.DELETE("/tags/:id", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), handler.DeleteTag)
+	router.GET("/resources", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.FindResources)
+	router.GET("/resources/:type/:id/tags", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.GetResourceTags)
+	router.POST("/resources/:type/:id/tags", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), handler.AssignTags)
+	router.DELETE("/resources/:type/:id/tags/:tagId", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), handler.UnassignTag)
+	router.GET("/webhooks", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.GetWebhooks)
+	router.POST("/webhooks", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.CreateWebhook)
+	router.DELETE("/webhooks/:id", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.DeleteWebhook)
+	router.GET("/webhooks/:id/deliveries", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.GetDeliveries)
+	router.GET("/keys", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.GetKeys)
+	router.POST("/keys", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.CreateKey)
+	router.PUT("/keys/:key", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.UpdateKey)
+	router.DELETE("/keys/:key", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.DeleteKey)
+	router.GET("/schema", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.GetSchema)
+	router.PUT("/schema", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.UpdateSchema)
+	router.GET("/palette", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionRead), handler.GetPalette)
+	router.PUT("/palette", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.UpdatePalette)
+	router.GET("/members", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.GetMembers)
+	router.PUT("/members/:accountId", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.UpdateMember)
+	router.DELETE("/members/:accountId", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionAdmin), handler.DeleteMember)
 	router.GET("/health", handler.Health)
-	router.POST("/tags", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.CreateTag)
+	router.POST("/tags", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), handler.CreateTag)
+	router.POST("/tags/:id/restore", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), handler.RestoreTag)
+	router.POST("/tags/:id", jwt.GinJWTMiddleware().MiddlewareFunc(), handler.require(model.PermissionWrite), subRoutes{"batchCreate": handler.BatchCreateTags, "batchUpdate": handler.BatchUpdateTags, "batchDelete": handler.BatchDeleteTags, "import": handler.ImportTags}.dispatch(notFound))
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	return router
 }
 
+// Endpoints living under /tags next to the tag id, e.g. /tags/tree. The router does not allow static path segments
+// alongside the :id wildcard, so they are registered on the wildcard and dispatched by name.
+type subRoutes map[string]gin.HandlerFunc
+
+// Calls the endpoint named by the :id parameter, or the given handler when the parameter is a tag id.
+func (routes subRoutes) dispatch(byId gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if route, ok := routes[c.Params.ByName(TagId)]; ok {
+			route(c)
+			return
+		}
+		byId(c)
+	}
+}
+
+// Responds to the requests made to a tag id on which the method is not allowed.
+func notFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, model.EmptyBody{})
+}
+
 // Helper method
 func setErrorResponse(msg string, status int, c *gin.Context) {
 	c.JSON(status, model.ErrorResponse{Message: msg, Code: status})
 }
 
-// checks if the given payload contain tag name with an empty string
-func tagNameEmptyString(req model.CreateTagRequest) bool {
-	return len(strings.TrimSpace(req.Name)) == 0
+// Queries the tag for the given id and checks the account of the request has the given access to it. The error
+// response is written when the tag is not found within the organisation, in the trash or when the access is denied by
+// its visibility.
+func (handler *TagHandler) findOrganisationTag(c *gin.Context, oid bson.ObjectId, organisationId string, access string) (model.TagDAO, bool) {
+	result, err := handler.tenant(c).Find(DatabaseName, DatabaseCollection, oid)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve the tag", c)
+		return result, false
+	}
+	if result.DeletedAt != nil {
+		c.JSON(http.StatusNotFound, model.EmptyBody{})
+		return result, false
+	}
+	return result, checkAccess(c, result, organisationId, access)
+}
+
+// Checks the account of the request has the given access to the tag, the error response is written otherwise.
+func checkAccess(c *gin.Context, tag model.TagDAO, organisationId string, access string) bool {
+	status, message := denial(tag, organisationId, c.Request.Header.Get(AccountIDField), access)
+	switch status {
+	case 0:
+		return true
+	case http.StatusNotFound:
+		c.JSON(http.StatusNotFound, model.EmptyBody{})
+	default:
+		logger.Error.Println(message)
+		setErrorResponse(message, status, c)
+	}
+	return false
+}
+
+// Persists the given fields and writes the updated tag to the response. The update of a conditional request only
+// applies if the tag still has the version it was read with.
+func (handler *TagHandler) updateTag(c *gin.Context, before model.TagDAO, tag model.TagDAO, fields bson.M) {
+	after := tag
+	after.Version++
+	record := historyOf(c, model.OperationUpdate, &before, &after)
+	stored, err := handler.writeTag(c, tag, withEvent(tagUpdate(fields), record))
+	if errors.Is(err, repository.ErrNotFound) && conditional(c) {
+		setErrorResponse(PreconditionFailMessage, http.StatusPreconditionFailed, c)
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		handler.nameConflict(c, tag.OrganisationId, tag.NormalisedName)
+		return
+	}
+	if err != nil {
+		setRepositoryError(err, "Update failed", c)
+		return
+	}
+	logger.Info.Printf("Tag successfully updated \"%v\"", tag.Id.Hex())
+	record.After = &stored
+	handler.recordChanges(c, record)
+	c.Header(ETagHeader, etag(stored))
+	c.JSON(http.StatusOK, model.ConvertToTag(stored))
+}
+
+// Builds the update setting the given fields, incrementing the version and recording the time of the change. An empty
+// parent id, empty attributes and empty translations are removed from the tag.
+func tagUpdate(fields bson.M) bson.M {
+	update := bson.M{"$inc": bson.M{Version: 1}}
+	unset := bson.M{}
+	if parentId, ok := fields[ParentId]; ok && parentId == bson.ObjectId("") {
+		delete(fields, ParentId)
+		unset[ParentId] = ""
+	}
+	if attributes, ok := fields[TagAttributes].(map[string]interface{}); ok && len(attributes) == 0 {
+		delete(fields, TagAttributes)
+		unset[TagAttributes] = ""
+	}
+	if translations, ok := fields[TagTranslations].(map[string]string); ok && len(translations) == 0 {
+		delete(fields, TagTranslations)
+		unset[TagTranslations] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	fields[UpdatedAt] = time.Now()
+	update["$set"] = fields
+	return update
+}
+
+// Reads the page size from the limit query parameter, falling back to DefaultLimit.
+func queryLimit(c *gin.Context) (int, error) {
+	value := c.Query(LimitParam)
+	if value == "" {
+		return DefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		return 0, fmt.Errorf("limit must be a number between 1 and %d", MaxLimit)
+	}
+	return limit, nil
+}
+
+// checks if the given tag name is an empty string
+func tagNameEmptyString(name string) bool {
+	return len(strings.TrimSpace(name)) == 0
 }
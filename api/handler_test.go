@@ -3,10 +3,12 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/globalsign/mgo/bson"
 	"github.com/golang/mock/gomock"
 	"github.com/tag-service/mocks"
 	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
 	"github.com/tag-service/test"
 	"net/http"
 	"net/http/httptest"
@@ -25,12 +27,15 @@ func TestTagHandler_CreateTag_Failure_to_Insert(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			mockRepo := mocks.NewMockRepository(mockCtrl)
+			withMembership(mockRepo, model.RoleEditor)
+			withSequences(mockRepo)
 
 			expectedErrorMessage := "Insert failed"
 			body := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
 			err := errors.New(expectedErrorMessage)
 
-			mockRepo.EXPECT().Insert(gomock.Any(), gomock.Any(), gomock.Any()).Return(err).Times(1)
+			paletteCall := mockRepo.EXPECT().FindPalette(gomock.Any(), gomock.Any(), test.OrgID1).Return(model.PaletteDAO{}, repository.ErrNotFound).Times(1)
+			mockRepo.EXPECT().Insert(gomock.Any(), gomock.Any(), gomock.Any()).Return(err).Times(1).After(paletteCall)
 
 			handler := NewTagHandler(mockRepo)
 			router := handler.CreateRouter()
@@ -63,24 +68,22 @@ Unit tests around the negative edges of testing against the DB failures.
 func TestTagHandler_DeleteTag_Failure_User_id_mismatch(t *testing.T) {
 	t.Logf("Given the tag service is up and running")
 	{
-		t.Logf("\tWhen Sending Delete TagDAO request with dummy accountId to the endpoint:  \"%s\"", "\\tags")
+		t.Logf("\tWhen Sending Delete TagDAO request for a tag of another organisation to the endpoint:  \"%s\"", "\\tags")
 		{
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			mockRepo := mocks.NewMockRepository(mockCtrl)
+			withMembership(mockRepo, model.RoleEditor)
 
-			expectedErrorMessage := "The given tag id does not belong to the organisation"
-			err := errors.New(expectedErrorMessage)
-			body := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
+			oid := bson.NewObjectId()
 
-			tag := model.TagDAO{Id: bson.NewObjectId(), Name: body.Name, Colour: body.Colour, OrganisationId: test.OrgID1}
-
-			mockRepo.EXPECT().Find(gomock.Any(), gomock.Any(), gomock.Any()).Return(tag, nil).Times(1)
+			// the tag is only looked up within the organisation of the request
+			mockRepo.EXPECT().FindOne(gomock.Any(), gomock.Any(), bson.M{"_id": oid, "organisationId": test.OrgID2}).Return(model.TagDAO{}, repository.ErrNotFound).Times(1)
 
 			handler := NewTagHandler(mockRepo)
 			router := handler.CreateRouter()
 
-			req, err := test.HttpRequest(nil, "/tags/"+bson.NewObjectId().Hex(), http.MethodDelete, test.Token1, test.OrgID2)
+			req, err := test.HttpRequest(nil, "/tags/"+oid.Hex(), http.MethodDelete, test.Token1, test.OrgID2)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
@@ -88,15 +91,7 @@ func TestTagHandler_DeleteTag_Failure_User_id_mismatch(t *testing.T) {
 			test.Ok(err, t)
 
 			// Assert response code status
-			test.CheckStatus(w, t, http.StatusConflict)
-
-			var response model.ErrorResponse
-			json.NewDecoder(w.Body).Decode(&response)
-
-			expectedResponse := model.ErrorResponse{Code: http.StatusConflict, Message: expectedErrorMessage}
-
-			// check body response matches the expected response
-			test.CheckResponseMessage(response, expectedResponse, t, w)
+			test.CheckStatus(w, t, http.StatusNotFound)
 		}
 	}
 }
@@ -109,15 +104,17 @@ func TestTagHandler_DeleteTag_Tag_not_found(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			mockRepo := mocks.NewMockRepository(mockCtrl)
+			withMembership(mockRepo, model.RoleEditor)
+			withSequences(mockRepo)
 
-			expectedErrorMessage := "Tag not found"
 			body := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
-			err := errors.New(expectedErrorMessage)
+			err := repository.ErrNotFound
 
 			tag := model.TagDAO{Id: bson.NewObjectId(), Name: body.Name, Colour: body.Colour, AccountId: test.AccountID2, OrganisationId: test.OrgID1}
 
-			findCall := mockRepo.EXPECT().Find(gomock.Any(), gomock.Any(), gomock.Any()).Return(tag, nil).Times(1)
-			mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(err).Times(1).After(findCall)
+			findCall := mockRepo.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(tag, nil).Times(1)
+			childrenCall := mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1).After(findCall)
+			mockRepo.EXPECT().Modify(gomock.Any(), gomock.Any(), bson.M{"_id": tag.Id, "organisationId": test.OrgID1}, gomock.Any()).Return(model.TagDAO{}, err).Times(1).After(childrenCall)
 
 			handler := NewTagHandler(mockRepo)
 			router := handler.CreateRouter()
@@ -146,6 +143,7 @@ func TestTagHandler_GetAllTags_Failed_to_Fetch_Due_to_DB_error(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		mockRepo := mocks.NewMockRepository(mockCtrl)
+		withoutMembership(mockRepo)
 		controller := NewTagHandler(mockRepo)
 		router := controller.CreateRouter()
 		expectedErrorMessage := "Failed to retrieve data from the database"
@@ -176,3 +174,167 @@ func TestTagHandler_GetAllTags_Failed_to_Fetch_Due_to_DB_error(t *testing.T) {
 		}
 	}
 }
+
+func TestTagHandler_GetAllTags_Database_Unavailable(t *testing.T) {
+
+	t.Logf("Given Tag service is up and running")
+	{
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockRepo := mocks.NewMockRepository(mockCtrl)
+		withoutMembership(mockRepo)
+		controller := NewTagHandler(mockRepo)
+		router := controller.CreateRouter()
+
+		// set mockrepo expectations
+		err := fmt.Errorf("%w: no reachable servers", repository.ErrUnavailable)
+		mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, err).Times(1)
+
+		t.Logf("\tWhen Sending Get All tags request to endpoint:  \"%s\"", "\\tags")
+		{
+			req, err := test.HttpRequest(nil, "/tags", http.MethodGet, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// check call success
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusServiceUnavailable)
+
+			if w.Header().Get(RetryAfterHeader) != "" {
+				t.Logf("\t\tThe client should be told when to retry. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe client should be told when to retry. %v", test.BallotX)
+			}
+		}
+	}
+}
+
+func TestTagHandler_GetTag_Invalid_Id(t *testing.T) {
+
+	t.Logf("Given Tag service is up and running")
+	{
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockRepo := mocks.NewMockRepository(mockCtrl)
+		withMembership(mockRepo, model.RoleEditor)
+		controller := NewTagHandler(mockRepo)
+		router := controller.CreateRouter()
+
+		t.Logf("\tWhen Sending Get tag request with a malformed id to endpoint:  \"%s\"", "\\tags\\id")
+		{
+			for _, method := range []string{http.MethodGet, http.MethodDelete} {
+				req, err := test.HttpRequest(nil, "/tags/not-an-id", method, test.Token2, test.OrgID1)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				// check call success
+				test.Ok(err, t)
+
+				// Assert response code status, the repository is not queried
+				test.CheckStatus(w, t, http.StatusBadRequest)
+			}
+		}
+	}
+}
+
+func TestTagHandler_UpdateTag_Failure_to_Update(t *testing.T) {
+	t.Logf("Given the tag service is up and running")
+	{
+		t.Logf("\tWhen Sending Update TagDAO request to endpoint:  \"%s\"", "\\tags\\id")
+		{
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockRepo := mocks.NewMockRepository(mockCtrl)
+			withMembership(mockRepo, model.RoleEditor)
+			withSequences(mockRepo)
+
+			expectedErrorMessage := "Update failed"
+			err := errors.New(expectedErrorMessage)
+			body := model.UpdateTagRequest{Name: "Lunch", Colour: "Blue"}
+
+			tag := model.TagDAO{Id: bson.NewObjectId(), Name: "Dinner", Colour: "Red", AccountId: test.AccountID2, OrganisationId: test.OrgID1}
+
+			findCall := mockRepo.EXPECT().FindOne(gomock.Any(), gomock.Any(), bson.M{"_id": tag.Id, "organisationId": test.OrgID1}).Return(tag, nil).Times(1)
+			paletteCall := mockRepo.EXPECT().FindPalette(gomock.Any(), gomock.Any(), test.OrgID1).Return(model.PaletteDAO{}, repository.ErrNotFound).Times(1).After(findCall)
+			mockRepo.EXPECT().Modify(gomock.Any(), gomock.Any(), bson.M{"_id": tag.Id, "organisationId": test.OrgID1}, gomock.Any()).Return(model.TagDAO{}, err).Times(1).After(paletteCall)
+
+			handler := NewTagHandler(mockRepo)
+			router := handler.CreateRouter()
+
+			req, err := test.HttpRequest(body, "/tags/"+tag.Id.Hex(), http.MethodPut, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// check call success
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusInternalServerError)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+
+			expectedResponse := model.ErrorResponse{Code: http.StatusInternalServerError, Message: expectedErrorMessage}
+
+			// check body response matches the expected response
+			test.CheckResponseMessage(response, expectedResponse, t, w)
+		}
+	}
+}
+
+func TestTagHandler_GetAllTags_Page_Requests_One_Extra_Tag(t *testing.T) {
+
+	t.Logf("Given Tag service is up and running")
+	{
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockRepo := mocks.NewMockRepository(mockCtrl)
+		withoutMembership(mockRepo)
+		controller := NewTagHandler(mockRepo)
+		router := controller.CreateRouter()
+
+		// set mockrepo expectations
+		tags := []model.TagDAO{{Id: bson.NewObjectId(), Name: "Alpha"}, {Id: bson.NewObjectId(), Name: "Bravo"}}
+		mockRepo.EXPECT().FindPage(gomock.Any(), gomock.Any(), gomock.Any(), []string{"-name", "-_id"}, 2).Return(tags, nil).Times(1)
+
+		t.Logf("\tWhen Sending Get tags request to endpoint:  \"%s\"", "\\tags?limit=1&sort=-name")
+		{
+			req, err := test.HttpRequest(nil, "/tags?limit=1&sort=-name", http.MethodGet, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// check call success
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.GetAllTagResponse
+			json.NewDecoder(w.Body).Decode(&response)
+
+			if len(response.Tags) == 1 && response.NextCursor != "" {
+				t.Logf("\t\tThe extra tag should only be used to create the next cursor. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe extra tag should only be used to create the next cursor. %v %v", test.BallotX, response)
+			}
+		}
+	}
+}
+
+// helper function, the accounts of the tokens have no role within the organisations and get the default one
+func withoutMembership(mockRepo *mocks.MockRepository) {
+	mockRepo.EXPECT().FindMembership(gomock.Any(), MembershipCollection, gomock.Any(), gomock.Any()).Return(model.MembershipDAO{}, repository.ErrNotFound).AnyTimes()
+}
+
+// helper function, the accounts of the tokens have been given the role within the organisations
+func withMembership(mockRepo *mocks.MockRepository, role string) {
+	mockRepo.EXPECT().FindMembership(gomock.Any(), MembershipCollection, gomock.Any(), gomock.Any()).Return(model.MembershipDAO{Role: role}, nil).AnyTimes()
+}
+
+// helper function, the writes of the tags reserve a sequence of the organisation and complete it once done
+func withSequences(mockRepo *mocks.MockRepository) {
+	mockRepo.EXPECT().ReserveSequence(gomock.Any(), SequenceCollection, gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	mockRepo.EXPECT().CompleteSequence(gomock.Any(), SequenceCollection, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"net/http"
+)
+
+const (
+	HistoryCollection = "history"
+	HistoryTagId      = "tagId"
+	HistoryBefore     = "before"
+	HistoryAfter      = "after"
+)
+
+// @Summary Get the history of the tags
+// @ID get-history
+// @Description Returns the changes made to the tags of the organisation, the most recent first. The changes of the
+// @Description private tags of the other accounts are left out.
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Maximum number of changes returned"
+// @Param cursor query string false "Cursor returned with the previous page"
+// @Success 200 {object} model.HistoryResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/history [get]
+func (handler *TagHandler) GetHistory(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the history for organisationId \"%v\"", organisationId)
+	handler.getHistoryPage(c, bson.M{OrganisationId: organisationId})
+}
+
+// @Summary Get the history of a tag
+// @ID get-tag-history
+// @Description Returns the changes made to the tag, the most recent first. The history outlives the tag.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tag ID"
+// @Param limit query int false "Maximum number of changes returned"
+// @Param cursor query string false "Cursor returned with the previous page"
+// @Success 200 {object} model.HistoryResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/{id}/history [get]
+func (handler *TagHandler) GetTagHistory(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(TagId)
+	logger.Info.Printf("Received request to retrieve the history of tag \"%v\" for organisationId \"%v\"", id, organisationId)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
+	handler.getHistoryPage(c, bson.M{OrganisationId: organisationId, HistoryTagId: oid})
+}
+
+// Writes one page of the audit records matching the query, the most recent first. The changes of the tags which were
+// not visible to the account of the request before or after the change are left out.
+func (handler *TagHandler) getHistoryPage(c *gin.Context, query bson.M) {
+	readableVersions(c, query, HistoryBefore, HistoryAfter)
+
+	limit, err := queryLimit(c)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	// resume after the last record of the previous page
+	if cursor := c.Query(CursorParam); cursor != "" {
+		after, err := decodeHistoryCursor(cursor)
+		if err != nil {
+			setErrorResponse("invalid cursor", http.StatusBadRequest, c)
+			return
+		}
+		query["_id"] = bson.M{"$lt": after}
+	}
+
+	// fetch one extra record to know whether there is a next page
+	records, err := handler.tenant(c).FindHistory(DatabaseName, HistoryCollection, query, limit+1)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+
+	nextCursor := ""
+	if len(records) > limit {
+		records = records[:limit]
+		nextCursor = encodeHistoryCursor(records[limit-1].Id)
+	}
+	response := model.ConvertHistory(records)
+	response.NextCursor = nextCursor
+	c.JSON(http.StatusOK, response)
+}
+
+// Builds the audit record of a change made by the account of the request.
+func historyOf(c *gin.Context, operation string, before *model.TagDAO, after *model.TagDAO) model.HistoryDAO {
+	return model.NewHistory(c.Request.Header.Get(AccountIDField), operation, before, after)
+}
+
+// Writes the audit records of the changes made by the request, and moves the events announcing them, which were stored
+// along with the tags, to the outbox. The changes have already been applied, so failing to record them is logged rather
+// than reported to the client, the relay moving the events left in the tags.
+func (handler *TagHandler) recordChanges(c *gin.Context, records ...model.HistoryDAO) {
+	if len(records) == 0 {
+		return
+	}
+	docs := make([]interface{}, len(records))
+	for i := range records {
+		docs[i] = &records[i]
+	}
+	failed, err := handler.tenant(c).BulkInsert(DatabaseName, HistoryCollection, docs)
+	if err != nil {
+		logger.Error.Printf("Failed to record the history of %d tags: %v", len(records), err.Error())
+	}
+	for p, err := range failed {
+		logger.Error.Printf("Failed to record the history of tag \"%v\": %v", records[p].TagId.Hex(), err.Error())
+	}
+	pending := make([]model.EventDAO, len(records))
+	for i, record := range records {
+		pending[i] = model.NewEvent(record)
+	}
+	if err := moveEvents(handler.tenant(c), pending); err != nil {
+		logger.Error.Printf("Failed to move the events of %d tags to the outbox: %v", len(records), err.Error())
+	}
+}
+
+// Encodes the id of the last record of a page into an opaque cursor.
+func encodeHistoryCursor(id bson.ObjectId) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// Decodes a cursor created by encodeHistoryCursor.
+func decodeHistoryCursor(cursor string) (bson.ObjectId, error) {
+	content, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil && len(content) != 12 {
+		err = errors.New("invalid cursor")
+	}
+	return bson.ObjectId(content), err
+}
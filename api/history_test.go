@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Renames, recolours and deletes a tag then reads its history one page at a time.
+func TestGetTagHistory(t *testing.T) {
+
+	t.Logf("Given I create, rename, recolour and delete a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		organisationId := bson.NewObjectId().Hex()
+		giveRole(organisationId, test.AccountID1, model.RoleEditor)
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, organisationId)
+		name := test.UniqueName("Supper")
+		colour := "Blue"
+		for _, body := range []model.PatchTagRequest{{Name: &name}, {Colour: &colour}} {
+			req, _ := test.HttpRequest(body, "/tags/"+id, http.MethodPatch, test.Token1, organisationId)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+		req, _ := test.HttpRequest(nil, "/tags/"+id, http.MethodDelete, test.Token1, organisationId)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		t.Logf("\tWhen Sending Get tag history request to endpoint:  \"%s\"", "\\tags\\"+id+"\\history?limit=2")
+		{
+			first := getHistory(router, "/tags/"+id+"/history?limit=2", organisationId, t)
+			second := getHistory(router, "/tags/"+id+"/history?limit=2&cursor="+first.NextCursor, organisationId, t)
+
+			entries := append(first.Entries, second.Entries...)
+			operations := make([]string, len(entries))
+			for i, entry := range entries {
+				operations[i] = entry.Operation
+			}
+			expected := []string{model.OperationDelete, model.OperationUpdate, model.OperationUpdate, model.OperationCreate}
+			if len(operations) == 4 && operations[0] == expected[0] && operations[1] == expected[1] && operations[2] == expected[2] && operations[3] == expected[3] && second.NextCursor == "" {
+				t.Logf("\t\tThe history should list the changes, the most recent first. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe history should list the changes, the most recent first:  \"%v\". %v", operations, test.BallotX)
+			}
+
+			if len(entries) == 4 && entries[2].Changes[0] == "name" && entries[2].Before.Name != entries[2].After.Name && entries[1].After.Colour == colour && entries[0].AccountId != "" {
+				t.Logf("\t\tThe history should hold the snapshots and the actor of each change. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe history should hold the snapshots and the actor of each change:  \"%v\". %v", entries, test.BallotX)
+			}
+		}
+	}
+}
+
+// Reads the history of all the tags of an organisation.
+func TestGetHistory(t *testing.T) {
+
+	t.Logf("Given I create two tags in a new organisation")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		organisationId := bson.NewObjectId().Hex()
+		giveRole(organisationId, test.AccountID1, model.RoleEditor)
+		test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, organisationId)
+		test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Lunch"), Colour: "Red"}, router, t, test.Token1, organisationId)
+
+		t.Logf("\tWhen Sending Get history request to endpoint:  \"%s\"", "\\tags\\history")
+		{
+			response := getHistory(router, "/tags/history", organisationId, t)
+			if len(response.Entries) == 2 && response.Entries[0].Operation == model.OperationCreate {
+				t.Logf("\t\tThe history should list the creation of both tags. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe history should list the creation of both tags:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// helper function
+func getHistory(router *gin.Engine, url string, orgId string, t *testing.T) model.HistoryResponse {
+	req, _ := test.HttpRequest(nil, url, http.MethodGet, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusOK)
+
+	var response model.HistoryResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	return response
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// Indexes backing the queries of the handlers, keyed by collection.
+var indexes = map[string][]mgo.Index{
+	DatabaseCollection: {
+		// the pages of the tags sorted by name or by creation, the tags visible to the account being those of the whole
+		// organisation but the private tags of the other accounts
+		{Key: []string{OrganisationId, TagName, "_id"}},
+		{Key: []string{OrganisationId, "_id"}},
+		{Key: []string{OrganisationId, ParentId}},
+		// tags created before names were normalised have no normalised name until BackfillNormalisedNames is run, and the
+		// tags in the trash have none until they are restored
+		{Key: []string{OrganisationId, NormalisedName}, Unique: true, PartialFilter: bson.M{NormalisedName: bson.M{"$exists": true}}},
+		{Key: []string{DeletedAt}, Sparse: true},
+		{Key: []string{OrganisationId, DeletedAt, "_id"}, PartialFilter: bson.M{DeletedAt: bson.M{"$exists": true}}},
+		{Key: []string{OrganisationId, Sequence}},
+		{Key: []string{OrganisationId, TagKey}, Sparse: true},
+		// the tags whose events are still to be moved to the outbox, the oldest event first
+		{Key: []string{PendingEvents + "._id"}, PartialFilter: bson.M{PendingEvents + ".0": bson.M{"$exists": true}}},
+	},
+	KeyCollection: {
+		{Key: []string{OrganisationId, TagKey}, Unique: true},
+	},
+	HistoryCollection: {
+		{Key: []string{OrganisationId, "_id"}},
+		{Key: []string{OrganisationId, HistoryTagId, "_id"}},
+	},
+	OutboxCollection: {
+		{Key: []string{PublishedAt, "_id"}},
+		{Key: []string{OrganisationId, "_id"}},
+	},
+	WebhookCollection: {
+		{Key: []string{OrganisationId}},
+	},
+	// an event published again by the relay is only delivered once to each webhook
+	DeliveryCollection: {
+		{Key: []string{WebhookId, DeliveryEventId}, Unique: true},
+		{Key: []string{WebhookId, "_id"}},
+		{Key: []string{DeliveryStatus, NextAttemptAt}},
+	},
+	SequenceCollection: {
+		{Key: []string{OrganisationId}, Unique: true},
+	},
+	MembershipCollection: {
+		{Key: []string{OrganisationId, AccountId}, Unique: true},
+	},
+	AssignmentCollection: {
+		{Key: []string{OrganisationId, ResourceType, ResourceId, AssignmentTagId}, Unique: true},
+		{Key: []string{AssignmentTagId}},
+	},
+}
+
+// EnsureIndexes creates the indexes backing the queries of the handlers. Existing indexes are left untouched.
+func (handler *TagHandler) EnsureIndexes() error {
+	for collection, collectionIndexes := range indexes {
+		for _, index := range collectionIndexes {
+			if err := handler.unscoped.EnsureIndex(DatabaseName, collection, index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
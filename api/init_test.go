@@ -2,11 +2,17 @@ package api
 
 import (
 	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
 	"github.com/globalsign/mgo/dbtest"
+	"github.com/tag-service/events"
+	"github.com/tag-service/model"
 	"github.com/tag-service/repository"
+	"github.com/tag-service/test"
 	"io/ioutil"
+	"net"
 	"os"
 	"testing"
+	"time"
 )
 
 const (
@@ -34,6 +40,17 @@ func TestMain(m *testing.M) {
 
 	Repository = &repository.MongoRepository{Session}
 
+	// the test webhooks listen on the loopback address
+	webhookAddressAllowed = func(ip net.IP) bool { return ip.IsLoopback() || events.PublicAddress(ip) }
+	NewTagHandler(Repository).EnsureIndexes()
+
+	// the test tokens carry no role, their accounts edit the tags of the shared organisations
+	for _, orgId := range []string{test.OrgID1, test.OrgID2} {
+		for _, accountId := range []string{test.AccountID1, test.AccountID2, test.AccountID3} {
+			giveRole(orgId, accountId, model.RoleEditor)
+		}
+	}
+
 	// Run the test suite
 	retCode := m.Run()
 
@@ -50,3 +67,9 @@ func TestMain(m *testing.M) {
 	// call with result of m.Run()
 	os.Exit(retCode)
 }
+
+// helper function, gives the account the role within the organisation as PUT /members does
+func giveRole(orgId string, accountId string, role string) {
+	query := bson.M{OrganisationId: orgId, AccountId: accountId}
+	Repository.Upsert(DatabaseName, MembershipCollection, query, bson.M{"$set": bson.M{MemberRole: role, UpdatedAt: time.Now()}})
+}
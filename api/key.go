@@ -0,0 +1,310 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	KeyCollection          = "keys"
+	TagKey                 = "key"
+	TagValue               = "value"
+	KeyParam               = "key"
+	ValueParam             = "value"
+	KeyConflictMessage     = "The key already exists"
+	KeyInUseMessage        = "The key is used by tags"
+	KeyedNameMessage       = "The name of a key/value tag is made of its key and value, change its value instead"
+	ValueWithoutKeyMessage = "only a key/value tag has a value"
+)
+
+// @Summary Define a key
+// @ID create-key
+// @Description Defines a key of the key/value tags of the organisation. The values of an enum key are restricted to
+// @Description the given ones, the values of a free-form key are not.
+// @Accept  json
+// @Produce  json
+// @Param key body model.CreateKeyRequest true "New key"
+// @Success 201 {object} model.Key "Key created"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 409 {object} model.ErrorResponse "The key already exists"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /keys [post]
+func (handler *TagHandler) CreateKey(c *gin.Context) {
+	var req model.CreateKeyRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to define key \"%v\" for organisationId \"%v\"", req.Key, organisationId)
+
+	if err := model.ValidateKey(req.Key); err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+	values, err := model.NewKeyValues(req.Type, req.Values)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	key := model.KeyDAO{Id: bson.NewObjectId(), OrganisationId: organisationId, Key: req.Key, Type: req.Type, Values: values, CreatedAt: time.Now()}
+	err = handler.tenant(c).Insert(DatabaseName, KeyCollection, &key)
+	if errors.Is(err, repository.ErrConflict) {
+		setErrorResponse(KeyConflictMessage, http.StatusConflict, c)
+		return
+	}
+	if err != nil {
+		setRepositoryError(err, "Insert failed", c)
+		return
+	}
+	c.JSON(http.StatusCreated, model.ConvertKey(key))
+}
+
+// @Summary Get the keys
+// @ID get-keys
+// @Description Returns the keys of the key/value tags of the organisation, sorted by key
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.GetKeysResponse "ok"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /keys [get]
+func (handler *TagHandler) GetKeys(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the keys for organisationId \"%v\"", organisationId)
+
+	keys, err := handler.tenant(c).FindKeys(DatabaseName, KeyCollection, bson.M{OrganisationId: organisationId})
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.ConvertKeys(keys))
+}
+
+// @Summary Replace a key
+// @ID update-key
+// @Description Replaces the type and values of the key. The values used by tags must remain allowed.
+// @Accept  json
+// @Produce  json
+// @Param key path string true "Key"
+// @Param definition body model.UpdateKeyRequest true "Updated key"
+// @Success 200 {object} model.Key "Key updated"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 404 {object} model.EmptyBody "Key not found"
+// @Failure 409 {object} model.ErrorResponse "Tags use values which would no longer be allowed"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /keys/{key} [put]
+func (handler *TagHandler) UpdateKey(c *gin.Context) {
+	var req model.UpdateKeyRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	name := c.Params.ByName(KeyParam)
+	logger.Info.Printf("Received request to update key \"%v\" for organisationId \"%v\"", name, organisationId)
+
+	values, err := model.NewKeyValues(req.Type, req.Values)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+	key, ok := handler.findKey(c, organisationId, name)
+	if !ok {
+		return
+	}
+	key.Type = req.Type
+	key.Values = values
+
+	// the tags keep their value, which must still be allowed
+	tags, ok := handler.organisationTags(c, organisationId)
+	if !ok {
+		return
+	}
+	disallowed := make([]string, 0)
+	for _, tag := range tags {
+		if _, err := key.Allow(tag.Value); tag.Key == key.Key && err != nil {
+			disallowed = append(disallowed, tag.Value)
+		}
+	}
+	if len(disallowed) > 0 {
+		setErrorResponse("Tags use values which would no longer be allowed: "+strings.Join(disallowed, ", "), http.StatusConflict, c)
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"type": key.Type, "values": key.Values}}
+	if key.Values == nil {
+		update = bson.M{"$set": bson.M{"type": key.Type}, "$unset": bson.M{"values": ""}}
+	}
+	if err := handler.tenant(c).UpdateOne(DatabaseName, KeyCollection, bson.M{OrganisationId: organisationId, TagKey: key.Key}, update); err != nil {
+		setRepositoryError(err, "Update failed", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.ConvertKey(key))
+}
+
+// @Summary Delete a key
+// @ID delete-key
+// @Description Removes the key, which may not be used by any tag
+// @Accept  json
+// @Produce  json
+// @Param key path string true "Key"
+// @Success 204 "Key deleted"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 404 {object} model.EmptyBody "Key not found"
+// @Failure 409 {object} model.ErrorResponse "The key is used by tags"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /keys/{key} [delete]
+func (handler *TagHandler) DeleteKey(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	name := c.Params.ByName(KeyParam)
+	logger.Info.Printf("Received request to delete key \"%v\" for organisationId \"%v\"", name, organisationId)
+
+	count, err := handler.tenant(c).Count(DatabaseName, DatabaseCollection, live(bson.M{OrganisationId: organisationId, TagKey: name}))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	if count > 0 {
+		setErrorResponse(KeyInUseMessage, http.StatusConflict, c)
+		return
+	}
+
+	err = handler.tenant(c).RemoveOne(DatabaseName, KeyCollection, bson.M{OrganisationId: organisationId, TagKey: name})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, model.EmptyBody{})
+		return
+	}
+	if err != nil {
+		setRepositoryError(err, "Delete failed", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Queries the definition of the key within the organisation. The error response is written when it is not found.
+func (handler *TagHandler) findKey(c *gin.Context, organisationId string, name string) (model.KeyDAO, bool) {
+	keys, err := handler.organisationKeys(c, organisationId)
+	if err != nil {
+		return model.KeyDAO{}, false
+	}
+	key, ok := keys[name]
+	if !ok {
+		c.JSON(http.StatusNotFound, model.EmptyBody{})
+	}
+	return key, ok
+}
+
+// Returns the key definitions of the organisation by key. The error response is written when they cannot be read.
+func (handler *TagHandler) organisationKeys(c *gin.Context, organisationId string) (map[string]model.KeyDAO, error) {
+	keys, err := handler.tenant(c).FindKeys(DatabaseName, KeyCollection, bson.M{OrganisationId: organisationId})
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return nil, err
+	}
+	byKey := make(map[string]model.KeyDAO)
+	for _, key := range keys {
+		byKey[key.Key] = key
+	}
+	return byKey, nil
+}
+
+// Resolves the name and value of a tag to create: the given name for a plain tag, key:value for a key/value tag
+// whose value must be allowed by the definition of the key.
+func keyedTag(keys map[string]model.KeyDAO, key string, value string, name string) (string, string, error) {
+	if key == "" {
+		if value != "" {
+			return "", "", errors.New(ValueWithoutKeyMessage)
+		}
+		return name, "", nil
+	}
+	definition, ok := keys[key]
+	if !ok {
+		return "", "", fmt.Errorf("unknown key: %v", key)
+	}
+	value, err := definition.Allow(value)
+	if err != nil {
+		return "", "", err
+	}
+	keyed := model.KeyedName(key, value)
+	if name != "" && model.NameKey(name) != model.NameKey(keyed) {
+		return "", "", errors.New(KeyedNameMessage)
+	}
+	return keyed, value, nil
+}
+
+// Applies the name or value of a patch to the tag and adds the updated fields. Only a key/value tag has a value, and
+// its name follows the value.
+func renameTag(keys map[string]model.KeyDAO, tag *model.TagDAO, name *string, value *string, fields bson.M) error {
+	if tag.Key == "" {
+		if value != nil {
+			return errors.New(ValueWithoutKeyMessage)
+		}
+		if name != nil {
+			tag.Name = model.NormaliseName(*name)
+			tag.NormalisedName = model.NameKey(*name)
+			fields[TagName] = tag.Name
+			fields[NormalisedName] = tag.NormalisedName
+		}
+		return nil
+	}
+
+	newValue, newName := tag.Value, ""
+	if value != nil {
+		newValue = *value
+	}
+	if name != nil {
+		newName = *name
+	}
+	keyed, newValue, err := keyedTag(keys, tag.Key, newValue, newName)
+	if err != nil {
+		return err
+	}
+	tag.Name = model.NormaliseName(keyed)
+	tag.NormalisedName = model.NameKey(keyed)
+	tag.Value = newValue
+	fields[TagName] = tag.Name
+	fields[NormalisedName] = tag.NormalisedName
+	fields[TagValue] = tag.Value
+	return nil
+}
+
+// Restricts the query to the tags with the key given as parameter, and the value if given. The error response is
+// written when the parameters are not valid.
+func keyFilter(c *gin.Context, query bson.M) bool {
+	key, value := c.Query(KeyParam), c.Query(ValueParam)
+	if key == "" {
+		if value != "" {
+			setErrorResponse("the value parameter needs a key", http.StatusBadRequest, c)
+			return false
+		}
+		return true
+	}
+	query[TagKey] = key
+	if value != "" {
+		query[NormalisedName] = model.NameKey(model.KeyedName(key, value))
+	}
+	return true
+}
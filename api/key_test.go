@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Defines an enum key and creates key/value tags with it.
+func TestCreateKeyValueTag(t *testing.T) {
+
+	t.Logf("Given the key env with the values prod and staging")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		w := keyRequest(router, model.CreateKeyRequest{Key: "env", Type: model.KeyEnum, Values: []string{"prod", "staging"}}, "/keys", http.MethodPost, orgId)
+		test.CheckStatus(w, t, http.StatusCreated)
+
+		t.Logf("\tWhen creating a tag with the key env and the value PROD")
+		{
+			id := test.CreateTag(model.CreateTagRequest{Key: "env", Value: "PROD", Colour: "Red"}, router, t, test.Token1, orgId)
+			checkStoredTag(router, id, model.Tag{Id: id, Name: "env:prod", Colour: "Red", AccountId: test.AccountID1, OrganisationId: orgId, Visibility: model.VisibilityOrganisation, Key: "env", Value: "prod"}, t)
+		}
+
+		t.Logf("\tWhen creating a tag with a value which is not allowed")
+		{
+			req, _ := test.HttpRequest(model.CreateTagRequest{Key: "env", Value: "dev"}, "/tags", http.MethodPost, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+
+		t.Logf("\tWhen creating a tag with an unknown key")
+		{
+			req, _ := test.HttpRequest(model.CreateTagRequest{Key: "team", Value: "payments"}, "/tags", http.MethodPost, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+
+		t.Logf("\tWhen defining the key again")
+		{
+			w := keyRequest(router, model.CreateKeyRequest{Key: "env", Type: model.KeyFreeForm}, "/keys", http.MethodPost, orgId)
+			test.CheckStatus(w, t, http.StatusConflict)
+		}
+	}
+}
+
+// Filters the tags and the resources by key and by key and value.
+func TestFilterByKey(t *testing.T) {
+
+	t.Logf("Given the tags team:payments, team:search and a plain tag, all assigned to a resource")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		w := keyRequest(router, model.CreateKeyRequest{Key: "team", Type: model.KeyFreeForm}, "/keys", http.MethodPost, orgId)
+		test.CheckStatus(w, t, http.StatusCreated)
+		payments := test.CreateTag(model.CreateTagRequest{Key: "team", Value: "payments", Colour: "Red"}, router, t, test.Token1, orgId)
+		search := test.CreateTag(model.CreateTagRequest{Key: "team", Value: "search", Colour: "Red"}, router, t, test.Token1, orgId)
+		plain := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+		assign(router, "/resources/note/1/tags", []string{payments}, orgId, t)
+		assign(router, "/resources/note/2/tags", []string{search}, orgId, t)
+		assign(router, "/resources/note/3/tags", []string{plain}, orgId, t)
+
+		t.Logf("\tWhen retrieving the tags with the key team")
+		{
+			tags := filterTags(router, "/tags?key=team", orgId, t)
+			if len(tags.Tags) == 2 {
+				t.Logf("\t\tOnly the key/value tags should be returned. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tOnly the key/value tags should be returned:  \"%v\". %v", tags, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen retrieving the tags with the key team and the value payments")
+		{
+			tags := filterTags(router, "/tags?key=team&value=Payments", orgId, t)
+			if len(tags.Tags) == 1 && tags.Tags[0].Id == payments {
+				t.Logf("\t\tOnly the tag with the value should be returned. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tOnly the tag with the value should be returned:  \"%v\". %v", tags, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen finding the resources tagged with the key team")
+		{
+			req, _ := test.HttpRequest(nil, "/resources?key=team", http.MethodGet, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.FindResourcesResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if len(response.Resources) == 2 {
+				t.Logf("\t\tThe resources with a tag of the key should be returned. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe resources with a tag of the key should be returned:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// Changes the value of a key/value tag and checks the key cannot be removed or restricted while in use.
+func TestUpdateKeyValueTag(t *testing.T) {
+
+	t.Logf("Given the tag env:prod")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		w := keyRequest(router, model.CreateKeyRequest{Key: "env", Type: model.KeyFreeForm}, "/keys", http.MethodPost, orgId)
+		test.CheckStatus(w, t, http.StatusCreated)
+		id := test.CreateTag(model.CreateTagRequest{Key: "env", Value: "prod", Colour: "Red"}, router, t, test.Token1, orgId)
+
+		t.Logf("\tWhen patching the value of the tag")
+		{
+			value := "staging"
+			w := keyRequest(router, model.PatchTagRequest{Value: &value}, "/tags/"+id, http.MethodPatch, orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			checkStoredTag(router, id, model.Tag{Id: id, Name: "env:staging", Colour: "Red", AccountId: test.AccountID1, OrganisationId: orgId, Visibility: model.VisibilityOrganisation, Key: "env", Value: "staging"}, t)
+		}
+
+		t.Logf("\tWhen renaming the tag")
+		{
+			name := "production"
+			w := keyRequest(router, model.PatchTagRequest{Name: &name}, "/tags/"+id, http.MethodPatch, orgId)
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+
+		t.Logf("\tWhen restricting the key to values the tag does not use")
+		{
+			w := keyRequest(router, model.UpdateKeyRequest{Type: model.KeyEnum, Values: []string{"prod"}}, "/keys/env", http.MethodPut, orgId)
+			test.CheckStatus(w, t, http.StatusConflict)
+		}
+
+		t.Logf("\tWhen deleting the key")
+		{
+			w := keyRequest(router, nil, "/keys/env", http.MethodDelete, orgId)
+			test.CheckStatus(w, t, http.StatusConflict)
+		}
+	}
+}
+
+// Only a key/value tag has a value.
+func TestKeyedTag(t *testing.T) {
+	keys := map[string]model.KeyDAO{"env": {Key: "env", Type: model.KeyEnum, Values: []string{"Prod"}}}
+	tests := []struct {
+		key, value, name string
+		expectedName     string
+		ok               bool
+	}{
+		{"", "", "Dinner", "Dinner", true},
+		{"", "prod", "Dinner", "", false},
+		{"env", "prod", "", "env:Prod", true},
+		{"env", "prod", "env:prod", "env:Prod", true},
+		{"env", "prod", "Dinner", "", false},
+		{"env", "dev", "", "", false},
+		{"team", "payments", "", "", false},
+	}
+
+	t.Logf("Given the key env allowing the value Prod")
+	for _, tt := range tests {
+		t.Logf("\tWhen resolving the name of the tag with key %q, value %q and name %q", tt.key, tt.value, tt.name)
+		name, _, err := keyedTag(keys, tt.key, tt.value, tt.name)
+		if (err == nil) == tt.ok && name == tt.expectedName {
+			t.Logf("\t\tThe name should be %q. %v", tt.expectedName, test.CheckMark)
+		} else {
+			t.Errorf("\t\tThe name should be %q: %q, %v. %v", tt.expectedName, name, err, test.BallotX)
+		}
+	}
+}
+
+// helper function
+func keyRequest(router *gin.Engine, body interface{}, url string, method string, orgId string) *httptest.ResponseRecorder {
+	req, _ := test.HttpRequest(body, url, method, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// helper function
+func filterTags(router *gin.Engine, url string, orgId string, t *testing.T) model.GetAllTagResponse {
+	req, _ := test.HttpRequest(nil, url, http.MethodGet, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusOK)
+
+	var response model.GetAllTagResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	return response
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/events"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	cfg "github.com/tag-service/vault"
+	"time"
+)
+
+const (
+	OutboxCollection       = "outbox"
+	PublishedAt            = "publishedAt"
+	PendingEvents          = "pendingEvents"
+	LeaseCollection        = "leases"
+	RelayLease             = "relay"
+	RelayLeaseDuration     = 30 * time.Second
+	EventPublisherEnv      = "EVENT_PUBLISHER"
+	EventPublisherFallback = events.NDJSONPublisherKind
+	EventsFileEnv          = "EVENTS_FILE"
+	EventsFileFallback     = "events.ndjson"
+	RelayInterval          = time.Second
+	RelayBatchSize         = 100
+)
+
+// The writes moving the events to the outbox, made by the requests within their organisation and by the relay across
+// the organisations.
+type eventWriter interface {
+	BulkInsert(database string, collection string, docs []interface{}) (map[int]error, error)
+	UpdateAll(database string, collection string, query bson.M, update interface{}) error
+}
+
+// Adds the event announcing the recorded change to the update of the tag, so that the event is stored by the same write
+// as the change. The event waits in the tag until it is moved to the outbox.
+func withEvent(update bson.M, record model.HistoryDAO) bson.M {
+	update["$push"] = bson.M{PendingEvents: model.NewEvent(record)}
+	return update
+}
+
+// Returns the tag to insert along with the event announcing its creation.
+func withCreationEvent(tag model.TagDAO, record model.HistoryDAO) *model.PendingTagDAO {
+	return &model.PendingTagDAO{TagDAO: tag, PendingEvents: []model.EventDAO{model.NewEvent(record)}}
+}
+
+// Applies the update built for each recorded change to its tag, along with the event announcing the change, as long as
+// the tag still matches the query. Returns the records of the changes which were applied.
+func (handler *TagHandler) writeChanges(c *gin.Context, query bson.M, records []model.HistoryDAO, update func() bson.M) ([]model.HistoryDAO, error) {
+	ids := make([]bson.ObjectId, len(records))
+	updates := make([]interface{}, len(records))
+	for i, record := range records {
+		ids[i] = record.TagId
+		updates[i] = withEvent(update(), record)
+	}
+	failed, err := handler.writeTags(c, query, ids, updates)
+	if err != nil {
+		return nil, err
+	}
+	written := make([]model.HistoryDAO, 0)
+	for i, record := range records {
+		if err, ok := failed[i]; ok {
+			logger.Error.Printf("Failed to change tag \"%v\": %v", record.TagId.Hex(), err.Error())
+			continue
+		}
+		written = append(written, record)
+	}
+	return written, nil
+}
+
+// Moves the events waiting in their tags to the outbox. An event already in the outbox is only removed from its tag,
+// the events which cannot be written stay in their tag until the next attempt.
+func moveEvents(repo eventWriter, pending []model.EventDAO) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(pending))
+	for i := range pending {
+		docs[i] = &pending[i]
+	}
+	failed, err := repo.BulkInsert(DatabaseName, OutboxCollection, docs)
+	if err != nil {
+		return err
+	}
+	tagIds := make([]bson.ObjectId, 0)
+	eventIds := make([]bson.ObjectId, 0)
+	for p, event := range pending {
+		if err, ok := failed[p]; ok && !errors.Is(err, repository.ErrConflict) {
+			logger.Error.Printf("Failed to write the event of tag \"%v\" to the outbox: %v", event.TagId.Hex(), err.Error())
+			continue
+		}
+		tagIds = append(tagIds, event.TagId)
+		eventIds = append(eventIds, event.Id)
+	}
+	if len(eventIds) == 0 {
+		return nil
+	}
+	return repo.UpdateAll(DatabaseName, DatabaseCollection, bson.M{"_id": bson.M{"$in": tagIds}}, bson.M{"$pull": bson.M{PendingEvents: bson.M{"_id": bson.M{"$in": eventIds}}}})
+}
+
+// Moves to the outbox the events the requests have left in their tags, a batch of tags at a time.
+func (handler *TagHandler) moveWaitingEvents() error {
+	tags, err := handler.unscoped.FindPendingTags(DatabaseName, DatabaseCollection, bson.M{PendingEvents + ".0": bson.M{"$exists": true}}, RelayBatchSize)
+	if err != nil {
+		return err
+	}
+	pending := make([]model.EventDAO, 0)
+	for _, tag := range tags {
+		pending = append(pending, tag.PendingEvents...)
+	}
+	return moveEvents(handler.unscoped, pending)
+}
+
+// RelayEvents moves the events left in their tags to the outbox, then publishes the events waiting in the outbox in the
+// order they were written and returns the number of events published. The relay holds a lease while it runs so that
+// the instances of the service take turns, the pass of an instance is skipped while another holds it. It stops at the
+// first event which cannot be published so that the order is kept, the event is published again by the next pass.
+func (handler *TagHandler) RelayEvents(publisher events.Publisher) (int, error) {
+	acquired, err := handler.unscoped.AcquireLease(DatabaseName, LeaseCollection, RelayLease, handler.instance, RelayLeaseDuration)
+	if err != nil || !acquired {
+		return 0, err
+	}
+	defer handler.unscoped.ReleaseLease(DatabaseName, LeaseCollection, RelayLease, handler.instance)
+
+	if err := handler.moveWaitingEvents(); err != nil {
+		return 0, err
+	}
+	published := 0
+	for {
+		pending, err := handler.unscoped.FindEvents(DatabaseName, OutboxCollection, bson.M{PublishedAt: bson.M{"$exists": false}}, RelayBatchSize)
+		if err != nil {
+			return published, err
+		}
+		for _, event := range pending {
+			if err := publisher.Publish(model.ConvertEvent(event)); err != nil {
+				return published, err
+			}
+			// the event is published again if it cannot be marked, publishers must cope with duplicates anyway
+			if err := handler.unscoped.Update(DatabaseName, OutboxCollection, event.Id, bson.M{"$set": bson.M{PublishedAt: time.Now()}}); err != nil {
+				return published, err
+			}
+			published++
+		}
+		if len(pending) < RelayBatchSize {
+			return published, nil
+		}
+		// the lease is renewed for each batch, the pass stops if another instance has taken it over meanwhile
+		if acquired, err := handler.unscoped.AcquireLease(DatabaseName, LeaseCollection, RelayLease, handler.instance, RelayLeaseDuration); err != nil || !acquired {
+			return published, err
+		}
+	}
+}
+
+// RelayEventsEvery relays the events waiting in the outbox once per interval. It never returns and is meant to run
+// in its own goroutine.
+func (handler *TagHandler) RelayEventsEvery(publisher events.Publisher, interval time.Duration) {
+	for {
+		if _, err := handler.RelayEvents(publisher); err != nil {
+			logger.Error.Printf("Failed to relay the events: %v", err.Error())
+		}
+		time.Sleep(interval)
+	}
+}
+
+// NewEventPublisher returns the publisher configured with the EVENT_PUBLISHER environment variable, memory or ndjson.
+// The ndjson publisher appends the events to the file named by EVENTS_FILE.
+func NewEventPublisher() (events.Publisher, error) {
+	return events.NewPublisher(cfg.GetEnv(EventPublisherEnv, EventPublisherFallback), cfg.GetEnv(EventsFileEnv, EventsFileFallback))
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"github.com/globalsign/mgo/bson"
+	"github.com/golang/mock/gomock"
+	"github.com/tag-service/events"
+	"github.com/tag-service/mocks"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"testing"
+	"time"
+)
+
+// Creates a tag and relays the event written to the outbox.
+func TestRelayEvents(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+
+		t.Logf("\tWhen relaying the events of the outbox")
+		{
+			publisher := events.NewMemoryPublisher()
+			_, err := controller.RelayEvents(publisher)
+			test.Ok(err, t)
+
+			published := 0
+			for _, event := range publisher.Events() {
+				if event.TagId == id && event.Type == model.TagCreated && event.Tag.Id == id {
+					published++
+				}
+			}
+			if published == 1 {
+				t.Logf("\t\tThe creation of the tag should have been published. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe creation of the tag should have been published once:  \"%d\". %v", published, test.BallotX)
+			}
+
+			again := events.NewMemoryPublisher()
+			controller.RelayEvents(again)
+			for _, event := range again.Events() {
+				if event.TagId == id {
+					t.Errorf("\t\tThe event should not have been published again. %v", test.BallotX)
+				}
+			}
+		}
+	}
+}
+
+// The events left in their tag by a request which could not move them are moved to the outbox and published by the relay.
+func TestRelayWaitingEvents(t *testing.T) {
+
+	t.Logf("Given a tag stored along with the event of its creation")
+	{
+		controller := NewTagHandler(Repository)
+		tag := model.TagDAO{Id: bson.NewObjectId(), Name: test.UniqueName("Dinner"), Colour: "Red", OrganisationId: bson.NewObjectId().Hex(), Version: 1}
+		record := model.NewHistory(test.AccountID1, model.OperationCreate, nil, &tag)
+		test.Ok(Repository.Insert(DatabaseName, DatabaseCollection, withCreationEvent(tag, record)), t)
+
+		t.Logf("\tWhen another instance is relaying the events")
+		{
+			_, err := Repository.AcquireLease(DatabaseName, LeaseCollection, RelayLease, "another", time.Minute)
+			test.Ok(err, t)
+			publisher := events.NewMemoryPublisher()
+			published, err := controller.RelayEvents(publisher)
+			Repository.ReleaseLease(DatabaseName, LeaseCollection, RelayLease, "another")
+			if published == 0 && err == nil && len(publisher.Events()) == 0 {
+				t.Logf("\t\tThe pass should have been skipped. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe pass should have been skipped:  \"%d\". %v", published, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen relaying the events")
+		{
+			publisher := events.NewMemoryPublisher()
+			_, err := controller.RelayEvents(publisher)
+			test.Ok(err, t)
+
+			published := 0
+			for _, event := range publisher.Events() {
+				if event.Id == record.Id.Hex() && event.Type == model.TagCreated && event.Tag.Name == tag.Name {
+					published++
+				}
+			}
+			if published == 1 {
+				t.Logf("\t\tThe event left in the tag should have been published. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe event left in the tag should have been published once:  \"%d\". %v", published, test.BallotX)
+			}
+
+			waiting, err := Repository.FindPendingTags(DatabaseName, DatabaseCollection, bson.M{"_id": tag.Id, PendingEvents + ".0": bson.M{"$exists": true}}, 1)
+			if err == nil && len(waiting) == 0 {
+				t.Logf("\t\tThe event should have been removed from the tag. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe event should have been removed from the tag:  \"%v\". %v", waiting, test.BallotX)
+			}
+		}
+	}
+}
+
+// The relay stops at the first event which cannot be published, which stays in the outbox.
+func TestRelayEventsStopsOnFailure(t *testing.T) {
+
+	t.Logf("Given two events waiting in the outbox")
+	{
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockRepo := mocks.NewMockRepository(mockCtrl)
+
+		pending := []model.EventDAO{{Id: bson.NewObjectId(), Type: model.TagCreated}, {Id: bson.NewObjectId(), Type: model.TagDeleted}}
+		mockRepo.EXPECT().AcquireLease(gomock.Any(), LeaseCollection, RelayLease, gomock.Any(), RelayLeaseDuration).Return(true, nil).Times(1)
+		mockRepo.EXPECT().ReleaseLease(gomock.Any(), LeaseCollection, RelayLease, gomock.Any()).Return(nil).Times(1)
+		mockRepo.EXPECT().FindPendingTags(gomock.Any(), DatabaseCollection, gomock.Any(), RelayBatchSize).Return(nil, nil).Times(1)
+		mockRepo.EXPECT().FindEvents(gomock.Any(), OutboxCollection, gomock.Any(), RelayBatchSize).Return(pending, nil).Times(1)
+		mockRepo.EXPECT().Update(gomock.Any(), OutboxCollection, gomock.Any(), gomock.Any()).Times(0)
+
+		t.Logf("\tWhen the publisher fails")
+		{
+			published, err := NewTagHandler(mockRepo).RelayEvents(failingPublisher{})
+			if published == 0 && err != nil {
+				t.Logf("\t\tNo event should have been marked as published. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tNo event should have been marked as published:  \"%d\". %v", published, test.BallotX)
+			}
+		}
+	}
+}
+
+// publisher failing to deliver any event
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(event model.Event) error {
+	return errors.New("broker unavailable")
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	SortParam       = "sort"
+	CountParam      = "count"
+	SortByName      = "name"
+	SortByCreatedAt = "createdAt"
+	SortByDeletedAt = "deletedAt"
+)
+
+// Position of the last tag of a page, the sort is part of the cursor so that it cannot be resumed in another order.
+type tagCursor struct {
+	Sort      string        `json:"s"`
+	Name      string        `json:"n,omitempty"`
+	DeletedAt *time.Time    `json:"d,omitempty"`
+	Id        bson.ObjectId `json:"id"`
+}
+
+// Writes one page of the tags matching the query.
+func (handler *TagHandler) getTagsPage(c *gin.Context, query bson.M) {
+	sort := c.DefaultQuery(SortParam, SortByName)
+	if strings.TrimPrefix(sort, "-") != SortByName && strings.TrimPrefix(sort, "-") != SortByCreatedAt {
+		setErrorResponse("sort must be one of name, -name, createdAt or -createdAt", http.StatusBadRequest, c)
+		return
+	}
+
+	results, nextCursor, ok := handler.findTagsPage(c, query, sort)
+	if !ok {
+		return
+	}
+	response := model.Convert(localise(c, results))
+	response.NextCursor = nextCursor
+
+	if c.Query(CountParam) == "true" {
+		total, err := handler.tenant(c).Count(DatabaseName, DatabaseCollection, query)
+		if err != nil {
+			setRepositoryError(err, "Failed to retrieve data from the database", c)
+			return
+		}
+		response.Total = &total
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Queries one page of the tags matching the query in the given sort, and returns them together with the cursor of the
+// next page, empty on the last one. Pages are delimited by the sort key of the last tag rather than skipped over, so
+// that each page is served from the index however deep the client pages. The error response is written when the
+// paging parameters are invalid or the tags cannot be read.
+func (handler *TagHandler) findTagsPage(c *gin.Context, query bson.M, sort string) ([]model.TagDAO, string, bool) {
+	limit, err := queryLimit(c)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return nil, "", false
+	}
+
+	pageQuery := query
+	if cursor := c.Query(CursorParam); cursor != "" {
+		after, err := decodeTagCursor(cursor)
+		if err != nil || after.Sort != sort {
+			setErrorResponse("invalid cursor", http.StatusBadRequest, c)
+			return nil, "", false
+		}
+		pageQuery = afterCursor(query, after)
+	}
+
+	// fetch one extra tag to know whether there is a next page
+	results, err := handler.tenant(c).FindPage(DatabaseName, DatabaseCollection, pageQuery, sortFields(sort), limit+1)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return nil, "", false
+	}
+
+	if len(results) <= limit {
+		return results, "", true
+	}
+	results = results[:limit]
+	last := results[limit-1]
+	return results, encodeTagCursor(tagCursor{Sort: sort, Name: last.Name, DeletedAt: last.DeletedAt, Id: last.Id}), true
+}
+
+// Checks whether any of the paging parameters is set.
+func pagingRequested(c *gin.Context) bool {
+	for _, param := range []string{LimitParam, CursorParam, SortParam, CountParam} {
+		if c.Query(param) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the sort fields for the given sort. Object ids start with their creation time so creation order is the id
+// order, and the id breaks ties between tags with the same name.
+func sortFields(sort string) []string {
+	direction := ""
+	if strings.HasPrefix(sort, "-") {
+		direction = "-"
+	}
+	switch strings.TrimPrefix(sort, "-") {
+	case SortByCreatedAt:
+		return []string{direction + "_id"}
+	case SortByDeletedAt:
+		return []string{direction + DeletedAt, direction + "_id"}
+	}
+	return []string{direction + TagName, direction + "_id"}
+}
+
+// Restricts the query to the tags sorted after the cursor.
+func afterCursor(query bson.M, after tagCursor) bson.M {
+	operator := "$gt"
+	if strings.HasPrefix(after.Sort, "-") {
+		operator = "$lt"
+	}
+
+	result := bson.M{}
+	for key, value := range query {
+		result[key] = value
+	}
+	switch strings.TrimPrefix(after.Sort, "-") {
+	case SortByCreatedAt:
+		result["_id"] = bson.M{operator: after.Id}
+		return result
+	case SortByDeletedAt:
+		result["$or"] = []bson.M{
+			{DeletedAt: bson.M{operator: after.DeletedAt}},
+			{DeletedAt: after.DeletedAt, "_id": bson.M{operator: after.Id}},
+		}
+		return result
+	}
+	result["$or"] = []bson.M{
+		{TagName: bson.M{operator: after.Name}},
+		{TagName: after.Name, "_id": bson.M{operator: after.Id}},
+	}
+	return result
+}
+
+// Encodes the cursor into an opaque string.
+func encodeTagCursor(cursor tagCursor) string {
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// Decodes a cursor created by encodeTagCursor.
+func decodeTagCursor(value string) (tagCursor, error) {
+	var cursor tagCursor
+	content, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(content, &cursor)
+	return cursor, err
+}
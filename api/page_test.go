@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Creates three tags and pages through them by name.
+func TestQueryPagesSortedByName(t *testing.T) {
+
+	t.Logf("Given I create three tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		createPagedTags(router, t)
+
+		t.Logf("\tWhen Sending Get tags request to endpoint:  \"%s\"", "\\tags?limit=2&sort=name&count=true")
+		{
+			first := queryPage(router, "/tags?limit=2&sort=name&count=true", t)
+			checkPage(first, []string{"Alpha", "Bravo"}, true, t)
+			if first.Total != nil && *first.Total == 3 {
+				t.Logf("\t\tThe response should contain the total number of tags. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe response should contain the total number of tags. %v %v", test.BallotX, first.Total)
+			}
+
+			second := queryPage(router, "/tags?limit=2&sort=name&cursor="+first.NextCursor, t)
+			checkPage(second, []string{"Charlie"}, false, t)
+		}
+	}
+}
+
+// Creates three tags and pages through them newest first.
+func TestQueryPagesSortedByCreationDescending(t *testing.T) {
+
+	t.Logf("Given I create three tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		createPagedTags(router, t)
+
+		t.Logf("\tWhen Sending Get tags request to endpoint:  \"%s\"", "\\tags?limit=1&sort=-createdAt")
+		{
+			first := queryPage(router, "/tags?limit=1&sort=-createdAt", t)
+			checkPage(first, []string{"Bravo"}, true, t)
+
+			second := queryPage(router, "/tags?limit=1&sort=-createdAt&cursor="+first.NextCursor, t)
+			checkPage(second, []string{"Alpha"}, true, t)
+		}
+	}
+}
+
+// Attempt to resume a page with a different sort results in bad request.
+func TestQueryPageWithMismatchingCursor(t *testing.T) {
+
+	t.Logf("Given I create three tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		createPagedTags(router, t)
+		first := queryPage(router, "/tags?limit=1&sort=name", t)
+
+		t.Logf("\tWhen Sending Get tags request with a cursor of another sort to endpoint:  \"%s\"", "\\tags")
+		{
+			req, err := test.HttpRequest(nil, "/tags?limit=1&sort=createdAt&cursor="+first.NextCursor, http.MethodGet, test.Token3, test.OrgID2)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+	}
+}
+
+// helper function, the tags are created once for the account of Token3 within OrgID2
+func createPagedTags(router *gin.Engine, t *testing.T) {
+	if page := queryPage(router, "/tags?limit=1", t); len(page.Tags) > 0 {
+		return
+	}
+	for _, name := range []string{"Charlie", "Alpha", "Bravo"} {
+		test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red"}, router, t, test.Token3, test.OrgID2)
+	}
+}
+
+// helper function
+func queryPage(router *gin.Engine, url string, t *testing.T) model.GetAllTagResponse {
+	req, _ := test.HttpRequest(nil, url, http.MethodGet, test.Token3, test.OrgID2)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusOK)
+
+	var response model.GetAllTagResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	return response
+}
+
+// helper function
+func checkPage(page model.GetAllTagResponse, names []string, hasNext bool, t *testing.T) {
+	actual := make([]string, 0)
+	for _, tag := range page.Tags {
+		actual = append(actual, tag.Name)
+	}
+	if len(actual) == len(names) && (page.NextCursor != "") == hasNext {
+		for i := range names {
+			if actual[i] != names[i] {
+				t.Errorf("\t\tThe page should contain the tags %v. %v %v", names, test.BallotX, actual)
+				return
+			}
+		}
+		t.Logf("\t\tThe page should contain the tags %v. %v", names, test.CheckMark)
+	} else {
+		t.Errorf("\t\tThe page should contain the tags %v. %v %v", names, test.BallotX, page)
+	}
+}
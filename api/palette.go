@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+)
+
+const PaletteCollection = "palettes"
+
+// @Summary Get the colour palette
+// @ID get-palette
+// @Description Returns the colours the tags of the organisation may use. The default palette is returned until the
+// @Description organisation defines its own, any colour is allowed in that case.
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.Palette "ok"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /palette [get]
+func (handler *TagHandler) GetPalette(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the palette for organisationId \"%v\"", organisationId)
+
+	palette, ok := handler.findPalette(c, organisationId)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, palette)
+}
+
+// @Summary Define the colour palette
+// @ID update-palette
+// @Description Replaces the colours the tags of the organisation may use. Existing tags keep their colour.
+// @Accept  json
+// @Produce  json
+// @Param palette body model.UpdatePaletteRequest true "Palette colours"
+// @Success 200 {object} model.Palette "Palette updated"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /palette [put]
+func (handler *TagHandler) UpdatePalette(c *gin.Context) {
+	var req model.UpdatePaletteRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to update the palette for organisationId \"%v\"", organisationId)
+
+	palette, err := model.NewPalette(req.Colours)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	err = handler.tenant(c).Upsert(DatabaseName, PaletteCollection, bson.M{OrganisationId: organisationId}, bson.M{"$set": bson.M{"colours": palette.Colours}})
+	if err != nil {
+		setRepositoryError(err, "Update failed", c)
+		return
+	}
+	c.JSON(http.StatusOK, palette)
+}
+
+// Queries the palette of the organisation, falling back to the default palette. The error response is written when
+// the palette cannot be read.
+func (handler *TagHandler) findPalette(c *gin.Context, organisationId string) (model.Palette, bool) {
+	result, err := handler.tenant(c).FindPalette(DatabaseName, PaletteCollection)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.DefaultPalette, true
+	}
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return model.Palette{}, false
+	}
+	return model.Palette{Colours: result.Colours, Custom: true}, true
+}
+
+// Resolves the colour of a tag with the palette of the organisation. The error response is written when the colour is
+// not valid.
+func (handler *TagHandler) resolveColour(c *gin.Context, organisationId string, colour string, name string) (string, bool) {
+	palette, ok := handler.findPalette(c, organisationId)
+	if !ok {
+		return "", false
+	}
+	resolved, err := tagColour(palette, colour, name)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return "", false
+	}
+	return resolved, true
+}
+
+// Returns the canonical form of the colour of a tag, or the palette colour picked from the tag name when no colour is
+// given.
+func tagColour(palette model.Palette, colour string, name string) (string, error) {
+	if colour == "" {
+		return palette.ColourFor(name), nil
+	}
+	return palette.Resolve(colour)
+}
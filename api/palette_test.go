@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Creates a tag without colour and checks a palette colour has been picked.
+func TestCreateTagWithoutColour(t *testing.T) {
+
+	t.Logf("Given the tag service is up and running")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		t.Logf("\tWhen Sending Create request without colour to endpoint:  \"%s\"", "\\tags")
+		{
+			name := test.UniqueName("Dinner")
+			id := test.CreateTag(model.CreateTagRequest{Name: name}, router, t, test.Token2, test.OrgID1)
+
+			expected := model.DefaultPalette.ColourFor(name)
+			checkStoredTag(router, id, model.Tag{Id: id, Name: name, Colour: expected, AccountId: test.AccountID2, OrganisationId: test.OrgID1, Visibility: model.VisibilityOrganisation}, t)
+		}
+	}
+}
+
+// Creates a tag with a colour value and checks it has been normalised.
+func TestCreateTagNormalisesColour(t *testing.T) {
+
+	t.Logf("Given the tag service is up and running")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		t.Logf("\tWhen Sending Create request with an rgb colour to endpoint:  \"%s\"", "\\tags")
+		{
+			name := test.UniqueName("Dinner")
+			id := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "rgb(18, 52, 86)"}, router, t, test.Token2, test.OrgID1)
+
+			checkStoredTag(router, id, model.Tag{Id: id, Name: name, Colour: "#123456", AccountId: test.AccountID2, OrganisationId: test.OrgID1, Visibility: model.VisibilityOrganisation}, t)
+		}
+	}
+}
+
+// Attempt to create a tag with an invalid colour results in bad request.
+func TestCreateTagWithInvalidColour(t *testing.T) {
+
+	t.Logf("Given the tag service is up and running")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		t.Logf("\tWhen Sending Create request with an invalid colour to endpoint:  \"%s\"", "\\tags")
+		{
+			body := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "rgb(300, 0, 0)"}
+			req, err := test.HttpRequest(body, "/tags", http.MethodPost, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			expectedResponse := model.ErrorResponse{Code: http.StatusBadRequest, Message: "invalid colour: rgb(300, 0, 0)"}
+
+			// check body response
+			test.CheckResponseMessage(response, expectedResponse, t, w)
+		}
+	}
+}
+
+// Defines the palette of an organisation and checks only its colours are allowed.
+func TestUpdatePaletteSuccess(t *testing.T) {
+
+	t.Logf("Given an organisation without palette")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		organisationId := bson.NewObjectId().Hex()
+		giveRole(organisationId, test.AccountID2, model.RoleAdmin)
+
+		t.Logf("\tWhen Sending Update palette request to endpoint:  \"%s\"", "\\palette")
+		{
+			body := model.UpdatePaletteRequest{Colours: []model.PaletteColour{{Name: "Brand", Hex: "#123"}, {Name: "Accent", Hex: "rgb(255, 0, 0)"}}}
+			req, err := test.HttpRequest(body, "/palette", http.MethodPut, test.Token2, organisationId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusOK)
+
+			req, _ = test.HttpRequest(nil, "/palette", http.MethodGet, test.Token2, organisationId)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var palette model.Palette
+			json.NewDecoder(w.Body).Decode(&palette)
+			if palette.Custom && len(palette.Colours) == 2 && palette.Colours[0] == (model.PaletteColour{Name: "Brand", Hex: "#112233"}) {
+				t.Logf("\t\tThe palette should have been stored. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe palette should have been stored:  \"%v\". %v", palette, test.BallotX)
+			}
+
+			// colours outside of the palette are rejected
+			req, _ = test.HttpRequest(model.CreateTagRequest{Name: "Dinner", Colour: "Blue"}, "/tags", http.MethodPost, test.Token2, organisationId)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusBadRequest)
+
+			id := test.CreateTag(model.CreateTagRequest{Name: "Dinner", Colour: "#ff0000"}, router, t, test.Token2, organisationId)
+			checkStoredTag(router, id, model.Tag{Id: id, Name: "Dinner", Colour: "Accent", AccountId: test.AccountID2, OrganisationId: organisationId, Visibility: model.VisibilityOrganisation}, t)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/tag-service/test"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
@@ -18,12 +19,12 @@ func TestQueryAllSuccess(t *testing.T) {
 		controller := NewTagHandler(Repository)
 		router := controller.CreateRouter()
 		//
-		tag1 := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
+		tag1 := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
 		tag2 := model.CreateTagRequest{Name: "Flight", Colour: "Black"}
 		id1 := test.CreateTag(tag1, router, t, test.Token2, test.OrgID2)
 		id2 := test.CreateTag(tag2, router, t, test.Token2, test.OrgID2)
-		expectedTags := map[string]model.Tag{id1: model.Tag{Id: id1, Name: tag1.Name, Colour: tag1.Colour, AccountId: test.AccountID2, OrganisationId: test.OrgID2},
-			id2: model.Tag{Id: id2, Name: tag2.Name, Colour: tag2.Colour, AccountId: test.AccountID2, OrganisationId: test.OrgID2}}
+		expectedTags := map[string]model.Tag{id1: model.Tag{Id: id1, Name: tag1.Name, Colour: tag1.Colour, AccountId: test.AccountID2, OrganisationId: test.OrgID2, Visibility: model.VisibilityOrganisation},
+			id2: model.Tag{Id: id2, Name: tag2.Name, Colour: tag2.Colour, AccountId: test.AccountID2, OrganisationId: test.OrgID2, Visibility: model.VisibilityOrganisation}}
 
 		t.Logf("\tWhen Sending Get All tags request to endpoint:  \"%s\"", "\\tags")
 		{
@@ -49,14 +50,14 @@ func TestQueryAllSuccess(t *testing.T) {
 
 // Query all tags, it should return none.
 func TestQueryAllNoTagFound(t *testing.T) {
-	t.Logf("Given no tags were created for the given user")
+	t.Logf("Given no tags were created for the organisation")
 	{
 		controller := NewTagHandler(Repository)
 		router := controller.CreateRouter()
 
 		t.Logf("\tWhen Sending Get All tags request to endpoint:  \"%s\"", "\\tags")
 		{
-			req, _ := test.HttpRequest(nil, "/tags", http.MethodGet, test.Token3, test.OrgID1)
+			req, _ := test.HttpRequest(nil, "/tags", http.MethodGet, test.Token3, bson.NewObjectId().Hex())
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
@@ -84,7 +85,7 @@ func TestQueryForGivenTagIdSuccess(t *testing.T) {
 		controller := NewTagHandler(Repository)
 		router := controller.CreateRouter()
 
-		tag := model.CreateTagRequest{Name: "Dinner", Colour: "Red"}
+		tag := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
 		id := test.CreateTag(tag, router, t, test.Token2, test.OrgID2)
 
 		t.Logf("\tWhen Sending Get tag request to endpoint:  \"%s\"", "\\tags\\"+id)
@@ -103,10 +104,10 @@ func TestQueryForGivenTagIdSuccess(t *testing.T) {
 
 			// accountId for token two
 			accountId := "5a3922ac86da0c1d779a776"
-			expectedResponse := model.Tag{Id: id, Name: tag.Name, Colour: tag.Colour, AccountId: accountId, OrganisationId: test.OrgID2}
+			expectedResponse := model.Tag{Id: id, Name: tag.Name, Colour: tag.Colour, AccountId: accountId, OrganisationId: test.OrgID2, Visibility: model.VisibilityOrganisation}
 
 			// check body response
-			if expectedResponse == response {
+			if reflect.DeepEqual(expectedResponse, response) {
 				t.Logf("\t\tTag should have been found:  \"%s\". %v", expectedResponse, test.CheckMark)
 			} else {
 				t.Errorf("\t\tTag should have been found:  \"%s\". %v", response, test.BallotX)
@@ -140,7 +141,7 @@ func TestQueryForGivenTagIdNotFound(t *testing.T) {
 
 // helper function
 func checkRetrievedTag(expectedTags map[string]model.Tag, id string, response model.GetAllTagResponse, t *testing.T) {
-	if expectedTags[id] == getCreatedTags(id, response.Tags) {
+	if reflect.DeepEqual(expectedTags[id], getCreatedTags(id, response.Tags)) {
 		t.Logf("\t\tTag [\"%s\"] has been retrieved successfully:  \"%s\". %v", id, getCreatedTags(id, response.Tags), test.CheckMark)
 	} else {
 		t.Logf("\t\tTag [\"%s\"] has been retrieved successfully:  \"%s\". %v", id, getCreatedTags(id, response.Tags), test.BallotX)
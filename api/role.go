@@ -0,0 +1,191 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	cfg "github.com/tag-service/vault"
+	"net/http"
+	"time"
+)
+
+const (
+	MembershipCollection = "memberships"
+	MemberRole           = "role"
+	MemberAccountParam   = "accountId"
+	RoleClaimEnv         = "ROLE_CLAIM"
+	RoleClaimFallback    = "http://asto.co.uk/roles"
+	DefaultRoleEnv       = "DEFAULT_ROLE"
+	// the accounts without a role can only read the tags, writing them or changing the settings of the organisation
+	// needs a role given explicitly
+	DefaultRoleFallback = model.RoleViewer
+	// the key under which the JWT middleware saves the token it has verified
+	JWTTokenKey = "JWT_TOKEN"
+)
+
+// @Summary Get the roles of the accounts
+// @ID get-members
+// @Description Returns the accounts given a role within the organisation. The other accounts get their role from
+// @Description their token or the default role.
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.GetMembersResponse "ok"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /members [get]
+func (handler *TagHandler) GetMembers(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the members for organisationId \"%v\"", organisationId)
+
+	memberships, err := handler.tenant(c).FindMemberships(DatabaseName, MembershipCollection)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.ConvertMembers(memberships))
+}
+
+// @Summary Give a role to an account
+// @ID update-member
+// @Description Gives the account the viewer, editor or admin role within the organisation. The role is used when the
+// @Description token of the account carries none.
+// @Accept  json
+// @Produce  json
+// @Param accountId path string true "Account ID"
+// @Param member body model.UpdateMemberRequest true "Role"
+// @Success 200 {object} model.Member "Role updated"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /members/{accountId} [put]
+func (handler *TagHandler) UpdateMember(c *gin.Context) {
+	var req model.UpdateMemberRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+	if err := model.ValidateRole(req.Role); err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	accountId := c.Params.ByName(MemberAccountParam)
+	logger.Info.Printf("Received request to make account \"%v\" %v for organisationId \"%v\"", accountId, req.Role, organisationId)
+
+	query := bson.M{OrganisationId: organisationId, AccountId: accountId}
+	if err := handler.tenant(c).Upsert(DatabaseName, MembershipCollection, query, bson.M{"$set": bson.M{MemberRole: req.Role, UpdatedAt: time.Now()}}); err != nil {
+		setRepositoryError(err, "Update failed", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.Member{AccountId: accountId, Role: req.Role})
+}
+
+// @Summary Remove the role of an account
+// @ID delete-member
+// @Description The account gets its role from its token or the default role again.
+// @Accept  json
+// @Produce  json
+// @Param accountId path string true "Account ID"
+// @Success 204 "Role removed"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 404 {object} model.EmptyBody "The account has no role"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /members/{accountId} [delete]
+func (handler *TagHandler) DeleteMember(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	accountId := c.Params.ByName(MemberAccountParam)
+	logger.Info.Printf("Received request to remove the role of account \"%v\" for organisationId \"%v\"", accountId, organisationId)
+
+	err := handler.tenant(c).RemoveOne(DatabaseName, MembershipCollection, bson.M{OrganisationId: organisationId, AccountId: accountId})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, model.EmptyBody{})
+		return
+	}
+	if err != nil {
+		setRepositoryError(err, "Delete failed", c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Guards a route with the given permission. The request is rejected with 403 when the role of the account does not
+// grant it.
+func (handler *TagHandler) require(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, err := handler.accountRole(c)
+		if err != nil {
+			setRepositoryError(err, "Failed to read the role of the account", c)
+			c.Abort()
+			return
+		}
+		if !model.Grants(role, permission) {
+			message := fmt.Sprintf("The %v role does not grant the %v permission", role, permission)
+			logger.Error.Println(message)
+			setErrorResponse(message, http.StatusForbidden, c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Returns the role of the account of the request: the one carried by its token, else the one it was given within the
+// organisation, else the default role.
+func (handler *TagHandler) accountRole(c *gin.Context) (string, error) {
+	if role := model.HighestRole(claimedRoles(c)); role != "" {
+		return role, nil
+	}
+	membership, err := handler.tenant(c).FindMembership(DatabaseName, MembershipCollection)
+	if err == nil {
+		return membership.Role, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return "", err
+	}
+	role := cfg.GetEnv(DefaultRoleEnv, DefaultRoleFallback)
+	if err := model.ValidateRole(role); err != nil {
+		return "", fmt.Errorf("invalid default role: %v", role)
+	}
+	return role, nil
+}
+
+// Returns the roles carried by the claim configured with ROLE_CLAIM, given as a string or a list of strings. The claims
+// are read from the token saved by the JWT middleware once it has verified it, never from the headers of the request.
+func claimedRoles(c *gin.Context) []string {
+	claim := cfg.GetEnv(RoleClaimEnv, RoleClaimFallback)
+	value, _ := c.Get(JWTTokenKey)
+	token, _ := value.(string)
+	if claim == "" || token == "" {
+		return nil
+	}
+	claims := jwtgo.MapClaims{}
+	if _, _, err := new(jwtgo.Parser).ParseUnverified(token, claims); err != nil {
+		return nil
+	}
+
+	switch value := claims[claim].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		names := make([]string, 0)
+		for _, v := range value {
+			if name, ok := v.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
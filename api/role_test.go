@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// Gives roles to the second account and checks the routes are guarded by the permissions they grant.
+func TestRolePermissions(t *testing.T) {
+
+	t.Logf("Given an organisation where the first account is an admin")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		id := test.CreateTag(model.CreateTagRequest{Name: "Dinner", Colour: "Red"}, router, t, test.Token1, orgId)
+
+		t.Logf("\tWhen the second account is made a viewer")
+		{
+			body := model.UpdateMemberRequest{Role: model.RoleViewer}
+			test.CheckStatus(visibilityRequest(router, "/members/"+test.AccountID2, http.MethodPut, body, test.Token1, orgId), t, http.StatusOK)
+
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id, http.MethodGet, nil, test.Token2, orgId), t, http.StatusOK)
+			w := visibilityRequest(router, "/tags/"+id, http.MethodDelete, nil, test.Token2, orgId)
+			test.CheckStatus(w, t, http.StatusForbidden)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Message == "The viewer role does not grant the tags:write permission" {
+				t.Logf("\t\tThe reason should be given. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe reason should be given:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen the second account is made an editor")
+		{
+			body := model.UpdateMemberRequest{Role: model.RoleEditor}
+			test.CheckStatus(visibilityRequest(router, "/members/"+test.AccountID2, http.MethodPut, body, test.Token1, orgId), t, http.StatusOK)
+
+			colour := "Blue"
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id, http.MethodPatch, model.PatchTagRequest{Colour: &colour}, test.Token2, orgId), t, http.StatusOK)
+			test.CheckStatus(visibilityRequest(router, "/schema", http.MethodPut, model.UpdateSchemaRequest{}, test.Token2, orgId), t, http.StatusForbidden)
+			test.CheckStatus(visibilityRequest(router, "/members/"+test.AccountID1, http.MethodPut, model.UpdateMemberRequest{Role: model.RoleAdmin}, test.Token2, orgId), t, http.StatusForbidden)
+		}
+
+		t.Logf("\tWhen the role of the second account is removed")
+		{
+			test.CheckStatus(visibilityRequest(router, "/members/"+test.AccountID2, http.MethodDelete, nil, test.Token1, orgId), t, http.StatusNoContent)
+			test.CheckStatus(visibilityRequest(router, "/members/"+test.AccountID2, http.MethodDelete, nil, test.Token1, orgId), t, http.StatusNotFound)
+			test.CheckStatus(visibilityRequest(router, "/members/"+test.AccountID2, http.MethodPut, model.UpdateMemberRequest{Role: "owner"}, test.Token1, orgId), t, http.StatusBadRequest)
+		}
+	}
+}
+
+// An account given no role falls back to the viewer role when DEFAULT_ROLE is not set.
+func TestDefaultRoleFallback(t *testing.T) {
+
+	t.Logf("Given DEFAULT_ROLE is not set")
+	{
+		defaultRole := os.Getenv(DefaultRoleEnv)
+		os.Unsetenv(DefaultRoleEnv)
+		defer os.Setenv(DefaultRoleEnv, defaultRole)
+
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		id := test.CreateTag(model.CreateTagRequest{Name: "Dinner", Colour: "Red"}, router, t, test.Token1, orgId)
+
+		t.Logf("\tWhen an account without a role sends requests")
+		{
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id, http.MethodGet, nil, test.Token2, orgId), t, http.StatusOK)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id, http.MethodDelete, nil, test.Token2, orgId), t, http.StatusForbidden)
+			w := visibilityRequest(router, "/tags", http.MethodPost, model.CreateTagRequest{Name: "Lunch", Colour: "Red"}, test.Token2, orgId)
+			test.CheckStatus(w, t, http.StatusForbidden)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Message == "The viewer role does not grant the tags:write permission" {
+				t.Logf("\t\tThe account should have the viewer role. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe account should have the viewer role:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// The roles are read from the token verified by the JWT middleware, not from the headers of the request.
+func TestClaimedRoles(t *testing.T) {
+
+	t.Logf("Given a verified token carrying the editor role and a header carrying the admin role")
+	{
+		verified, _ := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, jwtgo.MapClaims{RoleClaimFallback: []string{model.RoleViewer, model.RoleEditor}}).SignedString([]byte("secret"))
+		forged, _ := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, jwtgo.MapClaims{RoleClaimFallback: model.RoleAdmin}).SignedString([]byte("forged"))
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/tags", nil)
+		c.Request.Header.Set("X-JWT-Assertion", forged)
+		c.Request.Header.Set("Authorization", "Bearer "+forged)
+
+		t.Logf("\tWhen reading the roles of the request")
+		{
+			if roles := claimedRoles(c); len(roles) == 0 {
+				t.Logf("\t\tNo role should be read before the token is verified. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tNo role should be read before the token is verified:  \"%v\". %v", roles, test.BallotX)
+			}
+
+			c.Set(JWTTokenKey, verified)
+			if role := model.HighestRole(claimedRoles(c)); role == model.RoleEditor {
+				t.Logf("\t\tThe role should be read from the verified token. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe role should be read from the verified token:  \"%v\". %v", role, test.BallotX)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+	"strings"
+)
+
+const (
+	SchemaCollection = "schemas"
+	TagAttributes    = "attributes"
+)
+
+// @Summary Get the attribute schema
+// @ID get-schema
+// @Description Returns the attribute fields the tags of the organisation may carry. Until the organisation defines its
+// @Description own schema, any attribute is allowed.
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.Schema "ok"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /schema [get]
+func (handler *TagHandler) GetSchema(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the schema for organisationId \"%v\"", organisationId)
+
+	schema, ok := handler.findSchema(c, organisationId)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
+// @Summary Define the attribute schema
+// @ID update-schema
+// @Description Replaces the attribute fields the tags of the organisation may carry: their type, whether they are
+// @Description required and the maximum length of the strings. The tags are validated against it when next written.
+// @Accept  json
+// @Produce  json
+// @Param schema body model.UpdateSchemaRequest true "Attribute fields"
+// @Success 200 {object} model.Schema "Schema updated"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /schema [put]
+func (handler *TagHandler) UpdateSchema(c *gin.Context) {
+	var req model.UpdateSchemaRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to update the schema for organisationId \"%v\"", organisationId)
+
+	schema, err := model.NewSchema(req.Fields, req.AdditionalFields)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	err = handler.tenant(c).Upsert(DatabaseName, SchemaCollection, bson.M{OrganisationId: organisationId}, bson.M{"$set": bson.M{"fields": schema.Fields, "additionalFields": schema.AdditionalFields}})
+	if err != nil {
+		setRepositoryError(err, "Update failed", c)
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
+// Queries the schema of the organisation, falling back to the default schema. The error response is written when the
+// schema cannot be read.
+func (handler *TagHandler) findSchema(c *gin.Context, organisationId string) (model.Schema, bool) {
+	result, err := handler.tenant(c).FindSchema(DatabaseName, SchemaCollection)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.DefaultSchema, true
+	}
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return model.Schema{}, false
+	}
+	return model.Schema{Fields: result.Fields, AdditionalFields: result.AdditionalFields, Custom: true}, true
+}
+
+// Validates the attributes of a tag with the schema of the organisation. The error response, listing the invalid
+// fields, is written when they are not valid.
+func (handler *TagHandler) validateAttributes(c *gin.Context, organisationId string, attributes map[string]interface{}) bool {
+	schema, ok := handler.findSchema(c, organisationId)
+	if !ok {
+		return false
+	}
+	if errs := schema.Validate(attributes); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Message: model.InvalidAttributesMessage, Code: http.StatusBadRequest, Errors: errs})
+		return false
+	}
+	return true
+}
+
+// Returns the result of a batch item whose attributes are not valid.
+func attributesFailure(index int, errs []model.FieldError) model.BatchResult {
+	result := batchFailure(index, http.StatusBadRequest, model.InvalidAttributesMessage)
+	result.Errors = errs
+	return result
+}
+
+// Describes the invalid fields in one message, e.g. "invalid attributes: owner is required".
+func describeFieldErrors(errs []model.FieldError) string {
+	descriptions := make([]string, len(errs))
+	for i, err := range errs {
+		descriptions[i] = err.Field + " " + err.Message
+	}
+	return model.InvalidAttributesMessage + ": " + strings.Join(descriptions, ", ")
+}
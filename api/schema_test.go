@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Defines the attribute schema of an organisation and checks the attributes of the tags are validated against it.
+func TestTagAttributes(t *testing.T) {
+
+	t.Logf("Given a schema with a required owner of up to 10 characters and an integer priority")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		schema := model.UpdateSchemaRequest{Fields: map[string]model.AttributeField{
+			"owner":    {Type: model.FieldString, Required: true, MaxLength: 10},
+			"priority": {Type: model.FieldInteger},
+		}}
+		req, _ := test.HttpRequest(schema, "/schema", http.MethodPut, test.Token1, orgId)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		test.CheckStatus(w, t, http.StatusOK)
+
+		t.Logf("\tWhen creating a tag with invalid attributes")
+		{
+			body := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red", Attributes: map[string]interface{}{"priority": "high", "icon": "star"}}
+			req, _ := test.HttpRequest(body, "/tags", http.MethodPost, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusBadRequest)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			expected := []model.FieldError{
+				{Field: "icon", Message: "is not defined by the schema"},
+				{Field: "owner", Message: "is required"},
+				{Field: "priority", Message: "must be an integer"},
+			}
+			if response.Message == model.InvalidAttributesMessage && reflect.DeepEqual(response.Errors, expected) {
+				t.Logf("\t\tEach invalid field should be reported. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tEach invalid field should be reported:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen creating a tag with valid attributes and patching them")
+		{
+			name := test.UniqueName("Dinner")
+			attributes := map[string]interface{}{"owner": "payments", "priority": float64(1)}
+			id := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red", Attributes: attributes}, router, t, test.Token1, orgId)
+
+			patch := model.PatchTagRequest{Attributes: map[string]interface{}{"priority": nil, "owner": "search"}}
+			req, _ := test.HttpRequest(patch, "/tags/"+id, http.MethodPatch, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusOK)
+			checkStoredTag(router, id, model.Tag{Id: id, Name: name, Colour: "Red", AccountId: test.AccountID1, OrganisationId: orgId, Visibility: model.VisibilityOrganisation, Attributes: map[string]interface{}{"owner": "search"}}, t)
+
+			patch = model.PatchTagRequest{Attributes: map[string]interface{}{"owner": nil}}
+			req, _ = test.HttpRequest(patch, "/tags/"+id, http.MethodPatch, test.Token1, orgId)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+
+		t.Logf("\tWhen creating tags in batch")
+		{
+			items := []model.CreateTagRequest{
+				{Name: test.UniqueName("Dinner"), Colour: "Red", Attributes: map[string]interface{}{"owner": "payments"}},
+				{Name: test.UniqueName("Lunch"), Colour: "Red", Attributes: map[string]interface{}{"owner": "a very long owner"}},
+			}
+			response := batchAs(router, "/tags/batchCreate", model.BatchCreateRequest{Tags: items}, orgId, t)
+			checkBatchStatuses(response, []int{http.StatusCreated, http.StatusBadRequest}, t)
+			if len(response.Results) == 2 && len(response.Results[1].Errors) == 1 && response.Results[1].Errors[0].Field == "owner" {
+				t.Logf("\t\tThe invalid field should be reported for the item. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe invalid field should be reported for the item:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// helper function
+func batchAs(router http.Handler, url string, body interface{}, orgId string, t *testing.T) model.BatchResponse {
+	req, _ := test.HttpRequest(body, url, http.MethodPost, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusOK)
+
+	var response model.BatchResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	return response
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/search"
+	"net/http"
+)
+
+const SearchQueryParam = "q"
+
+// @Summary Search tags
+// @ID search-tags
+// @Description Returns the tags of the organisation whose name matches the query, most relevant first: exact matches,
+// @Description then prefix, word prefix, substring and finally approximate matches tolerating typos.
+// @Description Case, accents and character width are ignored. The translations of the names are searched as well.
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Search text"
+// @Param limit query int false "Maximum number of tags returned"
+// @Param Accept-Language header string false "Preferred languages of the tag names"
+// @Success 200 {object} model.GetAllTagResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/search [get]
+func (handler *TagHandler) SearchTags(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	text := c.Query(SearchQueryParam)
+	logger.Info.Printf("Received request to search tags matching \"%v\" for organisationId \"%v\"", text, organisationId)
+
+	if search.Fold(text) == "" {
+		setErrorResponse("q may not be empty", http.StatusBadRequest, c)
+		return
+	}
+
+	limit, err := queryLimit(c)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, readable(c, live(bson.M{OrganisationId: organisationId})))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+
+	// the translated names match as well as the default name
+	candidates := make([][]string, len(tags))
+	for i, tag := range tags {
+		candidates[i] = []string{tag.Name}
+		for _, name := range tag.Translations {
+			candidates[i] = append(candidates[i], name)
+		}
+	}
+	matches := make([]model.TagDAO, 0)
+	for _, result := range search.Rank(text, candidates, limit) {
+		matches = append(matches, tags[result.Index])
+	}
+	c.JSON(http.StatusOK, model.Convert(localise(c, matches)))
+}
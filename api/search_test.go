@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"testing"
+)
+
+// Searches the tags of an organisation ignoring case and accents.
+func TestSearchTagsSuccess(t *testing.T) {
+
+	t.Logf("Given I create three tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		cafe := test.CreateTag(model.CreateTagRequest{Name: "Café Visits", Colour: "Brown"}, router, t, test.Token1, test.OrgID2)
+		cafeteria := test.CreateTag(model.CreateTagRequest{Name: "Cafeteria", Colour: "Brown"}, router, t, test.Token1, test.OrgID2)
+		test.CreateTag(model.CreateTagRequest{Name: "Restaurant", Colour: "Red"}, router, t, test.Token1, test.OrgID2)
+
+		t.Logf("\tWhen Sending Search tags request to endpoint:  \"%s\"", "\\tags\\search")
+		{
+			req, err := test.HttpRequest(nil, "/tags/search?q="+neturl.QueryEscape("CAFE"), http.MethodGet, test.Token1, test.OrgID2)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.GetAllTagResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if len(response.Tags) >= 2 && response.Tags[0].Id == cafe && response.Tags[1].Id == cafeteria {
+				t.Logf("\t\tThe matching tags should be returned by relevance. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe matching tags should be returned by relevance:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// Attempt to search with an empty query results in bad request.
+func TestSearchTagsWithEmptyQuery(t *testing.T) {
+
+	t.Logf("Given the tag service is up and running")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		t.Logf("\tWhen Sending Search tags request with no query to endpoint:  \"%s\"", "\\tags\\search")
+		{
+			req, err := test.HttpRequest(nil, "/tags/search?q=", http.MethodGet, test.Token1, test.OrgID2)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			expectedResponse := model.ErrorResponse{Code: http.StatusBadRequest, Message: "q may not be empty"}
+
+			// check body response
+			test.CheckResponseMessage(response, expectedResponse, t, w)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"net/http"
+)
+
+// @Summary Get the usage of the tags
+// @ID get-stats
+// @Description Returns the number of assignments of the tags of the organisation, the most used tags first, and the
+// @Description tags which have never been assigned
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Maximum number of most used tags returned"
+// @Success 200 {object} model.StatsResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/stats [get]
+func (handler *TagHandler) GetStats(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the usage of the tags for organisationId \"%v\"", organisationId)
+
+	limit, err := queryLimit(c)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, readable(c, live(bson.M{OrganisationId: organisationId})))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	// the assignments of the tags in the trash or not visible to the account are counted too, they are left out when
+	// combined with the tags
+	usage, err := handler.tenant(c).UsageByTag(DatabaseName, AssignmentCollection, bson.M{OrganisationId: organisationId})
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.ConvertStats(tags, usage, limit))
+}
+
+// @Summary Get the usage of a tag
+// @ID get-tag-stats
+// @Description Returns the number of assignments of the tag, in total and per resource type, the most used type first
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} model.TagStatsResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Invalid tag id"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 404 {object} model.EmptyBody "Tag not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/{id}/stats [get]
+func (handler *TagHandler) GetTagStats(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(TagId)
+	logger.Info.Printf("Received request to retrieve the usage of tag \"%v\" for organisationId \"%v\"", id, organisationId)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
+
+	tag, ok := handler.findOrganisationTag(c, oid, organisationId, ReadAccess)
+	if !ok {
+		return
+	}
+
+	usage, err := handler.tenant(c).UsageByResourceType(DatabaseName, AssignmentCollection, bson.M{OrganisationId: organisationId, AssignmentTagId: oid})
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	if usage == nil {
+		usage = make([]model.ResourceTypeUsage, 0)
+	}
+	c.JSON(http.StatusOK, model.TagStatsResponse{TagStats: model.NewTagStats(tag, usage), ResourceTypes: usage})
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Assigns tags to resources and reads the usage of the tags.
+func TestGetStats(t *testing.T) {
+
+	t.Logf("Given three tags, two of which are assigned")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		most := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+		less := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Lunch"), Colour: "Red"}, router, t, test.Token1, orgId)
+		unused := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Brunch"), Colour: "Red"}, router, t, test.Token1, orgId)
+		assign(router, "/resources/note/"+bson.NewObjectId().Hex()+"/tags", []string{most, less}, orgId, t)
+		assign(router, "/resources/task/"+bson.NewObjectId().Hex()+"/tags", []string{most}, orgId, t)
+
+		t.Logf("\tWhen retrieving the usage of the tags")
+		{
+			req, _ := test.HttpRequest(nil, "/tags/stats", http.MethodGet, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.StatsResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.TagCount == 3 && response.AssignmentCount == 3 && len(response.MostUsed) == 2 &&
+				response.MostUsed[0].Id == most && response.MostUsed[0].Count == 2 && response.MostUsed[0].LastUsedAt != nil {
+				t.Logf("\t\tThe most used tag should come first. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe most used tag should come first:  \"%v\". %v", response, test.BallotX)
+			}
+			if len(response.Unused) == 1 && response.Unused[0].Id == unused {
+				t.Logf("\t\tThe unused tag should be listed. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe unused tag should be listed:  \"%v\". %v", response.Unused, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen retrieving the usage of the most used tag")
+		{
+			req, _ := test.HttpRequest(nil, "/tags/"+most+"/stats", http.MethodGet, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.TagStatsResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Id == most && response.Count == 2 && len(response.ResourceTypes) == 2 {
+				t.Logf("\t\tThe usage should be given per resource type. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe usage should be given per resource type:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen another organisation retrieves the usage of the tag")
+		{
+			req, _ := test.HttpRequest(nil, "/tags/"+most+"/stats", http.MethodGet, test.Token1, bson.NewObjectId().Hex())
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusNotFound)
+		}
+	}
+}
+
+func assign(router *gin.Engine, resource string, tagIds []string, orgId string, t *testing.T) {
+	req, _ := test.HttpRequest(model.AssignTagsRequest{TagIds: tagIds}, resource, http.MethodPost, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusNoContent)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	LastEventIdHeader  = "Last-Event-ID"
+	StreamPollInterval = time.Second
+	StreamHeartbeat    = 15 * time.Second
+	EventTag           = "tag"
+	// the events are written to the outbox shortly after their id is generated, so an event may land behind one
+	// already streamed. The stream stays this far behind so that such events are not skipped.
+	StreamSettleDelay = 2 * time.Second
+)
+
+// @Summary Stream the tag events
+// @ID stream-events
+// @Description Streams the events of the tags of the organisation as Server-Sent Events, named TagCreated, TagUpdated
+// @Description or TagDeleted. The stream starts with the events occurring after the connection, or after the event
+// @Description given in the Last-Event-ID header when resuming. Events are sent a couple of seconds after they occur.
+// @Description The events of the private tags of the other accounts are not streamed.
+// @Produce  text/event-stream
+// @Param Last-Event-ID header string false "Id of the last event received"
+// @Success 200 {object} model.Event "Stream of events"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Router /tags/events [get]
+func (handler *TagHandler) StreamEvents(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to stream the events for organisationId \"%v\"", organisationId)
+
+	after := bson.NewObjectIdWithTime(time.Now().Add(-StreamSettleDelay))
+	if lastEventId := c.Request.Header.Get(LastEventIdHeader); lastEventId != "" {
+		if !bson.IsObjectIdHex(lastEventId) {
+			setErrorResponse("invalid Last-Event-ID: "+lastEventId, http.StatusBadRequest, c)
+			return
+		}
+		after = bson.ObjectIdHex(lastEventId)
+	}
+
+	// send the headers straight away, the first event may take a while
+	c.Writer.Header().Set(ContentType, sse.ContentType)
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	lastWrite := time.Now()
+	c.Stream(func(w io.Writer) bool {
+		settled := bson.NewObjectIdWithTime(time.Now().Add(-StreamSettleDelay))
+		query := readableVersions(c, bson.M{OrganisationId: organisationId, "_id": bson.M{"$gt": after, "$lt": settled}}, EventTag)
+		pending, err := handler.tenant(c).FindEvents(DatabaseName, OutboxCollection, query, RelayBatchSize)
+		if err != nil {
+			logger.Error.Printf("Failed to stream the events for organisationId \"%v\": %v", organisationId, err.Error())
+			return false
+		}
+		for _, event := range pending {
+			c.Render(-1, sse.Event{Id: event.Id.Hex(), Event: event.Type, Data: model.ConvertEvent(event)})
+			after = event.Id
+			lastWrite = time.Now()
+		}
+		if len(pending) == RelayBatchSize {
+			return true
+		}
+
+		// a comment keeps the connection open through the proxies closing idle ones
+		if time.Since(lastWrite) >= StreamHeartbeat {
+			io.WriteString(w, ":\n\n")
+			lastWrite = time.Now()
+		}
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-time.After(StreamPollInterval):
+			return true
+		}
+	})
+}
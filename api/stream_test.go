@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Resumes the stream after the creation of a tag and receives the creation of the next one.
+func TestStreamEventsResume(t *testing.T) {
+
+	t.Logf("Given two tags created by an organisation")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		server := httptest.NewServer(router)
+		defer server.Close()
+
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+		second := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Supper"), Colour: "Red"}, router, t, test.Token1, orgId)
+		entries := getHistory(router, "/tags/history", orgId, t).Entries
+
+		t.Logf("\tWhen resuming the stream after the first creation")
+		{
+			received := streamEvents(server.URL, entries[len(entries)-1].Id, orgId, 1, t)
+			if len(received) == 1 && received[0].Type == model.TagCreated && received[0].TagId == second && received[0].Id == entries[0].Id {
+				t.Logf("\t\tThe creation of the second tag should have been streamed. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe creation of the second tag should have been streamed:  \"%v\". %v", received, test.BallotX)
+			}
+		}
+	}
+}
+
+// Streams the events occurring after the connection, only for the organisation of the caller.
+func TestStreamEvents(t *testing.T) {
+
+	t.Logf("Given a stream opened by an organisation")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		server := httptest.NewServer(router)
+		defer server.Close()
+
+		orgId := bson.NewObjectId().Hex()
+		otherOrgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		giveRole(otherOrgId, test.AccountID1, model.RoleEditor)
+		done := make(chan []model.Event)
+		go func() { done <- streamEvents(server.URL, "", orgId, 1, t) }()
+
+		t.Logf("\tWhen tags are created by the organisation and another one")
+		{
+			time.Sleep(500 * time.Millisecond)
+			test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, otherOrgId)
+			id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Supper"), Colour: "Red"}, router, t, test.Token1, orgId)
+
+			received := <-done
+			if len(received) == 1 && received[0].TagId == id && received[0].OrganisationId == orgId {
+				t.Logf("\t\tOnly the creation made by the organisation should have been streamed. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tOnly the creation made by the organisation should have been streamed:  \"%v\". %v", received, test.BallotX)
+			}
+		}
+	}
+}
+
+// Reads the stream until the given number of events is received, or gives up after a few seconds.
+func streamEvents(url string, lastEventId string, orgId string, count int, t *testing.T) []model.Event {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, _ := test.HttpRequest(nil, url+"/tags/events", http.MethodGet, test.Token1, orgId)
+	if lastEventId != "" {
+		req.Header.Set(LastEventIdHeader, lastEventId)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Errorf("\t\tThe stream should have been opened:  \"%v\". %v", err, test.BallotX)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	received := make([]model.Event, 0)
+	scanner := bufio.NewScanner(resp.Body)
+	for len(received) < count && scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "data:") {
+			var event model.Event
+			json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event)
+			received = append(received, event)
+		}
+	}
+	return received
+}
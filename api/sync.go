@@ -0,0 +1,228 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+	"time"
+)
+
+const (
+	UpdatedAt               = "updatedAt"
+	Sequence                = "sequence"
+	SequenceCollection      = "sequences"
+	SinceParam              = "since"
+	SyncTokenExpiredMessage = "The sync token has expired, all the tags must be downloaded again"
+	// a write still in flight after this long has failed, the syncs stop waiting for it
+	SequenceTimeout = time.Minute
+)
+
+// @Summary Get the changes made to the tags
+// @ID get-changes
+// @Description Returns the tags created or updated since the sync token, in their current state, and the tags deleted
+// @Description since as tombstones. Without a token all the tags are returned. A change may be returned by two
+// @Description consecutive syncs. The token expires once the deleted tags it would report have been purged. The tags made
+// @Description private by another account since are reported as deleted.
+// @Accept  json
+// @Produce  json
+// @Param since query string false "Sync token returned by the previous sync"
+// @Success 200 {object} model.ChangesResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 410 {object} model.ErrorResponse "The sync token has expired"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/changes [get]
+func (handler *TagHandler) GetChanges(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the changes since \"%v\" for organisationId \"%v\"", c.Query(SinceParam), organisationId)
+
+	// taken before reading so that the changes made during the read are returned again by the next sync
+	now := time.Now()
+	sequence, err := handler.tenant(c).FindSequence(DatabaseName, SequenceCollection)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	query := bson.M{OrganisationId: organisationId}
+	if token := c.Query(SinceParam); token != "" {
+		since, err := decodeSyncToken(token)
+		if errors.Is(err, errSyncTokenFormat) {
+			setErrorResponse(SyncTokenExpiredMessage, http.StatusGone, c)
+			return
+		}
+		if err != nil {
+			setErrorResponse("invalid sync token", http.StatusBadRequest, c)
+			return
+		}
+		retention, err := TrashRetention()
+		if err != nil {
+			setRepositoryError(err, "Failed to read the trash retention", c)
+			return
+		}
+		if since.issuedAt.Before(now.Add(-retention)) {
+			setErrorResponse(SyncTokenExpiredMessage, http.StatusGone, c)
+			return
+		}
+		query[Sequence] = bson.M{"$gt": since.sequence}
+	} else {
+		// the first sync has nothing to delete
+		live(query)
+	}
+
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, query)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	response := model.ConvertChanges(hideUnreadable(c, tags))
+	// the writes still in flight may complete with an earlier sequence than the changes read, the next sync starts
+	// below them
+	response.SyncToken = encodeSyncToken(syncToken{sequence: sequence.Watermark(now.Add(-SequenceTimeout)), issuedAt: now})
+	c.JSON(http.StatusOK, response)
+}
+
+// Position of a sync: the sequence up to which all the writes were read, and the time of the sync, which tells when
+// the token expires.
+type syncToken struct {
+	sequence int64
+	issuedAt time.Time
+}
+
+// errSyncTokenFormat is returned for the tokens holding the time of the sync only, issued before the writes were
+// stamped with a sequence
+var errSyncTokenFormat = errors.New("sync token of a former format")
+
+// Encodes the position of a sync into an opaque token.
+func encodeSyncToken(token syncToken) string {
+	content := make([]byte, 16)
+	binary.BigEndian.PutUint64(content, uint64(token.sequence))
+	binary.BigEndian.PutUint64(content[8:], uint64(token.issuedAt.UnixNano()))
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// Decodes a token created by encodeSyncToken.
+func decodeSyncToken(token string) (syncToken, error) {
+	content, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil && len(content) == 8 {
+		err = errSyncTokenFormat
+	} else if err == nil && len(content) != 16 {
+		err = errors.New("invalid sync token")
+	}
+	if err != nil {
+		return syncToken{}, err
+	}
+	return syncToken{sequence: int64(binary.BigEndian.Uint64(content)), issuedAt: time.Unix(0, int64(binary.BigEndian.Uint64(content[8:])))}, nil
+}
+
+// Sequence reserved for a write of the tags of the organisation of a request, stamped on every tag written
+type sequenceReservation struct {
+	id       bson.ObjectId
+	sequence int64
+}
+
+// Reserves the next sequence of the organisation for a write of its tags. The reservation must be completed once the
+// write is done, whether it succeeded or not.
+func (handler *TagHandler) reserveSequence(c *gin.Context) (sequenceReservation, error) {
+	reservation := sequenceReservation{id: bson.NewObjectId()}
+	sequence, err := handler.tenant(c).ReserveSequence(DatabaseName, SequenceCollection, reservation.id)
+	reservation.sequence = sequence
+	return reservation, err
+}
+
+// Completes the reservation once its write is done, so that the syncs report the changes stamped with the later
+// sequences. A reservation which could not be completed holds the syncs back until it times out.
+func (handler *TagHandler) completeSequence(c *gin.Context, reservation sequenceReservation) {
+	if err := handler.tenant(c).CompleteSequence(DatabaseName, SequenceCollection, reservation.id, time.Now().Add(-SequenceTimeout)); err != nil {
+		logger.Error.Printf("Failed to complete the sequence %d: %v", reservation.sequence, err.Error())
+	}
+}
+
+// Stamps the update of a tag with the sequence of its write.
+func withSequence(update bson.M, sequence int64) bson.M {
+	set, ok := update["$set"].(bson.M)
+	if !ok {
+		set = bson.M{}
+	}
+	set[Sequence] = sequence
+	update["$set"] = set
+	return update
+}
+
+// Inserts a new tag stamped with the sequence of its write.
+func (handler *TagHandler) insertTag(c *gin.Context, tag *model.PendingTagDAO) error {
+	reservation, err := handler.reserveSequence(c)
+	if err != nil {
+		return err
+	}
+	defer handler.completeSequence(c, reservation)
+	tag.Sequence = reservation.sequence
+	return handler.tenant(c).Insert(DatabaseName, DatabaseCollection, tag)
+}
+
+// Inserts the new tags stamped with the sequence of their write. Returns the errors of the tags which could not be
+// inserted, keyed by position.
+func (handler *TagHandler) insertTags(c *gin.Context, tags []*model.PendingTagDAO) (map[int]error, error) {
+	reservation, err := handler.reserveSequence(c)
+	if err != nil {
+		return nil, err
+	}
+	defer handler.completeSequence(c, reservation)
+	docs := make([]interface{}, len(tags))
+	for i, tag := range tags {
+		tag.Sequence = reservation.sequence
+		docs[i] = tag
+	}
+	return handler.tenant(c).BulkInsert(DatabaseName, DatabaseCollection, docs)
+}
+
+// Applies the update to the tag of the given id, stamped with the sequence of its write.
+func (handler *TagHandler) writeTagById(c *gin.Context, id bson.ObjectId, update bson.M) error {
+	reservation, err := handler.reserveSequence(c)
+	if err != nil {
+		return err
+	}
+	defer handler.completeSequence(c, reservation)
+	return handler.tenant(c).Update(DatabaseName, DatabaseCollection, id, withSequence(update, reservation.sequence))
+}
+
+// Applies the updates to the tags of the given ids matching the query, stamped with the sequence of their write. Returns
+// the errors of the tags which could not be updated, keyed by position.
+func (handler *TagHandler) writeTags(c *gin.Context, query bson.M, ids []bson.ObjectId, updates []interface{}) (map[int]error, error) {
+	reservation, err := handler.reserveSequence(c)
+	if err != nil {
+		return nil, err
+	}
+	defer handler.completeSequence(c, reservation)
+	for _, update := range updates {
+		withSequence(update.(bson.M), reservation.sequence)
+	}
+	return handler.tenant(c).BulkUpdate(DatabaseName, DatabaseCollection, query, ids, updates)
+}
+
+// Turns the changed tags the account of the request may no longer see into deleted tags, so that the clients remove
+// them. They are left out of the first sync.
+func hideUnreadable(c *gin.Context, tags []model.TagDAO) []model.TagDAO {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	accountId := c.Request.Header.Get(AccountIDField)
+	visible := make([]model.TagDAO, 0)
+	for _, tag := range tags {
+		if !tag.ReadableBy(organisationId, accountId) {
+			if c.Query(SinceParam) == "" {
+				continue
+			}
+			if tag.DeletedAt == nil {
+				hiddenAt := tag.UpdatedAt
+				tag.DeletedAt = &hiddenAt
+			}
+		}
+		visible = append(visible, tag)
+	}
+	return visible
+}
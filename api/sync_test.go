@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Syncs the tags of an organisation, then the changes made since.
+func TestGetChanges(t *testing.T) {
+
+	t.Logf("Given two tags created by an organisation")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		updated := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+		deleted := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Supper"), Colour: "Red"}, router, t, test.Token1, orgId)
+
+		t.Logf("\tWhen syncing for the first time")
+		{
+			w := getChanges(router, "", orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			var first model.ChangesResponse
+			json.NewDecoder(w.Body).Decode(&first)
+			if len(first.Tags) == 2 && len(first.Deleted) == 0 && first.SyncToken != "" {
+				t.Logf("\t\tAll the tags should be returned with a sync token. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tAll the tags should be returned with a sync token:  \"%v\". %v", first, test.BallotX)
+			}
+
+			t.Logf("\tWhen syncing after tags are updated, deleted and created")
+			{
+				name := test.UniqueName("Lunch")
+				req, _ := test.HttpRequest(model.PatchTagRequest{Name: &name}, "/tags/"+updated, http.MethodPatch, test.Token1, orgId)
+				router.ServeHTTP(httptest.NewRecorder(), req)
+				req, _ = test.HttpRequest(nil, "/tags/"+deleted, http.MethodDelete, test.Token1, orgId)
+				router.ServeHTTP(httptest.NewRecorder(), req)
+				created := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Brunch"), Colour: "Red"}, router, t, test.Token1, orgId)
+
+				w := getChanges(router, first.SyncToken, orgId)
+				test.CheckStatus(w, t, http.StatusOK)
+				var changes model.ChangesResponse
+				json.NewDecoder(w.Body).Decode(&changes)
+
+				names := make(map[string]string)
+				for _, tag := range changes.Tags {
+					names[tag.Id] = tag.Name
+				}
+				if len(changes.Tags) == 2 && names[updated] == name && names[created] != "" {
+					t.Logf("\t\tThe updated and created tags should be returned. %v", test.CheckMark)
+				} else {
+					t.Errorf("\t\tThe updated and created tags should be returned:  \"%v\". %v", changes.Tags, test.BallotX)
+				}
+				if len(changes.Deleted) == 1 && changes.Deleted[0].Id == deleted {
+					t.Logf("\t\tThe deleted tag should be returned as a tombstone. %v", test.CheckMark)
+				} else {
+					t.Errorf("\t\tThe deleted tag should be returned as a tombstone:  \"%v\". %v", changes.Deleted, test.BallotX)
+				}
+			}
+		}
+	}
+
+	t.Logf("Given invalid and expired sync tokens")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+
+		test.CheckStatus(getChanges(router, "not-a-token", orgId), t, http.StatusBadRequest)
+		test.CheckStatus(getChanges(router, encodeSyncToken(syncToken{sequence: 1, issuedAt: time.Now().AddDate(-1, 0, 0)}), orgId), t, http.StatusGone)
+
+		// the tokens issued before the writes were stamped with a sequence hold the time of the sync only
+		former := make([]byte, 8)
+		binary.BigEndian.PutUint64(former, uint64(time.Now().UnixNano()))
+		test.CheckStatus(getChanges(router, base64.RawURLEncoding.EncodeToString(former), orgId), t, http.StatusGone)
+	}
+}
+
+// A write stamped with an earlier sequence than the changes already synced, but completed after them, is returned by
+// the next sync.
+func TestGetChangesWriteInFlight(t *testing.T) {
+
+	t.Logf("Given a write of the organisation still in flight")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+		w := getChanges(router, "", orgId)
+		var first model.ChangesResponse
+		json.NewDecoder(w.Body).Decode(&first)
+
+		reservation := bson.NewObjectId()
+		sequence, err := Repository.ReserveSequence(DatabaseName, SequenceCollection, orgId, reservation)
+		test.Ok(err, t)
+
+		t.Logf("\tWhen syncing after a later write has completed")
+		{
+			created := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Supper"), Colour: "Red"}, router, t, test.Token1, orgId)
+			w := getChanges(router, first.SyncToken, orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			var changes model.ChangesResponse
+			json.NewDecoder(w.Body).Decode(&changes)
+			if len(changes.Tags) == 1 && changes.Tags[0].Id == created {
+				t.Logf("\t\tThe later write should be returned. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe later write should be returned:  \"%v\". %v", changes.Tags, test.BallotX)
+			}
+
+			t.Logf("\tWhen syncing after the write in flight has completed")
+			{
+				late := model.TagDAO{Id: bson.NewObjectId(), Name: test.UniqueName("Lunch"), Colour: "Red", OrganisationId: orgId, Version: 1, UpdatedAt: time.Now().Add(-time.Hour), Sequence: sequence}
+				test.Ok(Repository.Insert(DatabaseName, DatabaseCollection, late), t)
+				test.Ok(Repository.CompleteSequence(DatabaseName, SequenceCollection, orgId, reservation, time.Now().Add(-SequenceTimeout)), t)
+
+				w := getChanges(router, changes.SyncToken, orgId)
+				test.CheckStatus(w, t, http.StatusOK)
+				var next model.ChangesResponse
+				json.NewDecoder(w.Body).Decode(&next)
+				found := false
+				for _, tag := range next.Tags {
+					found = found || tag.Id == late.Id.Hex()
+				}
+				if found {
+					t.Logf("\t\tThe write completed late should be returned. %v", test.CheckMark)
+				} else {
+					t.Errorf("\t\tThe write completed late should be returned:  \"%v\". %v", next.Tags, test.BallotX)
+				}
+			}
+		}
+	}
+}
+
+func getChanges(router *gin.Engine, since string, orgId string) *httptest.ResponseRecorder {
+	url := "/tags/changes"
+	if since != "" {
+		url += "?since=" + since
+	}
+	req, _ := test.HttpRequest(nil, url, http.MethodGet, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
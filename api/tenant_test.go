@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	neturl "net/url"
+	"testing"
+)
+
+// Creates tags in one organisation and checks the same account acting within another organisation can neither see
+// nor change them, the tags being reported as not found as if they did not exist.
+func TestCrossTenantAccess(t *testing.T) {
+
+	t.Logf("Given a tag with a child and an assignment in an organisation")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		otherOrgId := bson.NewObjectId().Hex()
+		for _, id := range []string{orgId, otherOrgId} {
+			giveRole(id, test.AccountID1, model.RoleEditor)
+			giveRole(id, test.AccountID2, model.RoleEditor)
+		}
+		name := test.UniqueName("Holidays")
+		id := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red"}, router, t, test.Token1, orgId)
+		test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Beach"), Colour: "Red", ParentId: id}, router, t, test.Token1, orgId)
+		resource := "/resources/document/" + bson.NewObjectId().Hex() + "/tags"
+		test.CheckStatus(assignTags(router, resource, []string{id}, orgId), t, http.StatusNoContent)
+
+		t.Logf("\tWhen another organisation reads the tag")
+		{
+			for _, url := range []string{"/tags/" + id, "/tags/" + id + "/descendants", "/tags/" + id + "/history", "/tags/" + id + "/stats"} {
+				test.CheckStatus(visibilityRequest(router, url, http.MethodGet, nil, test.Token1, otherOrgId), t, http.StatusNotFound)
+			}
+		}
+
+		t.Logf("\tWhen another organisation lists and searches the tags")
+		{
+			for _, url := range []string{"/tags", "/tags/search?q=" + neturl.QueryEscape(name), resource} {
+				w := visibilityRequest(router, url, http.MethodGet, nil, test.Token1, otherOrgId)
+				test.CheckStatus(w, t, http.StatusOK)
+				var response model.GetAllTagResponse
+				json.NewDecoder(w.Body).Decode(&response)
+				if len(response.Tags) == 0 {
+					t.Logf("\t\t\"%s\" should not return the tags of the organisation. %v", url, test.CheckMark)
+				} else {
+					t.Errorf("\t\t\"%s\" should not return the tags of the organisation:  \"%v\". %v", url, response.Tags, test.BallotX)
+				}
+			}
+		}
+
+		t.Logf("\tWhen another organisation changes the tag")
+		{
+			colour := "Blue"
+			patch := model.PatchTagRequest{Colour: &colour}
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id, http.MethodPut, model.UpdateTagRequest{Name: "Work", Colour: "Blue"}, test.Token1, otherOrgId), t, http.StatusNotFound)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id, http.MethodPatch, patch, test.Token1, otherOrgId), t, http.StatusNotFound)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id+"/visibility", http.MethodPut, model.UpdateVisibilityRequest{Visibility: model.VisibilityPrivate}, test.Token1, otherOrgId), t, http.StatusNotFound)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id+"/restore", http.MethodPost, nil, test.Token1, otherOrgId), t, http.StatusNotFound)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id, http.MethodDelete, nil, test.Token1, otherOrgId), t, http.StatusNotFound)
+			test.CheckStatus(assignTags(router, "/resources/document/"+bson.NewObjectId().Hex()+"/tags", []string{id}, otherOrgId), t, http.StatusNotFound)
+
+			w := visibilityRequest(router, "/tags/batchUpdate", http.MethodPost, model.BatchUpdateRequest{Tags: []model.BatchUpdateItem{{Id: id, PatchTagRequest: patch}}}, test.Token1, otherOrgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			var updated model.BatchResponse
+			json.NewDecoder(w.Body).Decode(&updated)
+			checkBatchStatuses(updated, []int{http.StatusNotFound}, t)
+
+			w = visibilityRequest(router, "/tags/batchDelete", http.MethodPost, model.BatchDeleteRequest{Ids: []string{id}}, test.Token1, otherOrgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			var deleted model.BatchResponse
+			json.NewDecoder(w.Body).Decode(&deleted)
+			checkBatchStatuses(deleted, []int{http.StatusNotFound}, t)
+		}
+
+		t.Logf("\tWhen the organisation reads the tag again")
+		{
+			w := visibilityRequest(router, "/tags/"+id, http.MethodGet, nil, test.Token1, orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			var response model.Tag
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Name == name && response.Colour == "Red" && response.Visibility == model.VisibilityOrganisation {
+				t.Logf("\t\tThe tag should be unchanged. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag should be unchanged:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	FormatParam    = "format"
+	ModeParam      = "mode"
+	ImportFile     = "file"
+	MaxImportRows  = 5000
+	CSVContentType = "text/csv; charset=utf-8"
+)
+
+// @Summary Export the tags
+// @ID export-tags
+// @Description Returns the tags of the organisation visible to the account as a file which can be imported, the parents
+// @Description before their children. The parents are given by name.
+// @Produce  json
+// @Produce  text/csv
+// @Param format query string false "csv or json, json by default"
+// @Success 200 {array} model.TransferTag "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/export [get]
+func (handler *TagHandler) ExportTags(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	format := c.DefaultQuery(FormatParam, model.FormatJSON)
+	logger.Info.Printf("Received request to export the tags as %v for organisationId \"%v\"", format, organisationId)
+
+	if format != model.FormatCSV && format != model.FormatJSON {
+		setErrorResponse("format must be csv or json", http.StatusBadRequest, c)
+		return
+	}
+	tags, ok := handler.organisationTags(c, organisationId)
+	if !ok {
+		return
+	}
+
+	exported := model.ConvertTransfer(readableTags(c, tags))
+	c.Header("Content-Disposition", "attachment; filename=tags."+format)
+	if format == model.FormatJSON {
+		c.JSON(http.StatusOK, exported)
+		return
+	}
+	c.Header(ContentType, CSVContentType)
+	c.Status(http.StatusOK)
+	if err := model.WriteCSV(c.Writer, exported); err != nil {
+		logger.Error.Printf("Failed to write the export: %v", err.Error())
+	}
+}
+
+// @Summary Import tags
+// @ID import-tags
+// @Description Creates the tags of a CSV or JSON file as exported, up to 5000 rows. Parents are given by name, either
+// @Description of an existing tag or of another row. A row named as an existing tag is skipped, overwrites the tag,
+// @Description or fails the whole import depending on the mode. Invalid rows are reported and not imported. The rows
+// @Description overwriting a tag the account may not change are invalid, the imported tags are shared with the organisation.
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "CSV or JSON file"
+// @Param format query string false "csv or json, taken from the file extension by default"
+// @Param mode query string false "skip, overwrite or fail, fail by default"
+// @Success 200 {object} model.ImportResponse "Outcome of each row"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:write permission"
+// @Failure 409 {object} model.ImportResponse "Some rows are named as existing tags, nothing has been imported"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/import [post]
+func (handler *TagHandler) ImportTags(c *gin.Context) {
+	accountId := c.Request.Header.Get(AccountIDField)
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	mode := c.DefaultQuery(ModeParam, model.ImportFail)
+	logger.Info.Printf("Received request to import tags in %v mode for accountId \"%v\" and organisationId \"%v\"", mode, accountId, organisationId)
+
+	if mode != model.ImportSkip && mode != model.ImportOverwrite && mode != model.ImportFail {
+		setErrorResponse("mode must be skip, overwrite or fail", http.StatusBadRequest, c)
+		return
+	}
+	file, header, err := c.Request.FormFile(ImportFile)
+	if err != nil {
+		setErrorResponse("the file is required", http.StatusBadRequest, c)
+		return
+	}
+	defer file.Close()
+	format := c.Query(FormatParam)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+
+	var rows []model.TransferTag
+	switch format {
+	case model.FormatCSV:
+		rows, err = model.ReadCSV(file)
+	case model.FormatJSON:
+		rows, err = model.ReadJSON(file)
+	default:
+		setErrorResponse("format must be csv or json", http.StatusBadRequest, c)
+		return
+	}
+	if err != nil {
+		setErrorResponse("Failed to parse the file: "+err.Error(), http.StatusBadRequest, c)
+		return
+	}
+	if len(rows) == 0 || len(rows) > MaxImportRows {
+		setErrorResponse(fmt.Sprintf("the file must have between 1 and %d rows", MaxImportRows), http.StatusBadRequest, c)
+		return
+	}
+
+	palette, ok := handler.findPalette(c, organisationId)
+	if !ok {
+		return
+	}
+	tags, ok := handler.organisationTags(c, organisationId)
+	if !ok {
+		return
+	}
+
+	schema, ok := handler.findSchema(c, organisationId)
+	if !ok {
+		return
+	}
+
+	plan := planImport(rows, tags, palette, schema, mode, organisationId, accountId)
+	if mode == model.ImportFail && plan.conflicts() {
+		c.JSON(http.StatusConflict, plan.response())
+		return
+	}
+
+	// the parents are written before their children so that no tag refers to a missing parent
+	records := make([]model.HistoryDAO, 0)
+	for _, i := range plan.order() {
+		row := &plan.rows[i]
+		// the tag overwritten by a parent row which failed is still there
+		if parent, ok := plan.byId[row.tag.ParentId]; ok && plan.rows[parent].result.Status == model.RowInvalid && plan.rows[parent].before == nil {
+			row.fail(model.RowInvalid, fmt.Sprintf("the parent row %d could not be imported", parent+1))
+			continue
+		}
+		if row.before == nil {
+			row.tag.AccountId = accountId
+			row.tag.OrganisationId = organisationId
+			row.tag.Visibility = model.DefaultVisibility
+			row.tag.Version = 1
+			row.tag.UpdatedAt = time.Now()
+			record := historyOf(c, model.OperationCreate, nil, &row.tag)
+			if err := handler.insertTag(c, withCreationEvent(row.tag, record)); err != nil {
+				logger.Error.Printf("Failed to import the tag \"%v\": %v", row.tag.Name, err.Error())
+				row.fail(model.RowInvalid, importFailure(err))
+				continue
+			}
+			row.result.Status = model.RowCreated
+			records = append(records, record)
+			continue
+		}
+		after := row.tag
+		after.Version++
+		record := historyOf(c, model.OperationUpdate, row.before, &after)
+		update := withEvent(tagUpdate(bson.M{TagName: row.tag.Name, NormalisedName: row.tag.NormalisedName, TagColour: row.tag.Colour, ParentId: row.tag.ParentId}), record)
+		if err := handler.writeTagById(c, row.tag.Id, update); err != nil {
+			logger.Error.Printf("Failed to import the tag \"%v\": %v", row.tag.Name, err.Error())
+			row.fail(model.RowInvalid, importFailure(err))
+			continue
+		}
+		row.tag.Version++
+		row.result.Status = model.RowUpdated
+		records = append(records, record)
+	}
+	handler.recordChanges(c, records...)
+
+	response := plan.response()
+	logger.Info.Printf("%d tags imported and %d updated for organisationId \"%v\"", response.Created, response.Updated, organisationId)
+	c.JSON(http.StatusOK, response)
+}
+
+// Row of an import together with the tag it is written to.
+type importRow struct {
+	result model.ImportResult
+	tag    model.TagDAO
+	// the existing tag overwritten by the row, nil when the row creates a tag
+	before *model.TagDAO
+}
+
+func (row *importRow) fail(status string, message string) {
+	row.result.Status = status
+	row.result.Id = ""
+	row.result.Message = message
+}
+
+// The tags written by an import, the rows which are not written have their final status already.
+type importPlan struct {
+	rows []importRow
+	// positions of the rows written, by tag id
+	byId map[bson.ObjectId]int
+	// parents of the existing tags of the organisation
+	parents map[bson.ObjectId]bson.ObjectId
+}
+
+// Validates the rows of an import made by the account against the existing tags and resolves the parents.
+func planImport(rows []model.TransferTag, tags []model.TagDAO, palette model.Palette, schema model.Schema, mode string, organisationId string, accountId string) *importPlan {
+	plan := &importPlan{rows: make([]importRow, len(rows)), byId: make(map[bson.ObjectId]int), parents: make(map[bson.ObjectId]bson.ObjectId)}
+	existing := make(map[bson.ObjectId]model.TagDAO)
+	for _, tag := range tags {
+		existing[tag.Id] = tag
+		plan.parents[tag.Id] = tag.ParentId
+	}
+	names := nameIndex(tags)
+
+	// the rows by name, so that the following rows may refer to them as parent
+	inFile := make(map[string]int)
+	for i, item := range rows {
+		row := &plan.rows[i]
+		row.result = model.ImportResult{Row: i + 1, Name: item.Name}
+		if tagNameEmptyString(item.Name) {
+			row.fail(model.RowInvalid, "tag name may not be empty")
+			continue
+		}
+		key := model.NameKey(item.Name)
+		if previous, ok := inFile[key]; ok {
+			row.fail(model.RowInvalid, fmt.Sprintf("the name is already used by row %d", previous+1))
+			continue
+		}
+		inFile[key] = i
+
+		row.tag = model.TagDAO{Id: bson.NewObjectId(), Name: model.NormaliseName(item.Name), NormalisedName: key}
+		if id, ok := names[key]; ok {
+			// the private tags of the other accounts hold their names without giving their ids away
+			if existing[id].ReadableBy(organisationId, accountId) {
+				row.result.ExistingId = id.Hex()
+			}
+			switch mode {
+			case model.ImportSkip:
+				row.result.Status = model.RowSkipped
+				continue
+			case model.ImportFail:
+				row.fail(model.RowConflict, NameConflictMessage)
+				continue
+			}
+			before := existing[id]
+			if !before.WritableBy(organisationId, accountId) {
+				row.fail(model.RowInvalid, ReadOnlyMessage)
+				continue
+			}
+			row.before = &before
+			row.tag = before
+			row.tag.Name = model.NormaliseName(item.Name)
+			row.tag.NormalisedName = key
+		}
+
+		// an overwritten tag keeps its colour unless the row gives one
+		colour := item.Colour
+		if colour == "" && row.before != nil {
+			colour = row.before.Colour
+		}
+		resolved, err := tagColour(palette, colour, item.Name)
+		if err != nil {
+			row.fail(model.RowInvalid, err.Error())
+			continue
+		}
+		row.tag.Colour = resolved
+
+		// the file carries no attributes, the overwritten tags keep theirs
+		if errs := schema.Validate(row.tag.Attributes); len(errs) > 0 {
+			row.fail(model.RowInvalid, describeFieldErrors(errs))
+			continue
+		}
+		row.result.Id = row.tag.Id.Hex()
+		plan.byId[row.tag.Id] = i
+	}
+
+	// the parents may be given by later rows
+	for i, item := range rows {
+		row := &plan.rows[i]
+		if !plan.written(i) {
+			continue
+		}
+		row.tag.ParentId = ""
+		if strings.TrimSpace(item.Parent) == "" {
+			continue
+		}
+		key := model.NameKey(item.Parent)
+		j, parentInFile := inFile[key]
+		id, parentExists := names[key]
+		parentExists = parentExists && existing[id].ReadableBy(organisationId, accountId)
+		switch {
+		case parentInFile && plan.written(j):
+			row.tag.ParentId = plan.rows[j].tag.Id
+		case parentExists:
+			row.tag.ParentId = id
+		case parentInFile:
+			row.fail(model.RowInvalid, fmt.Sprintf("the parent row %d is not valid", j+1))
+		default:
+			row.fail(model.RowInvalid, "unknown parent tag: "+item.Parent)
+		}
+	}
+	plan.resolveCycles()
+	return plan
+}
+
+// Checks whether the row at the given position is written by the import.
+func (plan *importPlan) written(i int) bool {
+	_, ok := plan.byId[plan.rows[i].tag.Id]
+	return ok && plan.rows[i].result.Status == ""
+}
+
+// Rejects the rows which would make a tag its own ancestor, and the new rows whose parent is rejected, until the
+// tags written form a forest.
+func (plan *importPlan) resolveCycles() {
+	for changed := true; changed; {
+		changed = false
+		parents := make(map[bson.ObjectId]bson.ObjectId)
+		for id, parentId := range plan.parents {
+			parents[id] = parentId
+		}
+		for i := range plan.rows {
+			if plan.written(i) {
+				parents[plan.rows[i].tag.Id] = plan.rows[i].tag.ParentId
+			}
+		}
+		for i := range plan.rows {
+			row := &plan.rows[i]
+			if !plan.written(i) {
+				continue
+			}
+			if parent, ok := plan.byId[row.tag.ParentId]; ok && !plan.written(parent) && plan.rows[parent].before == nil {
+				row.fail(model.RowInvalid, fmt.Sprintf("the parent row %d is not valid", parent+1))
+				changed = true
+				continue
+			}
+			steps := 0
+			for id := parents[row.tag.Id]; id != "" && steps <= len(parents); id = parents[id] {
+				if id == row.tag.Id {
+					row.fail(model.RowInvalid, "the tag would become its own ancestor")
+					changed = true
+					break
+				}
+				steps++
+			}
+		}
+	}
+}
+
+// Checks whether some rows are named as existing tags.
+func (plan *importPlan) conflicts() bool {
+	for _, row := range plan.rows {
+		if row.result.Status == model.RowConflict {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the positions of the rows written, the parents first.
+func (plan *importPlan) order() []int {
+	order := make([]int, 0)
+	done := make(map[int]bool)
+	var visit func(i int)
+	visit = func(i int) {
+		if done[i] {
+			return
+		}
+		done[i] = true
+		if parent, ok := plan.byId[plan.rows[i].tag.ParentId]; ok && plan.written(parent) {
+			visit(parent)
+		}
+		order = append(order, i)
+	}
+	for i := range plan.rows {
+		if plan.written(i) {
+			visit(i)
+		}
+	}
+	return order
+}
+
+func (plan *importPlan) response() model.ImportResponse {
+	response := model.ImportResponse{Rows: make([]model.ImportResult, len(plan.rows))}
+	for i, row := range plan.rows {
+		response.Rows[i] = row.result
+		switch row.result.Status {
+		case model.RowCreated:
+			response.Created++
+		case model.RowUpdated:
+			response.Updated++
+		case model.RowSkipped:
+			response.Skipped++
+		default:
+			response.Failed++
+		}
+	}
+	return response
+}
+
+// Describes the failure to write a row, a duplicate name means the tag was created meanwhile.
+func importFailure(err error) string {
+	if errors.Is(err, repository.ErrConflict) {
+		return NameConflictMessage
+	}
+	return "Failed to write the tag"
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Imports a CSV file whose rows refer to each other, then exports the tags.
+func TestImportAndExportTags(t *testing.T) {
+
+	t.Logf("Given a CSV file with a child listed before its parent and invalid rows")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		content := "name,colour,parent\nDinner,,Meals\nMeals,Red,\nLunch,not-a-colour,\nSupper,,Unknown\ndinner,,\n"
+
+		t.Logf("\tWhen importing the file")
+		{
+			w := importTags(router, "tags.csv", content, "", orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.ImportResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			statuses := make([]string, 0)
+			for _, row := range response.Rows {
+				statuses = append(statuses, row.Status)
+			}
+			expected := []string{model.RowCreated, model.RowCreated, model.RowInvalid, model.RowInvalid, model.RowInvalid}
+			if response.Created == 2 && response.Failed == 3 && strings.Join(statuses, ",") == strings.Join(expected, ",") {
+				t.Logf("\t\tEach row should be reported. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tEach row should be reported:  \"%v\". %v", response.Rows, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen exporting the tags as CSV")
+		{
+			req, _ := test.HttpRequest(nil, "/tags/export?format=csv", http.MethodGet, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			tags, err := model.ReadCSV(w.Body)
+			if err == nil && len(tags) == 2 && tags[0].Name == "Meals" && tags[1].Name == "Dinner" && tags[1].Parent == "Meals" {
+				t.Logf("\t\tThe imported tags should be exported, the parent first. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe imported tags should be exported, the parent first:  \"%v\" \"%v\". %v", tags, err, test.BallotX)
+			}
+		}
+	}
+}
+
+// Imports a JSON file naming an existing tag in each mode.
+func TestImportTagsModes(t *testing.T) {
+
+	t.Logf("Given a tag and a JSON file with the same name")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		name := test.UniqueName("Dinner")
+		id := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red"}, router, t, test.Token1, orgId)
+		rows, _ := json.Marshal([]model.TransferTag{{Name: strings.ToUpper(name), Colour: "Blue"}, {Name: test.UniqueName("Lunch")}})
+
+		t.Logf("\tWhen importing the file in fail mode")
+		{
+			w := importTags(router, "tags.json", string(rows), model.ImportFail, orgId)
+			test.CheckStatus(w, t, http.StatusConflict)
+
+			var response model.ImportResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Created == 0 && response.Rows[0].Status == model.RowConflict && response.Rows[0].ExistingId == id {
+				t.Logf("\t\tNothing should have been imported. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tNothing should have been imported:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen importing the file in overwrite mode")
+		{
+			w := importTags(router, "tags.json", string(rows), model.ImportOverwrite, orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.ImportResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Created == 1 && response.Updated == 1 && response.Rows[0].Id == id {
+				t.Logf("\t\tThe existing tag should have been overwritten. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe existing tag should have been overwritten:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen importing the file again in skip mode")
+		{
+			w := importTags(router, "tags.json", string(rows), model.ImportSkip, orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.ImportResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Created == 0 && response.Skipped == 2 {
+				t.Logf("\t\tBoth rows should have been skipped. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tBoth rows should have been skipped:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+func importTags(router *gin.Engine, filename string, content string, mode string, orgId string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile(ImportFile, filename)
+	part.Write([]byte(content))
+	writer.Close()
+
+	url := "/tags/import"
+	if mode != "" {
+		url += "?mode=" + mode
+	}
+	req, _ := http.NewRequest(http.MethodPost, url, &body)
+	req.Header.Add(ContentType, writer.FormDataContentType())
+	req.Header.Add("Authorization", test.Token1)
+	req.Header.Add(OrganisationIDField, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
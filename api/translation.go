@@ -0,0 +1,18 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tag-service/model"
+)
+
+const (
+	TagTranslations      = "translations"
+	AcceptLanguageHeader = "Accept-Language"
+	VaryHeader           = "Vary"
+)
+
+// Names the tags in the translations best matching the Accept-Language header of the request.
+func localise(c *gin.Context, tags []model.TagDAO) []model.TagDAO {
+	c.Header(VaryHeader, AcceptLanguageHeader)
+	return model.Localise(tags, model.PreferredLanguages(c.Request.Header.Get(AcceptLanguageHeader)))
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Creates a translated tag and reads it in different languages.
+func TestLocalisedTag(t *testing.T) {
+
+	t.Logf("Given a tag named Dinner and translated in French")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		id := test.CreateTag(model.CreateTagRequest{Name: "Dinner", Colour: "Red", Translations: map[string]string{"fr": "Souper"}}, router, t, test.Token1, orgId)
+
+		t.Logf("\tWhen retrieving the tag in Canadian French")
+		{
+			tag := getLocalisedTag(router, "/tags/"+id, "fr-CA, en;q=0.5", orgId, t)
+			if tag.Name == "Souper" && tag.Translations["fr"] == "Souper" {
+				t.Logf("\t\tThe French name should be returned. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe French name should be returned:  \"%v\". %v", tag, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen retrieving the tag in German")
+		{
+			tag := getLocalisedTag(router, "/tags/"+id, "de", orgId, t)
+			if tag.Name == "Dinner" {
+				t.Logf("\t\tThe default name should be returned. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe default name should be returned:  \"%v\". %v", tag, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen searching the French name")
+		{
+			req, _ := test.HttpRequest(nil, "/tags/search?q=souper", http.MethodGet, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.GetAllTagResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if len(response.Tags) == 1 && response.Tags[0].Id == id && response.Tags[0].Name == "Dinner" {
+				t.Logf("\t\tThe tag should be found and named by default. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag should be found and named by default:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen creating a tag with an invalid language tag")
+		{
+			body := model.CreateTagRequest{Name: "Lunch", Colour: "Red", Translations: map[string]string{"french": "Déjeuner"}}
+			req, _ := test.HttpRequest(body, "/tags", http.MethodPost, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+	}
+}
+
+// helper function
+func getLocalisedTag(router *gin.Engine, url string, acceptLanguage string, orgId string, t *testing.T) model.Tag {
+	req, _ := test.HttpRequest(nil, url, http.MethodGet, test.Token1, orgId)
+	req.Header.Set(AcceptLanguageHeader, acceptLanguage)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusOK)
+
+	var tag model.Tag
+	json.NewDecoder(w.Body).Decode(&tag)
+	return tag
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	cfg "github.com/tag-service/vault"
+	"net/http"
+	"time"
+)
+
+const (
+	DeletedAt              = "deletedAt"
+	TrashRetentionEnv      = "TRASH_RETENTION"
+	TrashRetentionFallback = "720h"
+	PurgeInterval          = time.Hour
+)
+
+// @Summary Get the trash
+// @ID get-trash
+// @Description Returns the deleted tags of the organisation visible to the account which have not been purged yet, the
+// @Description most recently deleted first, one page at a time
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Maximum number of tags returned"
+// @Param cursor query string false "Cursor returned with the previous page"
+// @Success 200 {object} model.TrashResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/trash [get]
+func (handler *TagHandler) GetTrash(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the trash for organisationId \"%v\"", organisationId)
+
+	query := readable(c, bson.M{OrganisationId: organisationId, DeletedAt: bson.M{"$exists": true}})
+	tags, nextCursor, ok := handler.findTagsPage(c, query, "-"+SortByDeletedAt)
+	if !ok {
+		return
+	}
+	response := model.ConvertTrash(tags)
+	response.NextCursor = nextCursor
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Restore a deleted tag
+// @ID restore-tag
+// @Description Takes the tag out of the trash, together with the descendants deleted along with it. The tag is
+// @Description restored at the root of the tree when its parent is no longer available.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tag ID"
+// @Param If-Match header string false "ETag of the tag as last read"
+// @Success 200 {object} model.Tag "Tag restored"
+// @Failure 400 {object} model.ErrorResponse "Invalid tag id"
+// @Failure 403 {object} model.ErrorResponse "The tag or one of its descendants is read-only, or the role of the account does not grant the tags:write permission"
+// @Failure 404 {object} model.EmptyBody "Tag not found"
+// @Failure 409 {object} model.ErrorResponse "The tag is not in the trash or a tag with the same name already exists"
+// @Failure 412 {object} model.ErrorResponse "The tag has been modified since it was read"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/{id}/restore [post]
+func (handler *TagHandler) RestoreTag(c *gin.Context) {
+	accountId := c.Request.Header.Get(AccountIDField)
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(TagId)
+	logger.Info.Printf("Received request to restore tag \"%v\" for accountId \"%v\" and organisationId \"%v\"", id, accountId, organisationId)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
+
+	tag, err := handler.tenant(c).Find(DatabaseName, DatabaseCollection, oid)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve the tag", c)
+		return
+	}
+	if !checkAccess(c, tag, organisationId, WriteAccess) {
+		return
+	}
+	if tag.DeletedAt == nil {
+		setErrorResponse("The tag is not in the trash", http.StatusConflict, c)
+		return
+	}
+	if !checkIfMatch(c, tag) {
+		return
+	}
+
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, bson.M{OrganisationId: organisationId})
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	available := make([]model.TagDAO, 0)
+	for _, t := range tags {
+		if t.DeletedAt == nil {
+			available = append(available, t)
+		}
+	}
+
+	// the descendants are only restored along with the tag if the account may change them all, as on delete
+	restored := append([]model.TagDAO{tag}, model.TrashedWith(tags, tag)...)
+	if !writableChildren(c, restored[1:]) {
+		return
+	}
+
+	// the names may have been reused while the tags were in the trash
+	names := nameIndex(available)
+	for _, t := range restored {
+		if existing, ok := names[model.NameKey(t.Name)]; ok {
+			logger.Error.Printf("The tag name \"%v\" already exists for organisationId \"%v\"", t.Name, organisationId)
+			c.JSON(http.StatusConflict, model.ErrorResponse{Message: NameConflictMessage, Code: http.StatusConflict, ExistingId: visibleTagId(c, available, existing)})
+			return
+		}
+	}
+
+	// the parent may have been deleted or purged since
+	before := tag
+	fields := bson.M{NormalisedName: model.NameKey(tag.Name)}
+	if tag.ParentId != "" && !contains(available, tag.ParentId) {
+		tag.ParentId = ""
+		fields[ParentId] = tag.ParentId
+	}
+	after := tag
+	after.DeletedAt = nil
+	after.NormalisedName = model.NameKey(after.Name)
+	after.Version++
+	record := historyOf(c, model.OperationRestore, &before, &after)
+	stored, err := handler.writeTag(c, tag, withEvent(restoreUpdate(fields), record))
+	if errors.Is(err, repository.ErrNotFound) && conditional(c) {
+		setErrorResponse(PreconditionFailMessage, http.StatusPreconditionFailed, c)
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		handler.nameConflict(c, organisationId, model.NameKey(tag.Name))
+		return
+	}
+	if err != nil {
+		setRepositoryError(err, "Update failed", c)
+		return
+	}
+
+	record.After = &stored
+	records := []model.HistoryDAO{record}
+	for i, descendant := range restored[1:] {
+		after := descendant
+		after.DeletedAt = nil
+		after.NormalisedName = model.NameKey(after.Name)
+		after.Version++
+		change := historyOf(c, model.OperationRestore, &restored[i+1], &after)
+		update := withEvent(restoreUpdate(bson.M{NormalisedName: model.NameKey(descendant.Name)}), change)
+		if err := handler.writeTagById(c, descendant.Id, update); err != nil {
+			logger.Error.Printf("Failed to restore the descendant \"%v\" of tag \"%v\": %v", descendant.Id.Hex(), id, err.Error())
+			continue
+		}
+		records = append(records, change)
+	}
+	handler.recordChanges(c, records...)
+	logger.Info.Printf("Tag successfully restored \"%v\"", id)
+	c.Header(ETagHeader, etag(stored))
+	c.JSON(http.StatusOK, model.ConvertToTag(stored))
+}
+
+// PurgeTrash permanently removes the tags deleted before the given time, together with their assignments, and
+// returns the number of tags removed.
+func (handler *TagHandler) PurgeTrash(before time.Time) (int, error) {
+	// the tags whose events have not been moved to the outbox yet are left for the next purge
+	tags, err := handler.unscoped.FindAll(DatabaseName, DatabaseCollection, bson.M{DeletedAt: bson.M{"$lt": before}, PendingEvents + ".0": bson.M{"$exists": false}})
+	if err != nil || len(tags) == 0 {
+		return 0, err
+	}
+	ids := make([]bson.ObjectId, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.Id
+	}
+
+	// the assignments go first so that a failed purge is completed by the next one
+	if err := handler.unscoped.RemoveAll(DatabaseName, AssignmentCollection, bson.M{AssignmentTagId: bson.M{"$in": ids}}); err != nil {
+		return 0, err
+	}
+	if err := handler.unscoped.RemoveAll(DatabaseName, DatabaseCollection, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// PurgeTrashEvery purges the tags which have been in the trash for longer than the retention period, once per
+// interval. It never returns and is meant to run in its own goroutine.
+func (handler *TagHandler) PurgeTrashEvery(interval time.Duration, retention time.Duration) {
+	for {
+		count, err := handler.PurgeTrash(time.Now().Add(-retention))
+		if err != nil {
+			logger.Error.Printf("Failed to purge the trash: %v", err.Error())
+		} else if count > 0 {
+			logger.Info.Printf("%d tags purged from the trash", count)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// TrashRetention reads how long the deleted tags are kept in the trash from the TRASH_RETENTION environment
+// variable, e.g. 720h for 30 days.
+func TrashRetention() (time.Duration, error) {
+	value := cfg.GetEnv(TrashRetentionEnv, TrashRetentionFallback)
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention <= 0 {
+		return 0, fmt.Errorf("invalid trash retention: %v", value)
+	}
+	return retention, nil
+}
+
+// Restricts the query to the tags which are not in the trash.
+func live(query bson.M) bson.M {
+	query[DeletedAt] = bson.M{"$exists": false}
+	return query
+}
+
+// Returns the tag as it is once moved to the trash.
+func trashed(tag model.TagDAO, deletedAt time.Time) *model.TagDAO {
+	tag.DeletedAt = &deletedAt
+	tag.UpdatedAt = deletedAt
+	tag.NormalisedName = ""
+	tag.Version++
+	return &tag
+}
+
+// Builds the update moving a tag to the trash. The normalised name is removed so that the name can be reused while
+// the tag is in the trash, it is checked again when the tag is restored.
+func trashUpdate(deletedAt time.Time) bson.M {
+	return bson.M{"$set": bson.M{DeletedAt: deletedAt, UpdatedAt: deletedAt}, "$unset": bson.M{NormalisedName: ""}, "$inc": bson.M{Version: 1}}
+}
+
+// Builds the update taking a tag out of the trash and setting the given fields.
+func restoreUpdate(fields bson.M) bson.M {
+	update := tagUpdate(fields)
+	unset, ok := update["$unset"].(bson.M)
+	if !ok {
+		unset = bson.M{}
+	}
+	unset[DeletedAt] = ""
+	update["$unset"] = unset
+	return update
+}
+
+// Checks whether the tag with the given id is part of the list.
+func contains(tags []model.TagDAO, id bson.ObjectId) bool {
+	for _, tag := range tags {
+		if tag.Id == id {
+			return true
+		}
+	}
+	return false
+}
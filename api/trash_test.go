@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Deletes a tag, finds it in the trash and restores it.
+func TestDeleteAndRestoreTag(t *testing.T) {
+
+	t.Logf("Given I create and delete a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		organisationId := bson.NewObjectId().Hex()
+		giveRole(organisationId, test.AccountID1, model.RoleEditor)
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, organisationId)
+		test.CheckStatus(trashRequest(router, "/tags/"+id, http.MethodDelete, organisationId), t, http.StatusNoContent)
+		test.CheckStatus(trashRequest(router, "/tags/"+id, http.MethodGet, organisationId), t, http.StatusNotFound)
+
+		t.Logf("\tWhen Sending Get trash request to endpoint:  \"%s\"", "\\tags\\trash")
+		{
+			w := trashRequest(router, "/tags/trash", http.MethodGet, organisationId)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.TrashResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if len(response.Tags) == 1 && response.Tags[0].Id == id && !response.Tags[0].DeletedAt.IsZero() {
+				t.Logf("\t\tThe trash should contain the deleted tag. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe trash should contain the deleted tag:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen Sending Restore tag request to endpoint:  \"%s\"", "\\tags\\"+id+"\\restore")
+		{
+			test.CheckStatus(trashRequest(router, "/tags/"+id+"/restore", http.MethodPost, organisationId), t, http.StatusOK)
+			test.CheckStatus(trashRequest(router, "/tags/"+id, http.MethodGet, organisationId), t, http.StatusOK)
+
+			var response model.TrashResponse
+			json.NewDecoder(trashRequest(router, "/tags/trash", http.MethodGet, organisationId).Body).Decode(&response)
+			if len(response.Tags) == 0 {
+				t.Logf("\t\tThe trash should be empty. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe trash should be empty:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen Sending Restore tag request for a tag which is not in the trash to endpoint:  \"%s\"", "\\tags\\"+id+"\\restore")
+		{
+			test.CheckStatus(trashRequest(router, "/tags/"+id+"/restore", http.MethodPost, organisationId), t, http.StatusConflict)
+		}
+	}
+}
+
+// Lists the trash one page at a time, the most recently deleted tag first.
+func TestGetTrashPages(t *testing.T) {
+
+	t.Logf("Given I create and delete three tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		organisationId := bson.NewObjectId().Hex()
+		giveRole(organisationId, test.AccountID1, model.RoleEditor)
+		ids := make([]string, 3)
+		for i := range ids {
+			ids[i] = test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, organisationId)
+			test.CheckStatus(trashRequest(router, "/tags/"+ids[i], http.MethodDelete, organisationId), t, http.StatusNoContent)
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		t.Logf("\tWhen Sending Get trash requests to endpoint:  \"%s\"", "\\tags\\trash?limit=2")
+		{
+			var first, second model.TrashResponse
+			json.NewDecoder(trashRequest(router, "/tags/trash?limit=2", http.MethodGet, organisationId).Body).Decode(&first)
+			json.NewDecoder(trashRequest(router, "/tags/trash?limit=2&cursor="+first.NextCursor, http.MethodGet, organisationId).Body).Decode(&second)
+
+			if len(first.Tags) == 2 && first.Tags[0].Id == ids[2] && first.Tags[1].Id == ids[1] && first.NextCursor != "" {
+				t.Logf("\t\tThe first page should contain the last two deleted tags. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe first page should contain the last two deleted tags:  \"%v\". %v", first, test.BallotX)
+			}
+			if len(second.Tags) == 1 && second.Tags[0].Id == ids[0] && second.NextCursor == "" {
+				t.Logf("\t\tThe last page should contain the first deleted tag. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe last page should contain the first deleted tag:  \"%v\". %v", second, test.BallotX)
+			}
+			test.CheckStatus(trashRequest(router, "/tags/trash?cursor=invalid", http.MethodGet, organisationId), t, http.StatusBadRequest)
+		}
+	}
+}
+
+// Attempt to restore a tag whose name has been reused results in conflict error.
+func TestRestoreTagWithReusedName(t *testing.T) {
+
+	t.Logf("Given I delete a tag and create another one with the same name")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		name := test.UniqueName("Dinner")
+		id := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red"}, router, t, test.Token1, test.OrgID1)
+		test.CheckStatus(trashRequest(router, "/tags/"+id, http.MethodDelete, test.OrgID1), t, http.StatusNoContent)
+		existing := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Blue"}, router, t, test.Token1, test.OrgID1)
+
+		t.Logf("\tWhen Sending Restore tag request to endpoint:  \"%s\"", "\\tags\\"+id+"\\restore")
+		{
+			w := trashRequest(router, "/tags/"+id+"/restore", http.MethodPost, test.OrgID1)
+			test.CheckStatus(w, t, http.StatusConflict)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.ExistingId == existing {
+				t.Logf("\t\tThe response should contain the id of the existing tag. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe response should contain the id of the existing tag:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// Attempt to restore a tag deleted together with a read-only descendant of another account results in forbidden error.
+func TestRestoreTagWithReadOnlyDescendant(t *testing.T) {
+
+	t.Logf("Given a tag with a read-only child deleted together by the first account")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		organisationId := bson.NewObjectId().Hex()
+		giveRole(organisationId, test.AccountID1, model.RoleEditor)
+		giveRole(organisationId, test.AccountID2, model.RoleEditor)
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Policies"), Colour: "Red"}, router, t, test.Token1, organisationId)
+		child := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Expenses"), Colour: "Red", ParentId: id, Visibility: model.VisibilityReadOnly}, router, t, test.Token1, organisationId)
+		test.CheckStatus(trashRequest(router, "/tags/"+id+"?children=cascade", http.MethodDelete, organisationId), t, http.StatusNoContent)
+
+		t.Logf("\tWhen the second account sends Restore tag request to endpoint:  \"%s\"", "\\tags\\"+id+"\\restore")
+		{
+			test.CheckStatus(visibilityRequest(router, "/tags/"+id+"/restore", http.MethodPost, nil, test.Token2, organisationId), t, http.StatusForbidden)
+			if tag, err := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(child)); err == nil && tag.DeletedAt != nil {
+				t.Logf("\t\tThe read-only child should have been left in the trash. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe read-only child should have been left in the trash. %v", test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen the first account sends Restore tag request to endpoint:  \"%s\"", "\\tags\\"+id+"\\restore")
+		{
+			test.CheckStatus(trashRequest(router, "/tags/"+id+"/restore", http.MethodPost, organisationId), t, http.StatusOK)
+			test.CheckStatus(trashRequest(router, "/tags/"+child, http.MethodGet, organisationId), t, http.StatusOK)
+		}
+	}
+}
+
+// Purging the trash removes the tags and their assignments for good.
+func TestPurgeTrash(t *testing.T) {
+
+	t.Logf("Given I assign a tag to a resource and delete the tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Urgent"), Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		assignTags(router, "/resources/document/"+bson.NewObjectId().Hex()+"/tags", []string{id}, test.OrgID1)
+		test.CheckStatus(trashRequest(router, "/tags/"+id, http.MethodDelete, test.OrgID1), t, http.StatusNoContent)
+
+		t.Logf("\tWhen purging the tags deleted before now")
+		{
+			count, err := controller.PurgeTrash(time.Now().Add(time.Minute))
+			test.Ok(err, t)
+
+			_, errFind := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(id))
+			assignments, _ := Repository.FindAssignments(DatabaseName, AssignmentCollection, bson.M{AssignmentTagId: bson.ObjectIdHex(id)})
+			if count > 0 && errFind != nil && len(assignments) == 0 {
+				t.Logf("\t\tThe tag and its assignments should have been removed. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag and its assignments should have been removed. %v %v", test.BallotX, assignments)
+			}
+		}
+	}
+}
+
+// helper function, sends a request without body
+func trashRequest(router *gin.Engine, endpoint string, method string, orgId string) *httptest.ResponseRecorder {
+	req, _ := test.HttpRequest(nil, endpoint, method, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"net/http"
+)
+
+const (
+	ParentId         = "parentId"
+	ChildrenParam    = "children"
+	RejectChildren   = "reject"
+	CascadeChildren  = "cascade"
+	ReparentChildren = "reparent"
+)
+
+// @Summary Get the tag tree
+// @ID get-tag-tree
+// @Description Returns the tags of the organisation nested under their parent tag
+// @Accept  json
+// @Produce  json
+// @Param Accept-Language header string false "Preferred languages of the tag names"
+// @Success 200 {object} model.TagTreeResponse "ok"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/tree [get]
+func (handler *TagHandler) GetTagTree(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the tag tree for organisationId \"%v\"", organisationId)
+
+	// the tags whose parent the account may not see are roots of the tree
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, readable(c, live(bson.M{OrganisationId: organisationId})))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.BuildForest(localise(c, tags)))
+}
+
+// @Summary Get the descendants of a tag
+// @ID get-tag-descendants
+// @Description Returns the child tags of the given tag, nested under their own parent tag
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tag ID"
+// @Param Accept-Language header string false "Preferred languages of the tag names"
+// @Success 200 {object} model.TagTreeResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Invalid tag id"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:read permission"
+// @Failure 404 {object} model.EmptyBody "Tag not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/{id}/descendants [get]
+func (handler *TagHandler) GetTagDescendants(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(TagId)
+	logger.Info.Printf("Received request to retrieve the descendants of tag \"%v\" for organisationId \"%v\"", id, organisationId)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
+
+	// query the tag and check it belongs to the organisation
+	if _, ok := handler.findOrganisationTag(c, oid, organisationId, ReadAccess); !ok {
+		return
+	}
+
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, readable(c, live(bson.M{OrganisationId: organisationId})))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.BuildSubtree(localise(c, tags), oid))
+}
+
+// Checks the parent tag exists within the organisation, is visible to the account of the request and that the tag
+// would not become its own ancestor. The error response is written when the parent is not valid.
+func (handler *TagHandler) validateParent(c *gin.Context, organisationId string, id bson.ObjectId, parentId string) (bson.ObjectId, bool) {
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, live(bson.M{OrganisationId: organisationId}))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return "", false
+	}
+
+	oid, err := visibleParent(c, tags, id, parentId)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return "", false
+	}
+	return oid, true
+}
+
+// Checks the delete policy can be applied to the child tags of the tag about to be deleted. Returns the descendants to
+// delete along with the tag when cascading and the children to move up when reparenting, nothing being changed before
+// the tag itself is in the trash. The error response is written when the children prevent the deletion.
+func (handler *TagHandler) checkChildren(c *gin.Context, tag model.TagDAO, policy string) ([]model.TagDAO, []model.TagDAO, bool) {
+	children, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, live(bson.M{ParentId: tag.Id}))
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return nil, nil, false
+	}
+	if len(children) == 0 {
+		return nil, nil, true
+	}
+
+	switch policy {
+	case CascadeChildren:
+		tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, live(bson.M{OrganisationId: tag.OrganisationId}))
+		if err != nil {
+			setRepositoryError(err, "Failed to retrieve data from the database", c)
+			return nil, nil, false
+		}
+		byId := make(map[bson.ObjectId]model.TagDAO)
+		for _, t := range tags {
+			byId[t.Id] = t
+		}
+		descendants := make([]model.TagDAO, 0)
+		for _, id := range model.DescendantIds(tags, tag.Id) {
+			descendants = append(descendants, byId[id])
+		}
+		if !writableChildren(c, descendants) {
+			return nil, nil, false
+		}
+		return descendants, nil, true
+
+	case ReparentChildren:
+		if !writableChildren(c, children) {
+			return nil, nil, false
+		}
+		return nil, children, true
+	}
+
+	logger.Error.Println("The tag has child tags")
+	setErrorResponse("The tag has child tags", http.StatusConflict, c)
+	return nil, nil, false
+}
+
+// Moves the children of the deleted tag up to its parent, or to the root of the tree. Returns the records of the
+// changes made.
+func (handler *TagHandler) reparentChildren(c *gin.Context, tag model.TagDAO, children []model.TagDAO) ([]model.HistoryDAO, error) {
+	records := make([]model.HistoryDAO, len(children))
+	for i := range children {
+		child := children[i]
+		child.ParentId = tag.ParentId
+		child.Version++
+		records[i] = historyOf(c, model.OperationUpdate, &children[i], &child)
+	}
+	return handler.writeChanges(c, live(bson.M{}), records, func() bson.M { return tagUpdate(bson.M{ParentId: tag.ParentId}) })
+}
+
+// Checks the given delete policy is supported.
+func validChildrenPolicy(policy string) bool {
+	return policy == RejectChildren || policy == CascadeChildren || policy == ReparentChildren
+}
+
+// Checks the account of the request may change all the given child tags, the error response is written otherwise.
+func writableChildren(c *gin.Context, children []model.TagDAO) bool {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	accountId := c.Request.Header.Get(AccountIDField)
+	for _, child := range children {
+		if !child.WritableBy(organisationId, accountId) {
+			setErrorResponse(ReadOnlyChildrenMessage, http.StatusForbidden, c)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Creates Travel > Flights > Business and queries the tree.
+func TestGetTagTreeSuccess(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		travel, flights, business := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Get tag tree request to endpoint:  \"%s\"", "\\tags\\tree")
+		{
+			req, err := test.HttpRequest(nil, "/tags/tree", http.MethodGet, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.TagTreeResponse
+			json.NewDecoder(w.Body).Decode(&response)
+
+			node := findNode(response.Tags, travel)
+			if node != nil && len(node.Children) == 1 && node.Children[0].Id == flights &&
+				len(node.Children[0].Children) == 1 && node.Children[0].Children[0].Id == business {
+				t.Logf("\t\tThe tags should be nested under their parent. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tags should be nested under their parent:  \"%v\". %v", node, test.BallotX)
+			}
+		}
+	}
+}
+
+// Queries the descendants of the root of a taxonomy.
+func TestGetTagDescendantsSuccess(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		travel, flights, business := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Get tag descendants request to endpoint:  \"%s\"", "\\tags\\"+travel+"\\descendants")
+		{
+			req, err := test.HttpRequest(nil, "/tags/"+travel+"/descendants", http.MethodGet, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.TagTreeResponse
+			json.NewDecoder(w.Body).Decode(&response)
+
+			if len(response.Tags) == 1 && response.Tags[0].Id == flights && findNode(response.Tags, business) != nil {
+				t.Logf("\t\tThe descendants should have been returned. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe descendants should have been returned:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// Attempt to move a tag under one of its descendants results in bad request.
+func TestUpdateTagParentCreatingCycle(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		travel, _, business := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Patch tag request moving the root under a leaf to endpoint:  \"%s\"", "\\tags\\"+travel)
+		{
+			req, err := test.HttpRequest(model.PatchTagRequest{ParentId: &business}, "/tags/"+travel, http.MethodPatch, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			expectedResponse := model.ErrorResponse{Code: http.StatusBadRequest, Message: "a tag may not be moved under itself or one of its descendants"}
+
+			// check body response
+			test.CheckResponseMessage(response, expectedResponse, t, w)
+		}
+	}
+}
+
+// Attempt to create a tag under an unknown parent results in bad request.
+func TestCreateTagWithUnknownParent(t *testing.T) {
+
+	t.Logf("Given the tag service is up and running")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		t.Logf("\tWhen Sending Create request with an unknown parent to endpoint:  \"%s\"", "\\tags")
+		{
+			body := model.CreateTagRequest{Name: test.UniqueName("Flights"), Colour: "Blue", ParentId: bson.NewObjectId().Hex()}
+			req, err := test.HttpRequest(body, "/tags", http.MethodPost, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+	}
+}
+
+// Attempt to delete a tag with children using the default policy results in conflict error.
+func TestDeleteTagWithChildrenRejected(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		travel, _, _ := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Delete tag request to endpoint:  \"%s\"", "\\tags\\"+travel)
+		{
+			w := deleteTag(router, "/tags/"+travel)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusConflict)
+		}
+	}
+}
+
+// Deleting a tag with the cascade policy deletes all its descendants.
+func TestDeleteTagWithChildrenCascade(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		travel, flights, business := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Delete tag request to endpoint:  \"%s\"", "\\tags\\"+travel+"?children=cascade")
+		{
+			w := deleteTag(router, "/tags/"+travel+"?children=cascade")
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusNoContent)
+
+			for _, id := range []string{flights, business} {
+				if tag, err := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(id)); err == nil && tag.DeletedAt != nil {
+					t.Logf("\t\tThe descendant \"%s\" should have been moved to the trash. %v", id, test.CheckMark)
+				} else {
+					t.Errorf("\t\tThe descendant \"%s\" should have been moved to the trash. %v", id, test.BallotX)
+				}
+			}
+		}
+	}
+}
+
+// Deleting a tag with the reparent policy moves its children to its own parent.
+func TestDeleteTagWithChildrenReparent(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		travel, flights, business := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Delete tag request to endpoint:  \"%s\"", "\\tags\\"+flights+"?children=reparent")
+		{
+			w := deleteTag(router, "/tags/"+flights+"?children=reparent")
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusNoContent)
+
+			tag, _ := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(business))
+			if tag.ParentId.Hex() == travel {
+				t.Logf("\t\tThe child should have been moved to the grand parent. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe child should have been moved to the grand parent. %v", test.BallotX)
+			}
+		}
+	}
+}
+
+// Attempt to delete a tag with a stale ETag and the reparent policy results in precondition failed, its children being
+// left in place.
+func TestDeleteTagWithChildrenReparentAndStaleETag(t *testing.T) {
+
+	t.Logf("Given I create a taxonomy of tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		_, flights, business := createTaxonomy(router, t)
+
+		t.Logf("\tWhen Sending Delete tag request with a stale ETag to endpoint:  \"%s\"", "\\tags\\"+flights+"?children=reparent")
+		{
+			req, _ := test.HttpRequest(nil, "/tags/"+flights+"?children=reparent", http.MethodDelete, test.Token2, test.OrgID1)
+			req.Header.Set(IfMatchHeader, `"0"`)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusPreconditionFailed)
+
+			tag, _ := Repository.Find(DatabaseName, DatabaseCollection, bson.ObjectIdHex(business))
+			if tag.ParentId.Hex() == flights && tag.Version == 1 {
+				t.Logf("\t\tThe child should have been left in place. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe child should have been left in place. %v", test.BallotX)
+			}
+		}
+	}
+}
+
+// helper function, creates Travel > Flights > Business
+func createTaxonomy(router *gin.Engine, t *testing.T) (string, string, string) {
+	travel := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Travel"), Colour: "Blue"}, router, t, test.Token2, test.OrgID1)
+	flights := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Flights"), Colour: "Blue", ParentId: travel}, router, t, test.Token2, test.OrgID1)
+	business := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Business"), Colour: "Blue", ParentId: flights}, router, t, test.Token2, test.OrgID1)
+	return travel, flights, business
+}
+
+// helper function
+func deleteTag(router *gin.Engine, url string) *httptest.ResponseRecorder {
+	req, _ := test.HttpRequest(nil, url, http.MethodDelete, test.Token2, test.OrgID1)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// helper function
+func findNode(nodes []model.TagNode, id string) *model.TagNode {
+	for i := range nodes {
+		if nodes[i].Id == id {
+			return &nodes[i]
+		}
+		if node := findNode(nodes[i].Children, id); node != nil {
+			return node
+		}
+	}
+	return nil
+}
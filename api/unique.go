@@ -0,0 +1,118 @@
+package api
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	NormalisedName      = "normalisedName"
+	NameConflictMessage = "A tag with the same name already exists"
+)
+
+// Writes the conflict response for a name already taken within the organisation, together with the id of the tag
+// holding the name.
+func (handler *TagHandler) nameConflict(c *gin.Context, organisationId string, key string) {
+	logger.Error.Printf("The tag name \"%v\" already exists for organisationId \"%v\"", key, organisationId)
+	c.JSON(http.StatusConflict, model.ErrorResponse{Message: NameConflictMessage, Code: http.StatusConflict, ExistingId: handler.existingTagId(c, organisationId, key)})
+}
+
+// Returns the id of the tag holding the name within the organisation, or an empty string if it cannot be found. The
+// private tags of the other accounts hold their names all the same, but their ids are not given away.
+func (handler *TagHandler) existingTagId(c *gin.Context, organisationId string, key string) string {
+	tags, err := handler.tenant(c).FindAll(DatabaseName, DatabaseCollection, readable(c, bson.M{OrganisationId: organisationId, NormalisedName: key}))
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+	return tags[0].Id.Hex()
+}
+
+// Returns the id of the tag holding a name among the given tags, as reported with a name conflict, or an empty string
+// when the account of the request may not see it. The tags missing from the list are those just created by the account.
+func visibleTagId(c *gin.Context, tags []model.TagDAO, id bson.ObjectId) string {
+	for _, tag := range tags {
+		if tag.Id == id && !tag.ReadableBy(c.Request.Header.Get(OrganisationIDField), c.Request.Header.Get(AccountIDField)) {
+			return ""
+		}
+	}
+	return id.Hex()
+}
+
+// ReportDuplicates writes the tags sharing the same name within an organisation, one group per line, and returns the
+// number of groups found. The database is left unchanged, see BackfillNormalisedNames.
+func (handler *TagHandler) ReportDuplicates(out io.Writer) (int, error) {
+	groups, keys, err := handler.nameGroups()
+	if err != nil {
+		return 0, err
+	}
+
+	duplicates := 0
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) == 1 {
+			continue
+		}
+
+		duplicates++
+		names := make([]string, len(group))
+		for i, tag := range group {
+			names[i] = fmt.Sprintf("%v %q", tag.Id.Hex(), tag.Name)
+		}
+		fmt.Fprintf(out, "organisation %v: %v\n", group[0].OrganisationId, strings.Join(names, ", "))
+	}
+	return duplicates, nil
+}
+
+// BackfillNormalisedNames stores the normalised name of the tags created before names were normalised, or normalised
+// differently, so that the unique index applies to them from now on, and returns the number of tags updated. The
+// duplicated names are left out, they are to be renamed first, see ReportDuplicates.
+func (handler *TagHandler) BackfillNormalisedNames() (int, error) {
+	groups, keys, err := handler.nameGroups()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) > 1 {
+			continue
+		}
+		tag := group[0]
+		if name := model.NameKey(tag.Name); tag.NormalisedName != name {
+			update := bson.M{"$set": bson.M{NormalisedName: name}}
+			if err := handler.unscoped.Update(DatabaseName, DatabaseCollection, tag.Id, update); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// Groups the tags outside the trash by organisation and normalised name, and returns the groups with their sorted keys.
+func (handler *TagHandler) nameGroups() (map[string][]model.TagDAO, []string, error) {
+	tags, err := handler.unscoped.FindAll(DatabaseName, DatabaseCollection, live(bson.M{}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups := make(map[string][]model.TagDAO)
+	keys := make([]string, 0)
+	for _, tag := range tags {
+		key := tag.OrganisationId + "/" + model.NameKey(tag.Name)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], tag)
+	}
+	sort.Strings(keys)
+	return groups, keys, nil
+}
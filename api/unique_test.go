@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Attempt to create a tag differing from an existing one by case and spaces only results in conflict error.
+func TestCreateTagWithDuplicateName(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		name := test.UniqueName("Dinner")
+		id := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Create request with the same name in upper case to endpoint:  \"%s\"", "\\tags")
+		{
+			body := model.CreateTagRequest{Name: " " + strings.ToUpper(name) + " ", Colour: "Blue"}
+			req, err := test.HttpRequest(body, "/tags", http.MethodPost, test.Token1, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusConflict)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.ExistingId == id {
+				t.Logf("\t\tThe response should contain the id of the existing tag. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe response should contain the id of the existing tag:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// Attempt to rename a tag to the name of another tag results in conflict error.
+func TestPatchTagWithDuplicateName(t *testing.T) {
+
+	t.Logf("Given I create two tags")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		name := test.UniqueName("Dinner")
+		existing := test.CreateTag(model.CreateTagRequest{Name: name, Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Lunch"), Colour: "Red"}, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Patch tag request renaming the second tag to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			lower := strings.ToLower(name)
+			req, err := test.HttpRequest(model.PatchTagRequest{Name: &lower}, "/tags/"+id, http.MethodPatch, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusConflict)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.ExistingId == existing {
+				t.Logf("\t\tThe response should contain the id of the existing tag. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe response should contain the id of the existing tag:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+	}
+}
+
+// Reports the duplicates created before tag names were normalised.
+func TestReportDuplicates(t *testing.T) {
+
+	t.Logf("Given two tags with the same name stored before names were normalised")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		for _, name := range []string{"Dinner", "dinner "} {
+			Repository.Insert(DatabaseName, DatabaseCollection, &model.TagDAO{Id: bson.NewObjectId(), Name: name, Colour: "Red", OrganisationId: organisationId})
+		}
+		single := model.TagDAO{Id: bson.NewObjectId(), Name: "Lunch", Colour: "Red", OrganisationId: organisationId}
+		Repository.Insert(DatabaseName, DatabaseCollection, &single)
+
+		t.Logf("\tWhen Reporting the duplicate tags")
+		{
+			var out bytes.Buffer
+			_, err := NewTagHandler(Repository).ReportDuplicates(&out)
+
+			test.Ok(err, t)
+
+			if strings.Count(out.String(), organisationId) == 1 && !strings.Contains(out.String(), single.Id.Hex()) {
+				t.Logf("\t\tThe duplicate tags should have been reported. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe duplicate tags should have been reported:  \"%s\". %v", out.String(), test.BallotX)
+			}
+
+			tag, _ := Repository.Find(DatabaseName, DatabaseCollection, single.Id)
+			if tag.NormalisedName == "" {
+				t.Logf("\t\tThe tags should have been left unchanged. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tags should have been left unchanged. %v", test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen Backfilling the normalised names")
+		{
+			_, err := NewTagHandler(Repository).BackfillNormalisedNames()
+
+			test.Ok(err, t)
+
+			tag, _ := Repository.Find(DatabaseName, DatabaseCollection, single.Id)
+			if tag.NormalisedName == "lunch" {
+				t.Logf("\t\tThe normalised name of the other tag should have been stored. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe normalised name of the other tag should have been stored. %v", test.BallotX)
+			}
+			duplicates, _ := Repository.FindAll(DatabaseName, DatabaseCollection, bson.M{OrganisationId: organisationId, NormalisedName: "dinner"})
+			if len(duplicates) == 0 {
+				t.Logf("\t\tThe duplicate tags should have been left unchanged. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe duplicate tags should have been left unchanged. %v", test.BallotX)
+			}
+		}
+	}
+}
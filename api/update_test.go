@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Creates a tag and replaces its name and colour.
+func TestUpdateTagSuccess(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		tag := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
+		id := test.CreateTag(tag, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Update tag request to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			body := model.UpdateTagRequest{Name: "Lunch", Colour: "Blue"}
+			req, err := test.HttpRequest(body, "/tags/"+id, http.MethodPut, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusOK)
+
+			// check the tag has been updated
+			checkStoredTag(router, id, model.Tag{Id: id, Name: body.Name, Colour: body.Colour, AccountId: test.AccountID2, OrganisationId: test.OrgID1, Visibility: model.VisibilityOrganisation}, t)
+		}
+	}
+}
+
+// Creates a tag and changes its colour only.
+func TestPatchTagSuccess(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		tag := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
+		id := test.CreateTag(tag, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Patch tag request to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			colour := "Green"
+			body := model.PatchTagRequest{Colour: &colour}
+			req, err := test.HttpRequest(body, "/tags/"+id, http.MethodPatch, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusOK)
+
+			// check only the colour has been updated
+			checkStoredTag(router, id, model.Tag{Id: id, Name: tag.Name, Colour: colour, AccountId: test.AccountID2, OrganisationId: test.OrgID1, Visibility: model.VisibilityOrganisation}, t)
+		}
+	}
+}
+
+// Attempt to patch a tag with an empty payload results in bad request.
+func TestPatchTagWithNoFields(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		tag := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
+		id := test.CreateTag(tag, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Patch tag request with no fields to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			req, err := test.HttpRequest(model.PatchTagRequest{}, "/tags/"+id, http.MethodPatch, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			expectedResponse := model.ErrorResponse{Code: http.StatusBadRequest, Message: "no fields to update"}
+
+			// check body response
+			test.CheckResponseMessage(response, expectedResponse, t, w)
+		}
+	}
+}
+
+// Attempt to rename a tag to an empty string results in bad request.
+func TestUpdateTagWithEmptyTagName(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		tag := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
+		id := test.CreateTag(tag, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Update tag request with an empty name to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			body := model.UpdateTagRequest{Name: " ", Colour: "Red"}
+			req, err := test.HttpRequest(body, "/tags/"+id, http.MethodPut, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusBadRequest)
+
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			expectedResponse := model.ErrorResponse{Code: http.StatusBadRequest, Message: "tag name may not be empty"}
+
+			// check body response
+			test.CheckResponseMessage(response, expectedResponse, t, w)
+		}
+	}
+}
+
+// Attempt to update a tag which does not exist.
+func TestUpdateTagIdNotFound(t *testing.T) {
+
+	t.Logf("Given no tag is created")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		randomId := bson.NewObjectId().Hex()
+
+		t.Logf("\tWhen Sending Update tag request to endpoint:  \"%s\"", "\\tags\\"+randomId)
+		{
+			body := model.UpdateTagRequest{Name: "Lunch", Colour: "Blue"}
+			req, err := test.HttpRequest(body, "/tags/"+randomId, http.MethodPut, test.Token2, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusNotFound)
+		}
+	}
+}
+
+// Attempt to update a tag not belonging to the organisation result in not found error.
+func TestUpdateTagNotBelongingToTheUser(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		tag := model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}
+		id := test.CreateTag(tag, router, t, test.Token2, test.OrgID1)
+
+		t.Logf("\tWhen Sending Patch tag request to endpoint:  \"%s\" with a different organisationId", "\\tags\\"+id)
+		{
+			name := "Lunch"
+			req, err := test.HttpRequest(model.PatchTagRequest{Name: &name}, "/tags/"+id, http.MethodPatch, test.Token1, test.OrgID2)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+
+			// Assert response code status
+			test.CheckStatus(w, t, http.StatusNotFound)
+		}
+	}
+}
+
+// helper function
+func checkStoredTag(router http.Handler, id string, expected model.Tag, t *testing.T) {
+	req, _ := test.HttpRequest(nil, "/tags/"+id, http.MethodGet, test.Token2, expected.OrganisationId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response model.Tag
+	json.NewDecoder(w.Body).Decode(&response)
+	if reflect.DeepEqual(response, expected) {
+		t.Logf("\t\tTag should have been updated:  \"%s\". %v", expected, test.CheckMark)
+	} else {
+		t.Errorf("\t\tTag should have been updated:  \"%s\". %v", response, test.BallotX)
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	Version                 = "version"
+	ETagHeader              = "ETag"
+	IfMatchHeader           = "If-Match"
+	PreconditionFailMessage = "The tag has been modified since it was read"
+)
+
+// Returns the entity tag of the given tag, its quoted version.
+func etag(tag model.TagDAO) string {
+	return strconv.Quote(strconv.Itoa(tag.Version))
+}
+
+// Checks whether the request is conditional, i.e. holds an If-Match header.
+func conditional(c *gin.Context) bool {
+	return c.GetHeader(IfMatchHeader) != ""
+}
+
+// Checks the If-Match header of the request against the version of the tag, a request without If-Match always
+// matches. The error response is written when the tag has been modified since the client read it.
+func checkIfMatch(c *gin.Context, tag model.TagDAO) bool {
+	if !conditional(c) {
+		return true
+	}
+	for _, value := range strings.Split(c.GetHeader(IfMatchHeader), ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || value == etag(tag) {
+			return true
+		}
+	}
+	setErrorResponse(PreconditionFailMessage, http.StatusPreconditionFailed, c)
+	return false
+}
+
+// Builds the query matching the tag only while it still has the version it was read with. The tags created before
+// versions were introduced have no version.
+func versionQuery(tag model.TagDAO) bson.M {
+	if tag.Version == 0 {
+		return bson.M{"_id": tag.Id, Version: bson.M{"$in": []interface{}{0, nil}}}
+	}
+	return bson.M{"_id": tag.Id, Version: tag.Version}
+}
+
+// Applies the update to the tag and returns the tag as stored, with the version the update has incremented. The update
+// of a conditional request only applies if the tag still has the version it was read with, repository.ErrNotFound is
+// returned otherwise.
+func (handler *TagHandler) writeTag(c *gin.Context, tag model.TagDAO, update bson.M) (model.TagDAO, error) {
+	query := bson.M{"_id": tag.Id}
+	if conditional(c) {
+		query = versionQuery(tag)
+	}
+	reservation, err := handler.reserveSequence(c)
+	if err != nil {
+		return model.TagDAO{}, err
+	}
+	defer handler.completeSequence(c, reservation)
+	return handler.tenant(c).Modify(DatabaseName, DatabaseCollection, query, withSequence(update, reservation.sequence))
+}
+
+// Applies the update to the tag only if it still has the version it was read with, repository.ErrNotFound is returned
+// otherwise.
+func (handler *TagHandler) writeVersion(c *gin.Context, tag model.TagDAO, update bson.M) error {
+	reservation, err := handler.reserveSequence(c)
+	if err != nil {
+		return err
+	}
+	defer handler.completeSequence(c, reservation)
+	return handler.tenant(c).UpdateOne(DatabaseName, DatabaseCollection, versionQuery(tag), withSequence(update, reservation.sequence))
+}
+
+// Tells whether the version-conditioned write of a tag failed because the tag has been modified since it was read.
+func staleVersion(err error) bool {
+	return errors.Is(err, repository.ErrNotFound)
+}
+
+// Checks the versions given with the items of a batch, keyed by position, against the versions of their tags. The
+// items whose tag has been modified since the client read it fail as with If-Match and are left out of the found tags.
+func checkBatchVersions(results []model.BatchResult, found map[int]model.TagDAO, versions map[int]int) {
+	for i, version := range versions {
+		if tag, ok := found[i]; ok && tag.Version != version {
+			results[i] = batchFailure(i, http.StatusPreconditionFailed, PreconditionFailMessage)
+			delete(found, i)
+		}
+	}
+}
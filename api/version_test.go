@@ -0,0 +1,132 @@
+package api
+
+import (
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Updating a tag with the ETag it was read with succeeds, and the same ETag is then stale.
+func TestPatchTagWithIfMatch(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, test.OrgID1)
+
+		req, _ := test.HttpRequest(nil, "/tags/"+id, http.MethodGet, test.Token1, test.OrgID1)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		read := w.Header().Get(ETagHeader)
+		if read == `"1"` {
+			t.Logf("\t\tThe created tag should have the ETag \"1\". %v", test.CheckMark)
+		} else {
+			t.Errorf("\t\tThe created tag should have the ETag \"1\":  \"%s\". %v", read, test.BallotX)
+		}
+
+		t.Logf("\tWhen Sending Patch tag request with the ETag read to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			colour := "Blue"
+			req, err := test.HttpRequest(model.PatchTagRequest{Colour: &colour}, "/tags/"+id, http.MethodPatch, test.Token1, test.OrgID1)
+			req.Header.Set(IfMatchHeader, read)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			if etag := w.Header().Get(ETagHeader); etag == `"2"` {
+				t.Logf("\t\tThe updated tag should have the ETag \"2\". %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe updated tag should have the ETag \"2\":  \"%s\". %v", etag, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen Sending Patch tag request with the stale ETag to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			colour := "Green"
+			req, err := test.HttpRequest(model.PatchTagRequest{Colour: &colour}, "/tags/"+id, http.MethodPatch, test.Token1, test.OrgID1)
+			req.Header.Set(IfMatchHeader, read)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+			test.CheckStatus(w, t, http.StatusPreconditionFailed)
+		}
+	}
+}
+
+// Updating a tag without If-Match returns the ETag of the version stored by the update.
+func TestPatchTagWithoutIfMatch(t *testing.T) {
+
+	t.Logf("Given I create a tag")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, test.OrgID1)
+
+		t.Logf("\tWhen Sending Patch tag requests without If-Match to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			var etag string
+			for _, colour := range []string{"Blue", "Green"} {
+				colour := colour
+				req, _ := test.HttpRequest(model.PatchTagRequest{Colour: &colour}, "/tags/"+id, http.MethodPatch, test.Token1, test.OrgID1)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				test.CheckStatus(w, t, http.StatusOK)
+				etag = w.Header().Get(ETagHeader)
+			}
+
+			req, _ := test.HttpRequest(nil, "/tags/"+id, http.MethodGet, test.Token1, test.OrgID1)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if read := w.Header().Get(ETagHeader); etag == `"3"` && read == etag {
+				t.Logf("\t\tThe updated tag should have the ETag of the stored version. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe updated tag should have the ETag of the stored version:  \"%s\" \"%s\". %v", etag, read, test.BallotX)
+			}
+		}
+	}
+}
+
+// Deleting a tag with a stale ETag fails, the tag is then deleted with its current ETag.
+func TestDeleteTagWithIfMatch(t *testing.T) {
+
+	t.Logf("Given I create a tag and rename it")
+	{
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, test.OrgID1)
+		name := test.UniqueName("Supper")
+		req, _ := test.HttpRequest(model.PatchTagRequest{Name: &name}, "/tags/"+id, http.MethodPatch, test.Token1, test.OrgID1)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		t.Logf("\tWhen Sending Delete tag request with the stale ETag to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			req, err := test.HttpRequest(nil, "/tags/"+id, http.MethodDelete, test.Token1, test.OrgID1)
+			req.Header.Set(IfMatchHeader, `"1"`)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+			test.CheckStatus(w, t, http.StatusPreconditionFailed)
+		}
+
+		t.Logf("\tWhen Sending Delete tag request with the current ETag to endpoint:  \"%s\"", "\\tags\\"+id)
+		{
+			req, err := test.HttpRequest(nil, "/tags/"+id, http.MethodDelete, test.Token1, test.OrgID1)
+			req.Header.Set(IfMatchHeader, `"2"`)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			test.Ok(err, t)
+			test.CheckStatus(w, t, http.StatusNoContent)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"net/http"
+)
+
+const (
+	TagVisibility           = "visibility"
+	ReadAccess              = "read"
+	WriteAccess             = "write"
+	ReadOnlyMessage         = "The tag is read-only, only its owner may change it"
+	ReadOnlyChildrenMessage = "The tag has child tags the account may not change"
+	VisibilityOwnerMessage  = "Only the owner of the tag may change its visibility"
+)
+
+// @Summary Change the visibility of a tag
+// @ID update-tag-visibility
+// @Description Makes the tag private to its owner, shared with the organisation or readable by the organisation and
+// @Description writable by its owner only. Only the owner of the tag may change its visibility.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Tag ID"
+// @Param visibility body model.UpdateVisibilityRequest true "New visibility"
+// @Param If-Match header string false "ETag of the tag as last read"
+// @Success 200 {object} model.Tag "Visibility updated"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The account does not own the tag, or the role of the account does not grant the tags:write permission"
+// @Failure 404 {object} model.EmptyBody "Tag not found"
+// @Failure 412 {object} model.ErrorResponse "The tag has been modified since it was read"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /tags/{id}/visibility [put]
+func (handler *TagHandler) UpdateVisibility(c *gin.Context) {
+	var req model.UpdateVisibilityRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+	if err := model.ValidateVisibility(req.Visibility); err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+
+	accountId := c.Request.Header.Get(AccountIDField)
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(TagId)
+	logger.Info.Printf("Received request to make tag \"%v\" %v for accountId \"%v\" and organisationId \"%v\"", id, req.Visibility, accountId, organisationId)
+	oid, ok := parseId(c, id)
+	if !ok {
+		return
+	}
+
+	tag, ok := handler.findOrganisationTag(c, oid, organisationId, ReadAccess)
+	if !ok || !checkIfMatch(c, tag) {
+		return
+	}
+	if tag.AccountId != accountId {
+		setErrorResponse(VisibilityOwnerMessage, http.StatusForbidden, c)
+		return
+	}
+	before := tag
+	tag.Visibility = req.Visibility
+	handler.updateTag(c, before, tag, bson.M{TagVisibility: tag.Visibility})
+}
+
+// Returns the visibility of a new tag, the default one when none is given.
+func tagVisibility(visibility string) (string, error) {
+	if visibility == "" {
+		return model.DefaultVisibility, nil
+	}
+	return visibility, model.ValidateVisibility(visibility)
+}
+
+// Returns the status and message denying the account the given access to the tag, or 0 when the access is allowed.
+// A tag the account may not see is reported as not found.
+func denial(tag model.TagDAO, organisationId string, accountId string, access string) (int, string) {
+	switch {
+	case !tag.ReadableBy(organisationId, accountId):
+		return http.StatusNotFound, "tag not found"
+	case access == WriteAccess && !tag.WritableBy(organisationId, accountId):
+		return http.StatusForbidden, ReadOnlyMessage
+	}
+	return 0, ""
+}
+
+// Restricts the query to the tags the account of the request may see: the private tags of the other accounts are
+// excluded.
+func readable(c *gin.Context, query bson.M) bson.M {
+	query["$nor"] = []bson.M{privateTo(c, "")}
+	return query
+}
+
+// Restricts a query on records holding versions of the tags, e.g. audit records, to the records whose versions under
+// the given fields the account of the request may all see.
+func readableVersions(c *gin.Context, query bson.M, fields ...string) bson.M {
+	hidden := make([]bson.M, len(fields))
+	for i, field := range fields {
+		hidden[i] = privateTo(c, field+".")
+	}
+	query["$nor"] = hidden
+	return query
+}
+
+// Matches the private tags of the accounts other than the one of the request, under the given path prefix.
+func privateTo(c *gin.Context, prefix string) bson.M {
+	return bson.M{prefix + TagVisibility: model.VisibilityPrivate, prefix + AccountId: bson.M{"$ne": c.Request.Header.Get(AccountIDField)}}
+}
+
+// Validates the parent of a tag among all the tags of the organisation, so that the ancestors the account may not see
+// are still taken into account, a parent the account may not see being reported as not found.
+func visibleParent(c *gin.Context, tags []model.TagDAO, id bson.ObjectId, parentId string) (bson.ObjectId, error) {
+	oid, err := model.ValidateParent(tags, id, parentId)
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		if tag.Id == oid && !tag.ReadableBy(c.Request.Header.Get(OrganisationIDField), c.Request.Header.Get(AccountIDField)) {
+			return "", errors.New("parent tag not found")
+		}
+	}
+	return oid, nil
+}
+
+// Returns the tags the account of the request may see among the tags of its organisation.
+func readableTags(c *gin.Context, tags []model.TagDAO) []model.TagDAO {
+	return model.Readable(tags, c.Request.Header.Get(OrganisationIDField), c.Request.Header.Get(AccountIDField))
+}
+
+// Tells whether the account of the organisation may change all the tags with the given ids.
+func writableIds(byId map[bson.ObjectId]model.TagDAO, ids []bson.ObjectId, organisationId string, accountId string) bool {
+	for _, id := range ids {
+		if !byId[id].WritableBy(organisationId, accountId) {
+			return false
+		}
+	}
+	return true
+}
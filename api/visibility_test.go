@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Creates a private and a read-only tag and checks the other accounts of the organisation may not see or change them.
+func TestTagVisibility(t *testing.T) {
+
+	t.Logf("Given a private and a read-only tag created by the first account")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		giveRole(orgId, test.AccountID2, model.RoleEditor)
+		private := test.CreateTag(model.CreateTagRequest{Name: "Diary", Colour: "Red", Visibility: model.VisibilityPrivate}, router, t, test.Token1, orgId)
+		readOnly := test.CreateTag(model.CreateTagRequest{Name: "Policy", Colour: "Red", Visibility: model.VisibilityReadOnly}, router, t, test.Token1, orgId)
+
+		t.Logf("\tWhen the second account lists and reads the tags")
+		{
+			w := visibilityRequest(router, "/tags", http.MethodGet, nil, test.Token2, orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			var response model.GetAllTagResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			tags := response.Tags
+			if len(tags) == 1 && tags[0].Id == readOnly && tags[0].Visibility == model.VisibilityReadOnly {
+				t.Logf("\t\tOnly the read-only tag should be listed. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tOnly the read-only tag should be listed:  \"%v\". %v", tags, test.BallotX)
+			}
+			test.CheckStatus(visibilityRequest(router, "/tags/"+private, http.MethodGet, nil, test.Token2, orgId), t, http.StatusNotFound)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+readOnly, http.MethodGet, nil, test.Token2, orgId), t, http.StatusOK)
+		}
+
+		colour := "Blue"
+		patch := model.PatchTagRequest{Colour: &colour}
+
+		t.Logf("\tWhen the second account changes the tags")
+		{
+			test.CheckStatus(visibilityRequest(router, "/tags/"+private, http.MethodPatch, patch, test.Token2, orgId), t, http.StatusNotFound)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+readOnly, http.MethodPatch, patch, test.Token2, orgId), t, http.StatusForbidden)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+readOnly, http.MethodDelete, nil, test.Token2, orgId), t, http.StatusForbidden)
+
+			w := visibilityRequest(router, "/tags/batchDelete", http.MethodPost, model.BatchDeleteRequest{Ids: []string{private, readOnly}}, test.Token2, orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			var response model.BatchResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			checkBatchStatuses(response, []int{http.StatusNotFound, http.StatusForbidden}, t)
+		}
+
+		t.Logf("\tWhen the accounts change the visibility of the read-only tag")
+		{
+			body := model.UpdateVisibilityRequest{Visibility: model.VisibilityOrganisation}
+			test.CheckStatus(visibilityRequest(router, "/tags/"+readOnly+"/visibility", http.MethodPut, body, test.Token2, orgId), t, http.StatusForbidden)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+readOnly+"/visibility", http.MethodPut, model.UpdateVisibilityRequest{Visibility: "public"}, test.Token1, orgId), t, http.StatusBadRequest)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+readOnly+"/visibility", http.MethodPut, body, test.Token1, orgId), t, http.StatusOK)
+			test.CheckStatus(visibilityRequest(router, "/tags/"+readOnly, http.MethodPatch, patch, test.Token2, orgId), t, http.StatusOK)
+		}
+	}
+}
+
+// Checks the other accounts of the organisation may not reach a private tag through its name or its id.
+func TestPrivateTagReferences(t *testing.T) {
+
+	t.Logf("Given a private tag assigned to a document by the first account")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleEditor)
+		giveRole(orgId, test.AccountID2, model.RoleEditor)
+		private := test.CreateTag(model.CreateTagRequest{Name: "Diary", Colour: "Red", Visibility: model.VisibilityPrivate}, router, t, test.Token1, orgId)
+		resource := "/resources/document/" + bson.NewObjectId().Hex() + "/tags"
+		test.CheckStatus(visibilityRequest(router, resource, http.MethodPost, model.AssignTagsRequest{TagIds: []string{private}}, test.Token1, orgId), t, http.StatusNoContent)
+
+		t.Logf("\tWhen the second account creates a tag with the same name")
+		{
+			w := visibilityRequest(router, "/tags", http.MethodPost, model.CreateTagRequest{Name: "diary", Colour: "Blue"}, test.Token2, orgId)
+			test.CheckStatus(w, t, http.StatusConflict)
+			var response model.ErrorResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.ExistingId == "" {
+				t.Logf("\t\tThe id of the private tag should not be given. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe id of the private tag should not be given:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen the second account finds the resources and removes the tag by id")
+		{
+			w := visibilityRequest(router, "/resources?tags="+private, http.MethodGet, nil, test.Token2, orgId)
+			test.CheckStatus(w, t, http.StatusOK)
+			var response model.FindResourcesResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if len(response.Resources) == 0 {
+				t.Logf("\t\tNo resource should be found with the private tag. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tNo resource should be found with the private tag:  \"%v\". %v", response, test.BallotX)
+			}
+
+			test.CheckStatus(visibilityRequest(router, resource+"/"+private, http.MethodDelete, nil, test.Token2, orgId), t, http.StatusNotFound)
+			assignments, _ := Repository.FindAssignments(DatabaseName, AssignmentCollection, bson.M{AssignmentTagId: bson.ObjectIdHex(private)})
+			if len(assignments) == 1 {
+				t.Logf("\t\tThe private tag should still be assigned. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe private tag should still be assigned:  \"%v\". %v", assignments, test.BallotX)
+			}
+		}
+	}
+}
+
+// helper function
+func visibilityRequest(router http.Handler, url string, method string, body interface{}, token string, orgId string) *httptest.ResponseRecorder {
+	req, _ := test.HttpRequest(body, url, method, token, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
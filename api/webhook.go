@@ -0,0 +1,403 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/events"
+	"github.com/tag-service/logger"
+	"github.com/tag-service/model"
+	"github.com/tag-service/repository"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	WebhookCollection   = "webhooks"
+	DeliveryCollection  = "deliveries"
+	WebhookId           = "webhookId"
+	WebhookIdParam      = "id"
+	DeliveryStatus      = "status"
+	DeliveryEventId     = "event.id"
+	NextAttemptAt       = "nextAttemptAt"
+	StatusParam         = "status"
+	MaxDeliveryAttempts = 10
+	DeliveryInterval    = 5 * time.Second
+	DeliveryBatchSize   = 100
+	DeliveryWorkers     = 10
+	WebhookTimeout      = 10 * time.Second
+	// a claimed delivery is left to its instance for this long, well beyond the time an attempt may take
+	DeliveryClaim = 3 * WebhookTimeout
+)
+
+// Tells whether a webhook may be reached at the address, the addresses of the internal network are refused so that the
+// organisations cannot make the service call it.
+var webhookAddressAllowed = events.PublicAddress
+
+// Client posting the deliveries, a webhook taking longer than the timeout to answer counts as a failed attempt.
+var webhookClient = events.NewClient(WebhookTimeout, func(ip net.IP) bool { return webhookAddressAllowed(ip) })
+
+// @Summary Register a webhook
+// @ID create-webhook
+// @Description Registers a callback URL receiving the tag events of the organisation. Each delivery is signed with
+// @Description the secret of the webhook in the X-Tag-Signature header, as sha256=<HMAC-SHA256 of the body>. The secret
+// @Description is generated when none is given, and is only returned here. The URL must resolve to public addresses.
+// @Accept  json
+// @Produce  json
+// @Param webhook body model.CreateWebhookRequest true "New webhook"
+// @Success 201 {object} model.CreateWebhookResponse "Webhook created"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /webhooks [post]
+func (handler *TagHandler) CreateWebhook(c *gin.Context) {
+	var req model.CreateWebhookRequest
+
+	if errB := c.ShouldBindWith(&req, binding.JSON); errB != nil {
+		logger.Error.Println(errB.Error())
+		setErrorResponse("Failed to parse Json request", http.StatusBadRequest, c)
+		return
+	}
+
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to register webhook \"%v\" for organisationId \"%v\"", req.Url, organisationId)
+
+	target, err := url.Parse(req.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		setErrorResponse("url must be an absolute http or https URL", http.StatusBadRequest, c)
+		return
+	}
+	// checked again with each delivery, the addresses of the host may change
+	if err := events.CheckHost(c.Request.Context(), target.Hostname(), webhookAddressAllowed); err != nil {
+		logger.Error.Printf("Refused the host of webhook \"%v\": %v", req.Url, err.Error())
+		setErrorResponse("url must resolve to public addresses", http.StatusBadRequest, c)
+		return
+	}
+	for _, eventType := range req.Events {
+		if eventType != model.TagCreated && eventType != model.TagUpdated && eventType != model.TagDeleted {
+			setErrorResponse("unknown event: "+eventType, http.StatusBadRequest, c)
+			return
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			setRepositoryError(err, "Failed to generate the secret", c)
+			return
+		}
+		secret = generated
+	}
+
+	webhook := model.WebhookDAO{Id: bson.NewObjectId(), OrganisationId: organisationId, Url: req.Url, Secret: secret, Events: req.Events, CreatedAt: time.Now()}
+	if err := handler.tenant(c).Insert(DatabaseName, WebhookCollection, &webhook); err != nil {
+		setRepositoryError(err, "Insert failed", c)
+		return
+	}
+	logger.Info.Printf("Webhook successfully registered \"%v\"", webhook.Id.Hex())
+	c.JSON(http.StatusCreated, model.CreateWebhookResponse{Id: webhook.Id.Hex(), Secret: secret})
+}
+
+// @Summary Get the webhooks
+// @ID get-webhooks
+// @Description Returns the webhooks registered by the organisation, without their secret
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.GetWebhooksResponse "ok"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /webhooks [get]
+func (handler *TagHandler) GetWebhooks(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	logger.Info.Printf("Received request to retrieve the webhooks for organisationId \"%v\"", organisationId)
+
+	webhooks, err := handler.tenant(c).FindWebhooks(DatabaseName, WebhookCollection, bson.M{OrganisationId: organisationId})
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.ConvertWebhooks(webhooks))
+}
+
+// @Summary Delete a webhook
+// @ID delete-webhook
+// @Description Removes the webhook together with its delivery log. The pending deliveries are dropped.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Webhook ID"
+// @Success 204 "Webhook deleted"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 404 {object} model.EmptyBody "Webhook not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /webhooks/{id} [delete]
+func (handler *TagHandler) DeleteWebhook(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(WebhookIdParam)
+	logger.Info.Printf("Received request to delete webhook \"%v\" for organisationId \"%v\"", id, organisationId)
+
+	if !bson.IsObjectIdHex(id) {
+		setErrorResponse("invalid webhook id: "+id, http.StatusBadRequest, c)
+		return
+	}
+
+	err := handler.tenant(c).RemoveOne(DatabaseName, WebhookCollection, bson.M{"_id": bson.ObjectIdHex(id), OrganisationId: organisationId})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, model.EmptyBody{})
+		return
+	}
+	if err != nil {
+		setRepositoryError(err, "Delete failed", c)
+		return
+	}
+
+	// the deliveries are of no use without the webhook, they are left for the worker to drop if this fails
+	if err := handler.tenant(c).RemoveAll(DatabaseName, DeliveryCollection, bson.M{WebhookId: bson.ObjectIdHex(id)}); err != nil {
+		logger.Error.Printf("Failed to remove the deliveries of webhook \"%v\": %v", id, err.Error())
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get the delivery log of a webhook
+// @ID get-webhook-deliveries
+// @Description Returns the deliveries of the webhook, the most recent first. A delivery is pending until it succeeds,
+// @Description failed attempts are retried with an exponential backoff and the delivery is dead once they are exhausted.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Only the deliveries with the given status: pending, delivered or dead"
+// @Param limit query int false "Maximum number of deliveries returned"
+// @Success 200 {object} model.GetDeliveriesResponse "ok"
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "The role of the account does not grant the tags:admin permission"
+// @Failure 404 {object} model.EmptyBody "Webhook not found"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Failure 503 {object} model.ErrorResponse "The database is unavailable, retry after the delay of the Retry-After header"
+// @Router /webhooks/{id}/deliveries [get]
+func (handler *TagHandler) GetDeliveries(c *gin.Context) {
+	organisationId := c.Request.Header.Get(OrganisationIDField)
+	id := c.Params.ByName(WebhookIdParam)
+	logger.Info.Printf("Received request to retrieve the deliveries of webhook \"%v\" for organisationId \"%v\"", id, organisationId)
+
+	if !bson.IsObjectIdHex(id) {
+		setErrorResponse("invalid webhook id: "+id, http.StatusBadRequest, c)
+		return
+	}
+	limit, err := queryLimit(c)
+	if err != nil {
+		setErrorResponse(err.Error(), http.StatusBadRequest, c)
+		return
+	}
+	query := bson.M{WebhookId: bson.ObjectIdHex(id)}
+	if status := c.Query(StatusParam); status != "" {
+		if status != model.DeliveryPending && status != model.DeliveryDelivered && status != model.DeliveryDead {
+			setErrorResponse("unknown status: "+status, http.StatusBadRequest, c)
+			return
+		}
+		query[DeliveryStatus] = status
+	}
+
+	webhooks, err := handler.tenant(c).FindWebhooks(DatabaseName, WebhookCollection, bson.M{"_id": bson.ObjectIdHex(id), OrganisationId: organisationId})
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	if len(webhooks) == 0 {
+		c.JSON(http.StatusNotFound, model.EmptyBody{})
+		return
+	}
+
+	deliveries, err := handler.tenant(c).FindDeliveries(DatabaseName, DeliveryCollection, query, "-_id", limit)
+	if err != nil {
+		setRepositoryError(err, "Failed to retrieve data from the database", c)
+		return
+	}
+	c.JSON(http.StatusOK, model.ConvertDeliveries(deliveries))
+}
+
+// Publishes the events to the webhooks of their organisation by queueing one delivery per subscribed webhook. The events
+// of the private tags are left out.
+type webhookPublisher struct {
+	handler *TagHandler
+}
+
+// WebhookPublisher returns the publisher queueing the deliveries of the events, to be relayed from the outbox along
+// with the configured publisher. The deliveries are then made by DeliverWebhooks.
+func (handler *TagHandler) WebhookPublisher() events.Publisher {
+	return webhookPublisher{handler}
+}
+
+func (publisher webhookPublisher) Publish(event model.Event) error {
+	// the webhooks belong to the whole organisation, which may not see the private tags of its accounts, as with the
+	// event stream
+	if event.Tag.Visibility == model.VisibilityPrivate {
+		return nil
+	}
+	repo := repository.NewTenantRepository(publisher.handler.unscoped, repository.Tenant{OrganisationId: event.OrganisationId})
+	webhooks, err := repo.FindWebhooks(DatabaseName, WebhookCollection, bson.M{OrganisationId: event.OrganisationId})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	docs := make([]interface{}, 0)
+	for _, webhook := range webhooks {
+		if webhook.Subscribed(event.Type) {
+			docs = append(docs, &model.DeliveryDAO{Id: bson.NewObjectId(), WebhookId: webhook.Id, OrganisationId: event.OrganisationId, Event: event,
+				Status: model.DeliveryPending, NextAttemptAt: now, CreatedAt: now})
+		}
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	failed, err := repo.BulkInsert(DatabaseName, DeliveryCollection, docs)
+	if err != nil {
+		return err
+	}
+	// the event may be published again, its deliveries are then already queued
+	for _, err := range failed {
+		if !errors.Is(err, repository.ErrConflict) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverWebhooks makes the deliveries due at the given time and returns the number of them which succeeded. Each
+// delivery is claimed before it is made, so that the instances of the service share them, and is made by one of
+// DeliveryWorkers workers, a webhook receiving one delivery at a time so that a slow endpoint only holds up its own
+// deliveries. A failed delivery is attempted again after a backoff doubling with each attempt, and is dead after
+// MaxDeliveryAttempts attempts.
+func (handler *TagHandler) DeliverWebhooks(now time.Time) (int, error) {
+	var mutex sync.Mutex
+	var workers sync.WaitGroup
+	busy := make(map[bson.ObjectId]bool)
+	// a delivery failing again is not attempted twice by the same pass
+	attempted := make([]bson.ObjectId, 0)
+	delivered := 0
+	var failure error
+	slots := make(chan struct{}, DeliveryWorkers)
+	finished := make(chan struct{}, DeliveryBatchSize)
+	// a claimed delivery is due again once the claim has expired, should the instance stop before recording it
+	claim := bson.M{"$set": bson.M{NextAttemptAt: now.Add(DeliveryClaim)}}
+	for claimed := 0; claimed < DeliveryBatchSize; {
+		slots <- struct{}{}
+		mutex.Lock()
+		skipped := make([]bson.ObjectId, 0, len(busy))
+		for webhookId := range busy {
+			skipped = append(skipped, webhookId)
+		}
+		mutex.Unlock()
+		due := bson.M{"_id": bson.M{"$nin": attempted}, DeliveryStatus: model.DeliveryPending, NextAttemptAt: bson.M{"$lte": now}, WebhookId: bson.M{"$nin": skipped}}
+		delivery, err := handler.unscoped.ClaimDelivery(DatabaseName, DeliveryCollection, due, NextAttemptAt, claim)
+		if errors.Is(err, repository.ErrNotFound) {
+			<-slots
+			// the deliveries left may be waiting for their webhook to receive the one being made
+			mutex.Lock()
+			waiting := len(busy) > 0
+			mutex.Unlock()
+			if !waiting {
+				break
+			}
+			<-finished
+			continue
+		}
+		if err != nil {
+			<-slots
+			mutex.Lock()
+			failure = err
+			mutex.Unlock()
+			break
+		}
+
+		claimed++
+		attempted = append(attempted, delivery.Id)
+		mutex.Lock()
+		busy[delivery.WebhookId] = true
+		mutex.Unlock()
+		workers.Add(1)
+		go func(delivery model.DeliveryDAO) {
+			defer workers.Done()
+			ok, err := handler.deliver(delivery)
+			mutex.Lock()
+			delete(busy, delivery.WebhookId)
+			if ok {
+				delivered++
+			}
+			if err != nil && failure == nil {
+				failure = err
+			}
+			mutex.Unlock()
+			<-slots
+			finished <- struct{}{}
+		}(delivery)
+	}
+	workers.Wait()
+	return delivered, failure
+}
+
+// Makes a claimed delivery and records the outcome of the attempt. Returns whether the delivery succeeded.
+func (handler *TagHandler) deliver(delivery model.DeliveryDAO) (bool, error) {
+	webhooks, err := handler.unscoped.FindWebhooks(DatabaseName, WebhookCollection, bson.M{"_id": delivery.WebhookId})
+	if err != nil {
+		return false, err
+	}
+	if len(webhooks) == 0 {
+		// the webhook was deleted while its deliveries were being removed
+		handler.unscoped.RemoveAll(DatabaseName, DeliveryCollection, bson.M{"_id": delivery.Id})
+		return false, nil
+	}
+	webhook := webhooks[0]
+	status, err := events.Deliver(webhookClient, webhook.Url, webhook.Secret, delivery.Id.Hex(), delivery.Event)
+	if err != nil {
+		logger.Error.Printf("Failed to deliver event \"%v\" to webhook \"%v\": %v", delivery.Event.Id, webhook.Id.Hex(), err.Error())
+	}
+	return err == nil, handler.unscoped.Update(DatabaseName, DeliveryCollection, delivery.Id, deliveryUpdate(delivery, status, err, time.Now()))
+}
+
+// DeliverWebhooksEvery makes the deliveries due once per interval. It never returns and is meant to run in its own
+// goroutine.
+func (handler *TagHandler) DeliverWebhooksEvery(interval time.Duration) {
+	for {
+		if _, err := handler.DeliverWebhooks(time.Now()); err != nil {
+			logger.Error.Printf("Failed to deliver the webhooks: %v", err.Error())
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Builds the update recording the outcome of an attempt of the delivery made at the given time.
+func deliveryUpdate(delivery model.DeliveryDAO, status int, err error, at time.Time) bson.M {
+	attempts := delivery.Attempts + 1
+	set := bson.M{"attempts": attempts, "lastStatusCode": status}
+	if err == nil {
+		set[DeliveryStatus] = model.DeliveryDelivered
+		set["deliveredAt"] = at
+		return bson.M{"$set": set, "$unset": bson.M{"lastError": ""}}
+	}
+	set["lastError"] = err.Error()
+	if attempts >= MaxDeliveryAttempts {
+		set[DeliveryStatus] = model.DeliveryDead
+	} else {
+		set[NextAttemptAt] = at.Add(events.Backoff(attempts))
+	}
+	return bson.M{"$set": set}
+}
+
+// Generates a random secret for a webhook registered without one.
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
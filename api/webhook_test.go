@@ -0,0 +1,310 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/events"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Registers a webhook, creates a tag and delivers its event to a test server checking the signature.
+func TestDeliverWebhook(t *testing.T) {
+
+	t.Logf("Given a webhook registered for the tag creations and a private tag")
+	{
+		var mutex sync.Mutex
+		received := make([]model.Event, 0)
+		var secret string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			body, _ := ioutil.ReadAll(r.Body)
+			if r.Header.Get(events.SignatureHeader) != events.Sign(secret, body) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var event model.Event
+			json.Unmarshal(body, &event)
+			received = append(received, event)
+		}))
+		defer server.Close()
+
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		mutex.Lock()
+		webhook := createWebhook(router, model.CreateWebhookRequest{Url: server.URL, Events: []string{model.TagCreated}}, orgId, t)
+		secret = webhook.Secret
+		mutex.Unlock()
+
+		test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Diary"), Colour: "Red", Visibility: model.VisibilityPrivate}, router, t, test.Token1, orgId)
+		id := test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+
+		t.Logf("\tWhen relaying the events and delivering the webhooks")
+		{
+			_, err := controller.RelayEvents(controller.WebhookPublisher())
+			test.Ok(err, t)
+			_, err = controller.DeliverWebhooks(time.Now())
+			test.Ok(err, t)
+
+			mutex.Lock()
+			if len(received) == 1 && received[0].TagId == id && received[0].Type == model.TagCreated {
+				t.Logf("\t\tThe signed event should have been delivered. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe signed event should have been delivered once:  \"%v\". %v", received, test.BallotX)
+			}
+			mutex.Unlock()
+
+			deliveries := getDeliveries(router, webhook.Id, orgId, t)
+			if len(deliveries.Deliveries) == 1 && deliveries.Deliveries[0].Status == model.DeliveryDelivered && deliveries.Deliveries[0].Attempts == 1 {
+				t.Logf("\t\tThe delivery log should show the delivery. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe delivery log should show the delivery:  \"%v\". %v", deliveries, test.BallotX)
+			}
+		}
+	}
+}
+
+// A delivery failing every attempt is retried with a backoff then dead.
+func TestDeliverWebhookDeadLetter(t *testing.T) {
+
+	t.Logf("Given a webhook which always fails")
+	{
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		webhook := createWebhook(router, model.CreateWebhookRequest{Url: server.URL}, orgId, t)
+		test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+		_, err := controller.RelayEvents(controller.WebhookPublisher())
+		test.Ok(err, t)
+
+		t.Logf("\tWhen the delivery is first attempted")
+		{
+			now := time.Now()
+			controller.DeliverWebhooks(now)
+
+			deliveries := getDeliveries(router, webhook.Id, orgId, t).Deliveries
+			if len(deliveries) == 1 && deliveries[0].Status == model.DeliveryPending && deliveries[0].Attempts == 1 &&
+				deliveries[0].LastStatusCode == http.StatusInternalServerError && deliveries[0].NextAttemptAt != nil && deliveries[0].NextAttemptAt.After(now) {
+				t.Logf("\t\tThe delivery should be retried later. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe delivery should be retried later:  \"%v\". %v", deliveries, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen all the attempts have failed")
+		{
+			for i := 1; i < MaxDeliveryAttempts; i++ {
+				controller.DeliverWebhooks(time.Now().Add(48 * time.Hour))
+			}
+
+			deliveries := getDeliveries(router, webhook.Id, orgId, t).Deliveries
+			if len(deliveries) == 1 && deliveries[0].Status == model.DeliveryDead && deliveries[0].Attempts == MaxDeliveryAttempts {
+				t.Logf("\t\tThe delivery should be dead. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe delivery should be dead:  \"%v\". %v", deliveries, test.BallotX)
+			}
+		}
+	}
+}
+
+// Delivers the events of two organisations, one of them answering slowly, at the same time.
+func TestDeliverWebhooksSlowEndpoint(t *testing.T) {
+
+	t.Logf("Given a slow and a fast webhook of two organisations")
+	{
+		fastReceived := make(chan bool, 1)
+		var mutex sync.Mutex
+		heldUp := false
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-fastReceived:
+			case <-time.After(WebhookTimeout / 2):
+				mutex.Lock()
+				heldUp = true
+				mutex.Unlock()
+			}
+		}))
+		defer slow.Close()
+		fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fastReceived <- true
+		}))
+		defer fast.Close()
+
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		for _, url := range []string{slow.URL, fast.URL} {
+			orgId := bson.NewObjectId().Hex()
+			giveRole(orgId, test.AccountID1, model.RoleAdmin)
+			createWebhook(router, model.CreateWebhookRequest{Url: url, Events: []string{model.TagCreated}}, orgId, t)
+			test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+		}
+		_, err := controller.RelayEvents(controller.WebhookPublisher())
+		test.Ok(err, t)
+
+		t.Logf("\tWhen delivering the webhooks")
+		{
+			delivered, err := controller.DeliverWebhooks(time.Now())
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err == nil && delivered >= 2 && !heldUp {
+				t.Logf("\t\tThe fast webhook should not wait for the slow one. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe fast webhook should not wait for the slow one:  \"%d\" \"%v\". %v", delivered, err, test.BallotX)
+			}
+		}
+	}
+}
+
+// Two instances of the service deliver the webhooks at the same time.
+func TestDeliverWebhooksClaimed(t *testing.T) {
+
+	t.Logf("Given a webhook and an event to deliver")
+	{
+		var mutex sync.Mutex
+		received := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			received++
+		}))
+		defer server.Close()
+
+		controller := NewTagHandler(Repository)
+		router := controller.CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		createWebhook(router, model.CreateWebhookRequest{Url: server.URL, Events: []string{model.TagCreated}}, orgId, t)
+		test.CreateTag(model.CreateTagRequest{Name: test.UniqueName("Dinner"), Colour: "Red"}, router, t, test.Token1, orgId)
+		_, err := controller.RelayEvents(controller.WebhookPublisher())
+		test.Ok(err, t)
+
+		t.Logf("\tWhen two instances deliver the webhooks")
+		{
+			var instances sync.WaitGroup
+			for i := 0; i < 2; i++ {
+				instances.Add(1)
+				go func() {
+					defer instances.Done()
+					NewTagHandler(Repository).DeliverWebhooks(time.Now())
+				}()
+			}
+			instances.Wait()
+
+			mutex.Lock()
+			if received == 1 {
+				t.Logf("\t\tThe event should be delivered once. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe event should be delivered once:  \"%d\". %v", received, test.BallotX)
+			}
+			mutex.Unlock()
+		}
+	}
+}
+
+// Lists and deletes the webhooks of an organisation.
+func TestGetAndDeleteWebhooks(t *testing.T) {
+
+	t.Logf("Given a webhook registered by an organisation")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		webhook := createWebhook(router, model.CreateWebhookRequest{Url: "https://example.com/hook", Secret: "secret"}, orgId, t)
+
+		t.Logf("\tWhen listing the webhooks")
+		{
+			req, _ := test.HttpRequest(nil, "/webhooks", http.MethodGet, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusOK)
+
+			var response model.GetWebhooksResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if len(response.Webhooks) == 1 && response.Webhooks[0].Id == webhook.Id && webhook.Secret == "secret" {
+				t.Logf("\t\tThe webhook should be listed. %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe webhook should be listed:  \"%v\". %v", response, test.BallotX)
+			}
+		}
+
+		t.Logf("\tWhen another organisation deletes the webhook")
+		{
+			otherOrgId := bson.NewObjectId().Hex()
+			giveRole(otherOrgId, test.AccountID1, model.RoleAdmin)
+			req, _ := test.HttpRequest(nil, "/webhooks/"+webhook.Id, http.MethodDelete, test.Token1, otherOrgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusNotFound)
+		}
+
+		t.Logf("\tWhen the organisation deletes the webhook")
+		{
+			req, _ := test.HttpRequest(nil, "/webhooks/"+webhook.Id, http.MethodDelete, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusNoContent)
+		}
+	}
+
+	t.Logf("Given a webhook with an invalid URL")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		req, _ := test.HttpRequest(model.CreateWebhookRequest{Url: "ftp://example.com"}, "/webhooks", http.MethodPost, test.Token1, orgId)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		test.CheckStatus(w, t, http.StatusBadRequest)
+	}
+
+	t.Logf("Given webhooks reaching into the internal network")
+	{
+		router := NewTagHandler(Repository).CreateRouter()
+		orgId := bson.NewObjectId().Hex()
+		giveRole(orgId, test.AccountID1, model.RoleAdmin)
+		for _, url := range []string{"http://169.254.169.254/latest/meta-data", "http://10.0.0.1:8080/hook", "https://192.168.1.1/hook", "http://[fd00::1]/hook"} {
+			req, _ := test.HttpRequest(model.CreateWebhookRequest{Url: url}, "/webhooks", http.MethodPost, test.Token1, orgId)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			test.CheckStatus(w, t, http.StatusBadRequest)
+		}
+	}
+}
+
+func createWebhook(router *gin.Engine, body model.CreateWebhookRequest, orgId string, t *testing.T) model.CreateWebhookResponse {
+	req, _ := test.HttpRequest(body, "/webhooks", http.MethodPost, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusCreated)
+
+	var response model.CreateWebhookResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	return response
+}
+
+func getDeliveries(router *gin.Engine, id string, orgId string, t *testing.T) model.GetDeliveriesResponse {
+	req, _ := test.HttpRequest(nil, "/webhooks/"+id+"/deliveries", http.MethodGet, test.Token1, orgId)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	test.CheckStatus(w, t, http.StatusOK)
+
+	var response model.GetDeliveriesResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	return response
+}
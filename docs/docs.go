@@ -43,26 +43,27 @@ var doc = `{
                 }
             }
         },
-        "/tags": {
+        "/keys": {
             "get": {
+                "description": "Returns the keys of the key/value tags of the organisation, sorted by key",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get tags",
-                "operationId": "get-tags",
+                "summary": "Get the keys",
+                "operationId": "get-keys",
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
                             "type": "object",
-                            "$ref": "#/definitions/model.GetAllTagResponse"
+                            "$ref": "#/definitions/model.GetKeysResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
                         "schema": {
                             "type": "object",
                             "$ref": "#/definitions/model.ErrorResponse"
@@ -74,37 +75,44 @@ var doc = `{
                             "type": "object",
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Creates new tag with metadata send",
+                "description": "Defines a key of the key/value tags of the organisation. The values of an enum key are restricted to\nthe given ones, the values of a free-form key are not.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Creates new tag",
-                "operationId": "create-tag",
+                "summary": "Define a key",
+                "operationId": "create-key",
                 "parameters": [
                     {
-                        "description": "New tag",
-                        "name": "new-tag",
+                        "description": "New key",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "object",
-                            "$ref": "#/definitions/model.CreateTagRequest"
+                            "$ref": "#/definitions/model.CreateKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Tag created",
+                        "description": "Key created",
                         "schema": {
                             "type": "object",
-                            "$ref": "#/definitions/model.CreateTagResponse"
+                            "$ref": "#/definitions/model.Key"
                         }
                     },
                     "400": {
@@ -114,90 +122,158 @@ var doc = `{
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The key already exists",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/tags/{id}": {
-            "get": {
+        "/keys/{key}": {
+            "put": {
+                "description": "Replaces the type and values of the key. The values used by tags must remain allowed.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get tag by ID",
-                "operationId": "get-tag",
+                "summary": "Replace a key",
+                "operationId": "update-key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tag ID",
-                        "name": "id",
+                        "description": "Key",
+                        "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Updated key",
+                        "name": "definition",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.UpdateKeyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ok",
+                        "description": "Key updated",
                         "schema": {
                             "type": "object",
-                            "$ref": "#/definitions/model.Tag"
+                            "$ref": "#/definitions/model.Key"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Tag not found",
+                        "description": "Key not found",
                         "schema": {
                             "type": "object",
                             "$ref": "#/definitions/model.EmptyBody"
                         }
                     },
+                    "409": {
+                        "description": "Tags use values which would no longer be allowed",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     }
                 }
             },
             "delete": {
+                "description": "Removes the key, which may not be used by any tag",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Delete tag by ID",
-                "operationId": "delete-tag",
+                "summary": "Delete a key",
+                "operationId": "delete-key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tag ID",
-                        "name": "id",
+                        "description": "Key",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Tag deleted"
+                        "description": "Key deleted"
                     },
-                    "404": {
-                        "description": "Tag not found",
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
                         "schema": {
                             "type": "object",
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
                     "409": {
-                        "description": "The given tag id does not belong to the user",
+                        "description": "The key is used by tags",
                         "schema": {
                             "type": "object",
                             "$ref": "#/definitions/model.ErrorResponse"
@@ -209,67 +285,3380 @@ var doc = `{
                             "type": "object",
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.CreateTagRequest": {
-            "type": "object",
-            "properties": {
-                "Colour": {
-                    "type": "string"
-                },
-                "Name": {
-                    "type": "string"
-                }
-            }
         },
-        "model.CreateTagResponse": {
-            "type": "object",
-            "properties": {
-                "Id": {
-                    "type": "string"
+        "/members": {
+            "get": {
+                "description": "Returns the accounts given a role within the organisation. The other accounts get their role from their token or the default role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the roles of the accounts",
+                "operationId": "get-members",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.GetMembersResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "model.EmptyBody": {
-            "type": "object"
-        },
-        "model.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "Code": {
-                    "type": "integer"
-                },
-                "Message": {
-                    "type": "string"
+        "/members/{accountId}": {
+            "put": {
+                "description": "Gives the account the viewer, editor or admin role within the organisation. The role is used when the token of the account carries none.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Give a role to an account",
+                "operationId": "update-member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.UpdateMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role updated",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Member"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
                 }
-            }
-        },
-        "model.GetAllTagResponse": {
-            "type": "object",
-            "properties": {
-                "Tags": {
-                    "type": "array"
+            },
+            "delete": {
+                "description": "The account gets its role from its token or the default role again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove the role of an account",
+                "operationId": "delete-member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Role removed"
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The account has no role",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "model.Tag": {
-            "type": "object",
-            "properties": {
-                "Colour": {
-                    "type": "string"
-                },
+        "/palette": {
+            "get": {
+                "description": "Returns the colours the tags of the organisation may use. The default palette is returned until the organisation defines its own, any colour is allowed in that case.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the colour palette",
+                "operationId": "get-palette",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Palette"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the colours the tags of the organisation may use. Existing tags keep their colour.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Define the colour palette",
+                "operationId": "update-palette",
+                "parameters": [
+                    {
+                        "description": "Palette colours",
+                        "name": "palette",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.UpdatePaletteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Palette updated",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Palette"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/resources": {
+            "get": {
+                "description": "Returns the resources whose tags match the boolean expression, e.g. (urgent AND finance) AND NOT archived.\nTerms are tag names or tag ids, names containing spaces or operators must be double quoted.\nThe resources may also be filtered by the key of their key/value tags, in which case the expression is optional.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Find resources by tags",
+                "operationId": "find-resources",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag expression, required unless a key is given",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of one of the key/value tags of the resources",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value of the key/value tag, along with the key",
+                        "name": "value",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of resources returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.FindResourcesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/resources/{type}/{id}/tags": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the tags of a resource",
+                "operationId": "get-resource-tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages of the tag names",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.GetAllTagResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Applies the given tags to the resource. Tags already applied are left untouched.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Assign tags to a resource",
+                "operationId": "assign-tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags to assign",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.AssignTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Tags assigned"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/resources/{type}/{id}/tags/{tagId}": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove a tag from a resource",
+                "operationId": "unassign-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "tagId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Tag removed"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schema": {
+            "get": {
+                "description": "Returns the attribute fields the tags of the organisation may carry. Until the organisation defines its\nown schema, any attribute is allowed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the attribute schema",
+                "operationId": "get-schema",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Schema"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the attribute fields the tags of the organisation may carry: their type, whether they are\nrequired and the maximum length of the strings. The tags are validated against it when next written.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Define the attribute schema",
+                "operationId": "update-schema",
+                "parameters": [
+                    {
+                        "description": "Attribute fields",
+                        "name": "schema",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.UpdateSchemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema updated",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Schema"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Returns all the tags unless one of the paging parameters is set, in which case one page is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get tags",
+                "operationId": "get-tags",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of tags returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: name (default), -name, createdAt or -createdAt",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the total number of tags",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the key/value tags with this key",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the key/value tags with this value, along with the key",
+                        "name": "value",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages of the tag names",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.GetAllTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new tag with metadata send",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates new tag",
+                "operationId": "create-tag",
+                "parameters": [
+                    {
+                        "description": "New tag",
+                        "name": "new-tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tag created",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.CreateTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A tag with the same name already exists",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/batchCreate": {
+            "post": {
+                "description": "Creates up to 500 tags. Each tag is validated as by the create endpoint and gets its own status, the valid tags are created even when others are rejected. The parent of a tag must already exist, the tags of a batch getting their ids once created they cannot be the parents of one another.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Create tags in batch",
+                "operationId": "batch-create-tags",
+                "parameters": [
+                    {
+                        "description": "New tags",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.BatchCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status of each tag, 201 when created",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/batchDelete": {
+            "post": {
+                "description": "Deletes up to 500 tags. Each tag gets its own status, the tags which can be deleted are deleted even when others are rejected. Child tags also deleted in the batch do not prevent the deletion of their parent. The deleted tags are moved to the trash. A tag given with the version of its ETag is only deleted while it still has it, with the status 412 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete tags in batch",
+                "operationId": "batch-delete-tags",
+                "parameters": [
+                    {
+                        "description": "Tag IDs",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.BatchDeleteRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "What happens to the child tags: reject (default), cascade or reparent",
+                        "name": "children",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status of each tag, 204 when deleted",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/batchUpdate": {
+            "post": {
+                "description": "Updates up to 500 tags, only the fields present are updated. Each tag is validated as by the patch endpoint and gets its own status, the valid tags are updated even when others are rejected. A tag given with the version of its ETag is only updated while it still has it, with the status 412 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update tags in batch",
+                "operationId": "batch-update-tags",
+                "parameters": [
+                    {
+                        "description": "Fields to update",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.BatchUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status of each tag, 200 when updated",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/changes": {
+            "get": {
+                "description": "Returns the tags created or updated since the sync token, in their current state, and the tags deleted\nsince as tombstones. Without a token all the tags are returned. A change may be returned by two\nconsecutive syncs. The token expires once the deleted tags it would report have been purged. The tags made private by another account since are reported as deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the changes made to the tags",
+                "operationId": "get-changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sync token returned by the previous sync",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "The sync token has expired",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/events": {
+            "get": {
+                "description": "Streams the events of the tags of the organisation as Server-Sent Events, named TagCreated, TagUpdated\nor TagDeleted. The stream starts with the events occurring after the connection, or after the event\ngiven in the Last-Event-ID header when resuming. Events are sent a couple of seconds after they occur. The events of the private tags of the other accounts are not streamed.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "Stream the tag events",
+                "operationId": "stream-events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/export": {
+            "get": {
+                "description": "Returns the tags of the organisation visible to the account as a file which can be imported, the parents before their children. The parents are given by name.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "summary": "Export the tags",
+                "operationId": "export-tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv or json, json by default",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "$ref": "#/definitions/model.TransferTag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/history": {
+            "get": {
+                "description": "Returns the changes made to the tags of the organisation, the most recent first. The changes of the private tags of the other accounts are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the history of the tags",
+                "operationId": "get-history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of changes returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.HistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/import": {
+            "post": {
+                "description": "Creates the tags of a CSV or JSON file as exported, up to 5000 rows. Parents are given by name, either\nof an existing tag or of another row. A row named as an existing tag is skipped, overwrites the tag,\nor fails the whole import depending on the mode. Invalid rows are reported and not imported. The rows overwriting a tag the account may not change are invalid, the imported tags are shared with the organisation.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Import tags",
+                "operationId": "import-tags",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or JSON file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or json, taken from the file extension by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "skip, overwrite or fail, fail by default",
+                        "name": "mode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Outcome of each row",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Some rows are named as existing tags, nothing has been imported",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ImportResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/search": {
+            "get": {
+                "description": "Returns the tags of the organisation whose name matches the query, most relevant first: exact matches, then prefix, word prefix, substring and finally approximate matches tolerating typos. Case, accents and character width are ignored. The translations of the names are searched as well.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Search tags",
+                "operationId": "search-tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of tags returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages of the tag names",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.GetAllTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/stats": {
+            "get": {
+                "description": "Returns the number of assignments of the tags of the organisation, the most used tags first, and the\ntags which have never been assigned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the usage of the tags",
+                "operationId": "get-stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of most used tags returned",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.StatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/trash": {
+            "get": {
+                "description": "Returns the deleted tags of the organisation visible to the account which have not been purged yet, the most recently deleted first, one page at a time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the trash",
+                "operationId": "get-trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of tags returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.TrashResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/tree": {
+            "get": {
+                "description": "Returns the tags of the organisation nested under their parent tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the tag tree",
+                "operationId": "get-tag-tree",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.TagTreeResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                },
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Preferred languages of the tag names",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ]
+            }
+        },
+        "/tags/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get tag by ID",
+                "operationId": "get-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages of the tag names",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag id",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Moves the tag to the trash, from which it can be restored until it is purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete tag by ID",
+                "operationId": "delete-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "What happens to the child tags: reject (default), cascade or reparent",
+                        "name": "children",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the tag as last read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Tag deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The tag or one of its descendants is read-only, or the role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The tag has child tags",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The tag has been modified since it was read",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the name, colour and attributes of the given tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Replace tag by ID",
+                "operationId": "update-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated tag",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.UpdateTagRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the tag as last read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag updated",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The tag is read-only, or the role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "409": {
+                        "description": "A tag with the same name already exists",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The tag has been modified since it was read",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates only the fields present in the payload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Partially update tag by ID",
+                "operationId": "patch-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.PatchTagRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the tag as last read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag updated",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The tag is read-only, or the role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "409": {
+                        "description": "A tag with the same name already exists",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The tag has been modified since it was read",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/descendants": {
+            "get": {
+                "description": "Returns the child tags of the given tag, nested under their own parent tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the descendants of a tag",
+                "operationId": "get-tag-descendants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages of the tag names",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.TagTreeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag id",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/history": {
+            "get": {
+                "description": "Returns the changes made to the tag, the most recent first. The history outlives the tag.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the history of a tag",
+                "operationId": "get-tag-history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of changes returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.HistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/restore": {
+            "post": {
+                "description": "Takes the tag out of the trash, together with the descendants deleted along with it. The tag is restored at the root of the tree when its parent is no longer available.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Restore a deleted tag",
+                "operationId": "restore-tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the tag as last read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag restored",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag id",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The tag or one of its descendants is read-only, or the role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "409": {
+                        "description": "The tag is not in the trash or a tag with the same name already exists",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The tag has been modified since it was read",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/stats": {
+            "get": {
+                "description": "Returns the number of assignments of the tag, in total and per resource type, the most used type first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the usage of a tag",
+                "operationId": "get-tag-stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.TagStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag id",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:read permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/visibility": {
+            "put": {
+                "description": "Makes the tag private to its owner, shared with the organisation or readable by the organisation and writable by its owner only. Only the owner of the tag may change its visibility.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Change the visibility of a tag",
+                "operationId": "update-tag-visibility",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New visibility",
+                        "name": "visibility",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.UpdateVisibilityRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the tag as last read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Visibility updated",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The account does not own the tag, or the role of the account does not grant the tags:write permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "412": {
+                        "description": "The tag has been modified since it was read",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Returns the webhooks registered by the organisation, without their secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the webhooks",
+                "operationId": "get-webhooks",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.GetWebhooksResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registers a callback URL receiving the tag events of the organisation. Each delivery is signed with\nthe secret of the webhook in the X-Tag-Signature header, as sha256=<HMAC-SHA256 of the body>. The secret\nis generated when none is given, and is only returned here. The URL must resolve to public addresses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Register a webhook",
+                "operationId": "create-webhook",
+                "parameters": [
+                    {
+                        "description": "New webhook",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook created",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.CreateWebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "description": "Removes the webhook together with its delivery log. The pending deliveries are dropped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a webhook",
+                "operationId": "delete-webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Returns the deliveries of the webhook, the most recent first. A delivery is pending until it succeeds,\nfailed attempts are retried with an exponential backoff and the delivery is dead once they are exhausted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the delivery log of a webhook",
+                "operationId": "get-webhook-deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the deliveries with the given status: pending, delivered or dead",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries returned",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.GetDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the account does not grant the tags:admin permission",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.EmptyBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "The database is unavailable, retry after the delay of the Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AssignTagsRequest": {
+            "type": "object",
+            "properties": {
+                "TagIds": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.AttributeField": {
+            "type": "object",
+            "properties": {
+                "Type": {
+                    "type": "string"
+                },
+                "Required": {
+                    "type": "boolean"
+                },
+                "MaxLength": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BatchCreateRequest": {
+            "type": "object",
+            "properties": {
+                "Tags": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.BatchDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "Ids": {
+                    "type": "array"
+                },
+                "Versions": {
+                    "type": "object"
+                }
+            }
+        },
+        "model.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "Results": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.BatchResult": {
+            "type": "object",
+            "properties": {
+                "ExistingId": {
+                    "type": "string"
+                },
+                "Id": {
+                    "type": "string"
+                },
+                "Index": {
+                    "type": "integer"
+                },
+                "Message": {
+                    "type": "string"
+                },
+                "Status": {
+                    "type": "integer"
+                },
+                "Errors": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.BatchUpdateItem": {
+            "type": "object",
+            "properties": {
+                "Colour": {
+                    "type": "string"
+                },
+                "Id": {
+                    "type": "string"
+                },
+                "Version": {
+                    "type": "integer"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "ParentId": {
+                    "type": "string"
+                },
+                "Value": {
+                    "type": "string"
+                },
+                "Attributes": {
+                    "type": "object"
+                },
+                "Translations": {
+                    "type": "object"
+                }
+            }
+        },
+        "model.BatchUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "Tags": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.ChangesResponse": {
+            "type": "object",
+            "properties": {
+                "Tags": {
+                    "type": "array"
+                },
+                "Deleted": {
+                    "type": "array"
+                },
+                "SyncToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateKeyRequest": {
+            "type": "object",
+            "properties": {
+                "Key": {
+                    "type": "string"
+                },
+                "Type": {
+                    "type": "string"
+                },
+                "Values": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.CreateTagRequest": {
+            "type": "object",
+            "properties": {
+                "Colour": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "ParentId": {
+                    "type": "string"
+                },
+                "Key": {
+                    "type": "string"
+                },
+                "Value": {
+                    "type": "string"
+                },
+                "Attributes": {
+                    "type": "object"
+                },
+                "Translations": {
+                    "type": "object"
+                },
+                "Visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateTagResponse": {
+            "type": "object",
+            "properties": {
+                "Id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "Url": {
+                    "type": "string"
+                },
+                "Secret": {
+                    "type": "string"
+                },
+                "Events": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.CreateWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "Id": {
+                    "type": "string"
+                },
+                "Secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Delivery": {
+            "type": "object",
+            "properties": {
+                "Id": {
+                    "type": "string"
+                },
+                "EventId": {
+                    "type": "string"
+                },
+                "EventType": {
+                    "type": "string"
+                },
+                "Status": {
+                    "type": "string"
+                },
+                "Attempts": {
+                    "type": "integer"
+                },
+                "NextAttemptAt": {
+                    "type": "string"
+                },
+                "LastStatusCode": {
+                    "type": "integer"
+                },
+                "LastError": {
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "DeliveredAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EmptyBody": {
+            "type": "object"
+        },
+        "model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "Code": {
+                    "type": "integer"
+                },
+                "ExistingId": {
+                    "type": "string"
+                },
+                "Message": {
+                    "type": "string"
+                },
+                "Errors": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.Event": {
+            "type": "object",
+            "properties": {
+                "Id": {
+                    "type": "string"
+                },
+                "Type": {
+                    "type": "string"
+                },
+                "OrganisationId": {
+                    "type": "string"
+                },
+                "TagId": {
+                    "type": "string"
+                },
+                "AccountId": {
+                    "type": "string"
+                },
+                "Tag": {
+                    "type": "object"
+                },
+                "Changes": {
+                    "type": "array"
+                },
+                "OccurredAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FieldError": {
+            "type": "object",
+            "properties": {
+                "Field": {
+                    "type": "string"
+                },
+                "Message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FindResourcesResponse": {
+            "type": "object",
+            "properties": {
+                "NextCursor": {
+                    "type": "string"
+                },
+                "Resources": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.GetAllTagResponse": {
+            "type": "object",
+            "properties": {
+                "Tags": {
+                    "type": "array"
+                },
+                "NextCursor": {
+                    "type": "string"
+                },
+                "Total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.GetDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "Deliveries": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.GetKeysResponse": {
+            "type": "object",
+            "properties": {
+                "Keys": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.GetMembersResponse": {
+            "type": "object",
+            "properties": {
+                "Members": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.GetWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "Webhooks": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.HistoryEntry": {
+            "type": "object",
+            "properties": {
+                "AccountId": {
+                    "type": "string"
+                },
+                "After": {
+                    "type": "object",
+                    "$ref": "#/definitions/model.Tag"
+                },
+                "Before": {
+                    "type": "object",
+                    "$ref": "#/definitions/model.Tag"
+                },
+                "Changes": {
+                    "type": "array"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "Id": {
+                    "type": "string"
+                },
+                "Operation": {
+                    "type": "string"
+                },
+                "TagId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HistoryResponse": {
+            "type": "object",
+            "properties": {
+                "Entries": {
+                    "type": "array"
+                },
+                "NextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "Created": {
+                    "type": "integer"
+                },
+                "Updated": {
+                    "type": "integer"
+                },
+                "Skipped": {
+                    "type": "integer"
+                },
+                "Failed": {
+                    "type": "integer"
+                },
+                "Rows": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.ImportResult": {
+            "type": "object",
+            "properties": {
+                "Row": {
+                    "type": "integer"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "Status": {
+                    "type": "string"
+                },
+                "Id": {
+                    "type": "string"
+                },
+                "ExistingId": {
+                    "type": "string"
+                },
+                "Message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Key": {
+            "type": "object",
+            "properties": {
+                "Key": {
+                    "type": "string"
+                },
+                "Type": {
+                    "type": "string"
+                },
+                "Values": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.Member": {
+            "type": "object",
+            "properties": {
+                "AccountId": {
+                    "type": "string"
+                },
+                "Role": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Palette": {
+            "type": "object",
+            "properties": {
+                "Colours": {
+                    "type": "array"
+                },
+                "Custom": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.PaletteColour": {
+            "type": "object",
+            "properties": {
+                "Hex": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PatchTagRequest": {
+            "type": "object",
+            "properties": {
+                "Colour": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "ParentId": {
+                    "type": "string"
+                },
+                "Value": {
+                    "type": "string"
+                },
+                "Attributes": {
+                    "type": "object"
+                },
+                "Translations": {
+                    "type": "object"
+                }
+            }
+        },
+        "model.ResourceRef": {
+            "type": "object",
+            "properties": {
+                "ResourceId": {
+                    "type": "string"
+                },
+                "ResourceType": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ResourceTypeUsage": {
+            "type": "object",
+            "properties": {
+                "ResourceType": {
+                    "type": "string"
+                },
+                "Count": {
+                    "type": "integer"
+                },
+                "LastUsedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Schema": {
+            "type": "object",
+            "properties": {
+                "Fields": {
+                    "type": "object"
+                },
+                "AdditionalFields": {
+                    "type": "boolean"
+                },
+                "Custom": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "TagCount": {
+                    "type": "integer"
+                },
+                "AssignmentCount": {
+                    "type": "integer"
+                },
+                "MostUsed": {
+                    "type": "array"
+                },
+                "Unused": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.Tag": {
+            "type": "object",
+            "properties": {
+                "Colour": {
+                    "type": "string"
+                },
+                "Id": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "AccountId": {
+                    "type": "string"
+                },
+                "ParentId": {
+                    "type": "string"
+                },
+                "Key": {
+                    "type": "string"
+                },
+                "Value": {
+                    "type": "string"
+                },
+                "Attributes": {
+                    "type": "object"
+                },
+                "Translations": {
+                    "type": "object"
+                },
+                "Visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TagNode": {
+            "type": "object",
+            "properties": {
+                "Children": {
+                    "type": "array"
+                },
+                "Colour": {
+                    "type": "string"
+                },
+                "Id": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "ParentId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TagStats": {
+            "type": "object",
+            "properties": {
+                "Id": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "Colour": {
+                    "type": "string"
+                },
+                "Count": {
+                    "type": "integer"
+                },
+                "LastUsedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TagStatsResponse": {
+            "type": "object",
+            "properties": {
+                "Id": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "Colour": {
+                    "type": "string"
+                },
+                "Count": {
+                    "type": "integer"
+                },
+                "LastUsedAt": {
+                    "type": "string"
+                },
+                "ResourceTypes": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.TagTreeResponse": {
+            "type": "object",
+            "properties": {
+                "Tags": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.Tombstone": {
+            "type": "object",
+            "properties": {
+                "Id": {
+                    "type": "string"
+                },
+                "DeletedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TransferTag": {
+            "type": "object",
+            "properties": {
                 "Id": {
                     "type": "string"
                 },
                 "Name": {
                     "type": "string"
                 },
+                "Colour": {
+                    "type": "string"
+                },
+                "Parent": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TrashResponse": {
+            "type": "object",
+            "properties": {
+                "Tags": {
+                    "type": "array"
+                },
+                "NextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TrashedTag": {
+            "type": "object",
+            "properties": {
                 "AccountId": {
                     "type": "string"
+                },
+                "Colour": {
+                    "type": "string"
+                },
+                "DeletedAt": {
+                    "type": "string"
+                },
+                "Id": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "ParentId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UpdateKeyRequest": {
+            "type": "object",
+            "properties": {
+                "Type": {
+                    "type": "string"
+                },
+                "Values": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.UpdateMemberRequest": {
+            "type": "object",
+            "properties": {
+                "Role": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UpdatePaletteRequest": {
+            "type": "object",
+            "properties": {
+                "Colours": {
+                    "type": "array"
+                }
+            }
+        },
+        "model.UpdateSchemaRequest": {
+            "type": "object",
+            "properties": {
+                "Fields": {
+                    "type": "object"
+                },
+                "AdditionalFields": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.UpdateTagRequest": {
+            "type": "object",
+            "properties": {
+                "Colour": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "ParentId": {
+                    "type": "string"
+                },
+                "Attributes": {
+                    "type": "object"
+                },
+                "Translations": {
+                    "type": "object"
+                }
+            }
+        },
+        "model.UpdateVisibilityRequest": {
+            "type": "object",
+            "properties": {
+                "Visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "properties": {
+                "Id": {
+                    "type": "string"
+                },
+                "Url": {
+                    "type": "string"
+                },
+                "Events": {
+                    "type": "array"
+                },
+                "CreatedAt": {
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,104 @@
+// Package events delivers the tag lifecycle events read from the outbox to the services interested in them. The
+// Publisher interface hides the transport, so that the relay can be tested without a broker.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/tag-service/model"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// MemoryPublisherKind keeps the events in memory, mostly for tests.
+	MemoryPublisherKind = "memory"
+
+	// NDJSONPublisherKind appends the events to a file, one JSON document per line.
+	NDJSONPublisherKind = "ndjson"
+)
+
+// Publisher delivers the events. An error leaves the event in the outbox so that it is published again later, so
+// implementations must cope with the same event being delivered more than once.
+type Publisher interface {
+	Publish(event model.Event) error
+}
+
+// MemoryPublisher keeps the published events in memory.
+type MemoryPublisher struct {
+	mutex  sync.Mutex
+	events []model.Event
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{events: make([]model.Event, 0)}
+}
+
+func (publisher *MemoryPublisher) Publish(event model.Event) error {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	publisher.events = append(publisher.events, event)
+	return nil
+}
+
+// Events returns a copy of the events published so far, in the order they were published.
+func (publisher *MemoryPublisher) Events() []model.Event {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	return append([]model.Event{}, publisher.events...)
+}
+
+// NDJSONPublisher writes each event as one line of JSON.
+type NDJSONPublisher struct {
+	mutex sync.Mutex
+	out   io.Writer
+}
+
+func NewNDJSONPublisher(out io.Writer) *NDJSONPublisher {
+	return &NDJSONPublisher{out: out}
+}
+
+// OpenNDJSONFile returns a publisher appending the events to the given file, which is created if needed.
+func OpenNDJSONFile(path string) (*NDJSONPublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewNDJSONPublisher(file), nil
+}
+
+func (publisher *NDJSONPublisher) Publish(event model.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	_, err = publisher.out.Write(append(line, '\n'))
+	return err
+}
+
+// NewPublisher returns the publisher of the given kind, the path is the file written by the NDJSON publisher.
+func NewPublisher(kind string, path string) (Publisher, error) {
+	switch kind {
+	case MemoryPublisherKind:
+		return NewMemoryPublisher(), nil
+	case NDJSONPublisherKind:
+		return OpenNDJSONFile(path)
+	}
+	return nil, fmt.Errorf("unknown event publisher: %v", kind)
+}
+
+// Publishers publishes each event to all the publishers in turn, stopping at the first failure. The event is then
+// published again to all of them, so the publishers which succeeded receive it twice.
+type Publishers []Publisher
+
+func (publishers Publishers) Publish(event model.Event) error {
+	for _, publisher := range publishers {
+		if err := publisher.Publish(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/tag-service/model"
+	"testing"
+)
+
+const (
+	CheckMark = "\u2713"
+	BallotX   = "\u2717"
+)
+
+func TestMemoryPublisher(t *testing.T) {
+	t.Logf("Given a memory publisher")
+	{
+		publisher := NewMemoryPublisher()
+		publisher.Publish(model.Event{Id: "1", Type: model.TagCreated})
+		publisher.Publish(model.Event{Id: "2", Type: model.TagDeleted})
+
+		if events := publisher.Events(); len(events) == 2 && events[0].Id == "1" && events[1].Id == "2" {
+			t.Logf("\t\tThe events should be kept in order. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe events should be kept in order:  \"%v\". %v", events, BallotX)
+		}
+	}
+}
+
+func TestNDJSONPublisher(t *testing.T) {
+	t.Logf("Given a NDJSON publisher")
+	{
+		var out bytes.Buffer
+		publisher := NewNDJSONPublisher(&out)
+		publisher.Publish(model.Event{Id: "1", Type: model.TagCreated, Tag: model.Tag{Name: "Dinner"}})
+		publisher.Publish(model.Event{Id: "2", Type: model.TagUpdated, Tag: model.Tag{Name: "Supper"}})
+
+		events := make([]model.Event, 0)
+		scanner := bufio.NewScanner(&out)
+		for scanner.Scan() {
+			var event model.Event
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				t.Errorf("\t\tEach line should be a JSON event:  \"%s\". %v", scanner.Text(), BallotX)
+			}
+			events = append(events, event)
+		}
+		if len(events) == 2 && events[0].Tag.Name == "Dinner" && events[1].Type == model.TagUpdated {
+			t.Logf("\t\tThe events should be written one per line. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe events should be written one per line:  \"%v\". %v", events, BallotX)
+		}
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	t.Logf("Given an unknown publisher kind")
+	{
+		if _, err := NewPublisher("kafka", ""); err != nil {
+			t.Logf("\t\tThe publisher should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe publisher should be rejected. %v", BallotX)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/tag-service/model"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 of the request body keyed with the secret of the webhook, as
+	// sha256=<hex digest>.
+	SignatureHeader = "X-Tag-Signature"
+
+	// EventHeader carries the type of the event delivered.
+	EventHeader = "X-Tag-Event"
+
+	// DeliveryHeader carries the id of the delivery, which stays the same across the attempts.
+	DeliveryHeader = "X-Tag-Delivery"
+
+	// InitialBackoff is the delay before the second attempt of a delivery, it doubles with each failed attempt.
+	InitialBackoff = 10 * time.Second
+
+	// MaxBackoff caps the delay between two attempts of a delivery.
+	MaxBackoff = time.Hour
+)
+
+// ErrForbiddenAddress is returned when the host of a webhook resolves to an address which may not be reached.
+var ErrForbiddenAddress = errors.New("the webhook resolves to an internal address")
+
+// the networks of the service itself and of its internal network: unspecified, private, shared, loopback, link-local
+// (which holds the metadata endpoints of the cloud providers), unique local and multicast addresses
+var internalNetworks = parseNetworks("0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+	"172.16.0.0/12", "192.168.0.0/16", "224.0.0.0/4", "::/128", "::1/128", "fc00::/7", "fe80::/10", "ff00::/8")
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// PublicAddress tells whether the address is outside of the internal networks, and may be reached by a webhook.
+func PublicAddress(ip net.IP) bool {
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves the host of a webhook and returns ErrForbiddenAddress unless all its addresses are allowed.
+func CheckHost(ctx context.Context, host string, allowed func(net.IP) bool) error {
+	_, err := resolve(ctx, host, allowed)
+	return err
+}
+
+// Resolves the host into its addresses, which must all be allowed.
+func resolve(ctx context.Context, host string, allowed func(net.IP) bool) ([]net.IPAddr, error) {
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		if !allowed(address.IP) {
+			return nil, ErrForbiddenAddress
+		}
+	}
+	return addresses, nil
+}
+
+// NewClient returns the client posting the deliveries, which only connects to the allowed addresses. The host is
+// checked again when dialing and the address checked is the one dialed, so that neither a redirect nor a DNS record
+// changed since the webhook was registered leads to the internal network. A webhook taking longer than the timeout to
+// answer fails.
+func NewClient(timeout time.Duration, allowed func(net.IP) bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	transport := &http.Transport{
+		// no proxy, it would connect to the address instead of the client
+		Proxy: nil,
+		DialContext: func(ctx context.Context, network string, address string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			addresses, err := resolve(ctx, host, allowed)
+			if err != nil {
+				return nil, err
+			}
+			return dialer.DialContext(ctx, network, net.JoinHostPort(addresses[0].IP.String(), port))
+		},
+		TLSHandshakeTimeout: timeout,
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// Sign returns the value of the signature header for the given body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay before the next attempt of a delivery which failed the given number of times.
+func Backoff(attempts int) time.Duration {
+	backoff := InitialBackoff
+	for i := 1; i < attempts && backoff < MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxBackoff {
+		return MaxBackoff
+	}
+	return backoff
+}
+
+// Deliver posts the event to the URL of a webhook, signed with its secret, and returns the status code of the
+// response. Any status code outside of 2xx is an error.
+func Deliver(client *http.Client, url string, secret string, deliveryId string, event model.Event) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(secret, body))
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, deliveryId)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
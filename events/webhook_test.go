@@ -0,0 +1,135 @@
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"github.com/tag-service/model"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeliver(t *testing.T) {
+	t.Logf("Given a webhook endpoint checking the signature")
+	{
+		var received model.Event
+		var signed bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			signed = hmac.Equal([]byte(r.Header.Get(SignatureHeader)), []byte(Sign("secret", body))) &&
+				r.Header.Get(EventHeader) == model.TagCreated && r.Header.Get(DeliveryHeader) == "d1"
+			json.Unmarshal(body, &received)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		t.Logf("\tWhen delivering an event")
+		{
+			status, err := Deliver(server.Client(), server.URL, "secret", "d1", model.Event{Id: "1", Type: model.TagCreated, Tag: model.Tag{Name: "Dinner"}})
+			if err == nil && status == http.StatusNoContent && signed && received.Tag.Name == "Dinner" {
+				t.Logf("\t\tThe event should be delivered signed with the secret. %v", CheckMark)
+			} else {
+				t.Errorf("\t\tThe event should be delivered signed with the secret:  \"%v\" \"%v\". %v", status, err, BallotX)
+			}
+		}
+	}
+
+	t.Logf("Given a failing webhook endpoint")
+	{
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		t.Logf("\tWhen delivering an event")
+		{
+			status, err := Deliver(server.Client(), server.URL, "secret", "d1", model.Event{Id: "1", Type: model.TagCreated})
+			if err != nil && status == http.StatusBadGateway {
+				t.Logf("\t\tThe delivery should fail with the status code. %v", CheckMark)
+			} else {
+				t.Errorf("\t\tThe delivery should fail with the status code:  \"%v\" \"%v\". %v", status, err, BallotX)
+			}
+		}
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	t.Logf("Given the addresses a webhook may resolve to")
+	{
+		for address, public := range map[string]bool{"93.184.216.34": true, "2606:2800:220:1::": true, "127.0.0.1": false,
+			"10.1.2.3": false, "172.16.0.1": false, "192.168.1.1": false, "169.254.169.254": false, "0.0.0.0": false,
+			"::1": false, "fd00::1": false, "fe80::1": false, "::ffff:127.0.0.1": false} {
+			if PublicAddress(net.ParseIP(address)) == public {
+				t.Logf("\t\tThe address %v should be public: %v. %v", address, public, CheckMark)
+			} else {
+				t.Errorf("\t\tThe address %v should be public: %v. %v", address, public, BallotX)
+			}
+		}
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Logf("Given a webhook endpoint listening on the loopback address")
+	{
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		t.Logf("\tWhen delivering an event with a client reaching the public addresses only")
+		{
+			_, err := Deliver(NewClient(time.Second, PublicAddress), server.URL, "secret", "d1", model.Event{Id: "1", Type: model.TagCreated})
+			if errors.Is(err, ErrForbiddenAddress) && errors.Is(CheckHost(context.Background(), "127.0.0.1", PublicAddress), ErrForbiddenAddress) {
+				t.Logf("\t\tThe address should be refused. %v", CheckMark)
+			} else {
+				t.Errorf("\t\tThe address should be refused:  \"%v\". %v", err, BallotX)
+			}
+		}
+
+		t.Logf("\tWhen delivering an event with a client allowed to reach the loopback address")
+		{
+			loopback := func(ip net.IP) bool { return ip.IsLoopback() }
+			status, err := Deliver(NewClient(time.Second, loopback), server.URL, "secret", "d1", model.Event{Id: "1", Type: model.TagCreated})
+			if err == nil && status == http.StatusNoContent {
+				t.Logf("\t\tThe event should be delivered. %v", CheckMark)
+			} else {
+				t.Errorf("\t\tThe event should be delivered:  \"%v\" \"%v\". %v", status, err, BallotX)
+			}
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Logf("Given a delivery failing repeatedly")
+	{
+		if Backoff(1) == InitialBackoff && Backoff(2) == 2*InitialBackoff && Backoff(4) == 8*InitialBackoff {
+			t.Logf("\t\tThe delay should double with each attempt. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe delay should double with each attempt:  \"%v\" \"%v\". %v", Backoff(1), Backoff(4), BallotX)
+		}
+		if Backoff(100) == MaxBackoff {
+			t.Logf("\t\tThe delay should be capped. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe delay should be capped:  \"%v\". %v", Backoff(100), BallotX)
+		}
+	}
+}
+
+func TestPublishers(t *testing.T) {
+	t.Logf("Given two publishers")
+	{
+		first, second := NewMemoryPublisher(), NewMemoryPublisher()
+		Publishers{first, second}.Publish(model.Event{Id: "1", Type: model.TagCreated, OccurredAt: time.Now()})
+
+		if len(first.Events()) == 1 && len(second.Events()) == 1 {
+			t.Logf("\t\tThe event should be published to both. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe event should be published to both. %v", BallotX)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+// Package expression parses boolean tag expressions such as `(urgent AND finance) AND NOT archived` and translates
+// them into Mongo queries, so that callers never have to hand raw bson to the repository.
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"github.com/globalsign/mgo/bson"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxLength of an expression in characters.
+	MaxLength = 1024
+
+	// MaxTerms allowed in a single expression.
+	MaxTerms = 32
+
+	// MaxDepth of nested parentheses and NOT operators.
+	MaxDepth = 16
+)
+
+var (
+	// ErrEmpty returned when the expression contains no term.
+	ErrEmpty = errors.New("expression may not be empty")
+
+	// ErrTooLong returned when the expression exceeds MaxLength.
+	ErrTooLong = fmt.Errorf("expression may not be longer than %d characters", MaxLength)
+
+	// ErrTooManyTerms returned when the expression exceeds MaxTerms.
+	ErrTooManyTerms = fmt.Errorf("expression may not contain more than %d terms", MaxTerms)
+
+	// ErrTooDeep returned when the expression nests deeper than MaxDepth.
+	ErrTooDeep = fmt.Errorf("expression may not be nested deeper than %d levels", MaxDepth)
+)
+
+// Node of the parsed expression tree.
+type Node interface {
+	// Terms returns the tag names or ids referenced by the node.
+	Terms() []string
+
+	// Bson translates the node into a query over the given array field. The ids map resolves each term to the
+	// matching tag ids, a term missing from the map matches nothing.
+	Bson(field string, ids map[string][]bson.ObjectId) bson.M
+}
+
+// Term matches the documents tagged with the given tag name or id.
+type Term struct {
+	Value string
+}
+
+// And matches the documents matching all the operands.
+type And struct {
+	Operands []Node
+}
+
+// Or matches the documents matching any of the operands.
+type Or struct {
+	Operands []Node
+}
+
+// Not matches the documents not matching the operand.
+type Not struct {
+	Operand Node
+}
+
+func (t Term) Terms() []string {
+	return []string{t.Value}
+}
+
+func (t Term) Bson(field string, ids map[string][]bson.ObjectId) bson.M {
+	matches := ids[t.Value]
+	if matches == nil {
+		matches = []bson.ObjectId{}
+	}
+	return bson.M{field: bson.M{"$in": matches}}
+}
+
+func (a And) Terms() []string {
+	return terms(a.Operands)
+}
+
+func (a And) Bson(field string, ids map[string][]bson.ObjectId) bson.M {
+	return bson.M{"$and": operands(a.Operands, field, ids)}
+}
+
+func (o Or) Terms() []string {
+	return terms(o.Operands)
+}
+
+func (o Or) Bson(field string, ids map[string][]bson.ObjectId) bson.M {
+	return bson.M{"$or": operands(o.Operands, field, ids)}
+}
+
+func (n Not) Terms() []string {
+	return n.Operand.Terms()
+}
+
+func (n Not) Bson(field string, ids map[string][]bson.ObjectId) bson.M {
+	return bson.M{"$nor": []bson.M{n.Operand.Bson(field, ids)}}
+}
+
+// Parse parses the given expression. Terms are combined with the case insensitive AND, OR and NOT operators, AND
+// binding tighter than OR. Parentheses group terms and double quotes allow terms containing spaces or operators.
+func Parse(input string) (Node, error) {
+	if len(input) > MaxLength {
+		return nil, ErrTooLong
+	}
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrEmpty
+	}
+	p := &parser{tokens: tokens}
+	node, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected \"%s\" at position %d", p.tokens[p.pos].value, p.tokens[p.pos].offset)
+	}
+	if len(node.Terms()) > MaxTerms {
+		return nil, ErrTooManyTerms
+	}
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	termToken tokenKind = iota
+	andToken
+	orToken
+	notToken
+	openToken
+	closeToken
+)
+
+type token struct {
+	kind   tokenKind
+	value  string
+	offset int
+}
+
+// Splits the input into operators, parentheses and terms.
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{openToken, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{closeToken, ")", i})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated quote at position %d", i)
+			}
+			value := strings.TrimSpace(string(runes[i+1 : end]))
+			if value == "" {
+				return nil, fmt.Errorf("empty term at position %d", i)
+			}
+			tokens = append(tokens, token{termToken, value, i})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' && runes[end] != '"' {
+				end++
+			}
+			value := string(runes[i:end])
+			tokens = append(tokens, token{keyword(value), value, i})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// Returns the operator kind of the given word, or termToken when it is not an operator.
+func keyword(word string) tokenKind {
+	switch strings.ToUpper(word) {
+	case "AND":
+		return andToken
+	case "OR":
+		return orToken
+	case "NOT":
+		return notToken
+	}
+	return termToken
+}
+
+// Recursive descent parser over the tokens.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek(kind tokenKind) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == kind
+}
+
+func (p *parser) parseOr(depth int) (Node, error) {
+	node, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{node}
+	for p.peek(orToken) {
+		p.pos++
+		node, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return Or{nodes}, nil
+}
+
+func (p *parser) parseAnd(depth int) (Node, error) {
+	node, err := p.parseNot(depth)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{node}
+	for p.peek(andToken) {
+		p.pos++
+		node, err := p.parseNot(depth)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return And{nodes}, nil
+}
+
+func (p *parser) parseNot(depth int) (Node, error) {
+	if depth > MaxDepth {
+		return nil, ErrTooDeep
+	}
+	if p.peek(notToken) {
+		p.pos++
+		node, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{node}, nil
+	}
+	return p.parsePrimary(depth)
+}
+
+func (p *parser) parsePrimary(depth int) (Node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	switch tok.kind {
+	case termToken:
+		p.pos++
+		return Term{tok.value}, nil
+	case openToken:
+		p.pos++
+		node, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(closeToken) {
+			return nil, fmt.Errorf("missing closing parenthesis for position %d", tok.offset)
+		}
+		p.pos++
+		return node, nil
+	}
+	return nil, fmt.Errorf("unexpected \"%s\" at position %d", tok.value, tok.offset)
+}
+
+// Collects the terms of all the operands.
+func terms(nodes []Node) []string {
+	result := make([]string, 0)
+	for _, node := range nodes {
+		result = append(result, node.Terms()...)
+	}
+	return result
+}
+
+// Translates all the operands.
+func operands(nodes []Node, field string, ids map[string][]bson.ObjectId) []bson.M {
+	result := make([]bson.M, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, node.Bson(field, ids))
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package expression
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	CheckMark = "✓"
+	BallotX   = "✗"
+)
+
+func TestParsePrecedence(t *testing.T) {
+	t.Logf("Given an expression mixing AND, OR and NOT")
+	{
+		node, err := Parse("urgent AND finance OR NOT archived")
+		expected := Or{[]Node{And{[]Node{Term{"urgent"}, Term{"finance"}}}, Not{Term{"archived"}}}}
+		if err == nil && reflect.DeepEqual(node, expected) {
+			t.Logf("\t\tAND should bind tighter than OR:  \"%v\". %v", node, CheckMark)
+		} else {
+			t.Errorf("\t\tAND should bind tighter than OR:  \"%v\" %v. %v", node, err, BallotX)
+		}
+	}
+}
+
+func TestParseParenthesesAndQuotes(t *testing.T) {
+	t.Logf("Given an expression with parentheses and quoted terms")
+	{
+		node, err := Parse(`(urgent and "Business Travel") and not "OR"`)
+		expected := And{[]Node{And{[]Node{Term{"urgent"}, Term{"Business Travel"}}}, Not{Term{"OR"}}}}
+		if err == nil && reflect.DeepEqual(node, expected) {
+			t.Logf("\t\tThe expression should have been parsed:  \"%v\". %v", node, CheckMark)
+		} else {
+			t.Errorf("\t\tThe expression should have been parsed:  \"%v\" %v. %v", node, err, BallotX)
+		}
+		if reflect.DeepEqual(node.Terms(), []string{"urgent", "Business Travel", "OR"}) {
+			t.Logf("\t\tThe terms should have been collected:  \"%v\". %v", node.Terms(), CheckMark)
+		} else {
+			t.Errorf("\t\tThe terms should have been collected:  \"%v\". %v", node.Terms(), BallotX)
+		}
+	}
+}
+
+func TestParseInvalidExpressions(t *testing.T) {
+	invalid := []string{
+		"",
+		"   ",
+		"urgent AND",
+		"AND urgent",
+		"(urgent OR finance",
+		"urgent OR finance)",
+		"urgent finance",
+		`"urgent`,
+		`""`,
+		strings.Repeat("a", MaxLength+1),
+		strings.Repeat("a OR ", MaxTerms) + "a",
+		strings.Repeat("(", MaxDepth+1) + "a" + strings.Repeat(")", MaxDepth+1),
+		strings.Repeat("NOT ", MaxDepth+1) + "a",
+	}
+	t.Logf("Given invalid expressions")
+	{
+		for _, input := range invalid {
+			if _, err := Parse(input); err != nil {
+				t.Logf("\t\tThe expression \"%.40s\" should be rejected: %v. %v", input, err, CheckMark)
+			} else {
+				t.Errorf("\t\tThe expression \"%.40s\" should be rejected. %v", input, BallotX)
+			}
+		}
+	}
+}
+
+func TestBson(t *testing.T) {
+	t.Logf("Given a parsed expression")
+	{
+		urgent := bson.NewObjectId()
+		archived := bson.NewObjectId()
+		node, _ := Parse("urgent AND NOT archived AND unknown")
+		query := node.Bson("tags", map[string][]bson.ObjectId{"urgent": {urgent}, "archived": {archived}})
+		expected := bson.M{"$and": []bson.M{
+			{"tags": bson.M{"$in": []bson.ObjectId{urgent}}},
+			{"$nor": []bson.M{{"tags": bson.M{"$in": []bson.ObjectId{archived}}}}},
+			{"tags": bson.M{"$in": []bson.ObjectId{}}},
+		}}
+		if reflect.DeepEqual(query, expected) {
+			t.Logf("\t\tThe expression should translate to a Mongo query:  \"%v\". %v", query, CheckMark)
+		} else {
+			t.Errorf("\t\tThe expression should translate to a Mongo query:  \"%v\". %v", query, BallotX)
+		}
+	}
+}
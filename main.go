@@ -1,11 +1,14 @@
 package main
 
 import (
+	"flag"
 	"github.com/tag-service/api"
 	_ "github.com/tag-service/docs"
+	"github.com/tag-service/events"
 	"github.com/tag-service/logger"
 	"github.com/tag-service/repository"
 	"github.com/tag-service/vault"
+	"os"
 )
 
 // @BasePath /
@@ -17,8 +20,49 @@ import (
 
 // @BasePath /
 func main() {
+	reportDuplicates := flag.Bool("report-duplicates", false, "list the tags sharing the same name within an organisation and exit")
+	backfillNames := flag.Bool("backfill-names", false, "store the normalised name of the tags which are not duplicated and exit")
+	flag.Parse()
+
+	handler := api.NewTagHandler(repository.NewRepository(vault.LoadConfig()))
+	if *reportDuplicates {
+		count, err := handler.ReportDuplicates(os.Stdout)
+		if err != nil {
+			logger.Error.Printf("Failed to report the duplicate tags")
+			panic(err)
+		}
+		logger.Info.Printf("Found %d duplicate tag names", count)
+		return
+	}
+	if *backfillNames {
+		count, err := handler.BackfillNormalisedNames()
+		if err != nil {
+			logger.Error.Printf("Failed to backfill the normalised tag names")
+			panic(err)
+		}
+		logger.Info.Printf("Stored the normalised name of %d tags", count)
+		return
+	}
 
 	logger.Info.Println("Starting up the server..")
-	api.NewTagHandler(repository.NewRepository(vault.LoadConfig())).CreateRouter().Run(":8080")
+	if err := handler.EnsureIndexes(); err != nil {
+		logger.Error.Printf("Failed to create the database indexes")
+		panic(err)
+	}
+	retention, err := api.TrashRetention()
+	if err != nil {
+		logger.Error.Printf("Failed to read the trash retention")
+		panic(err)
+	}
+	go handler.PurgeTrashEvery(api.PurgeInterval, retention)
+
+	publisher, err := api.NewEventPublisher()
+	if err != nil {
+		logger.Error.Printf("Failed to create the event publisher")
+		panic(err)
+	}
+	go handler.RelayEventsEvery(events.Publishers{publisher, handler.WebhookPublisher()}, api.RelayInterval)
+	go handler.DeliverWebhooksEvery(api.DeliveryInterval)
+	handler.CreateRouter().Run(":8080")
 	logger.Info.Println("Shutting down the server..")
 }
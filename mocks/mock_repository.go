@@ -5,10 +5,12 @@
 package mocks
 
 import (
+	mgo "github.com/globalsign/mgo"
 	bson "github.com/globalsign/mgo/bson"
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/tag-service/model"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRepository is a mock of Repository interface
@@ -59,6 +61,32 @@ func (mr *MockRepositoryMockRecorder) FindAll(database, collection, query interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll), database, collection, query)
 }
 
+// FindPage mocks base method
+func (m *MockRepository) FindPage(database, collection string, query bson.M, sort []string, limit int) ([]model.TagDAO, error) {
+	ret := m.ctrl.Call(m, "FindPage", database, collection, query, sort, limit)
+	ret0, _ := ret[0].([]model.TagDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPage indicates an expected call of FindPage
+func (mr *MockRepositoryMockRecorder) FindPage(database, collection, query, sort, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockRepository)(nil).FindPage), database, collection, query, sort, limit)
+}
+
+// Count mocks base method
+func (m *MockRepository) Count(database, collection string, query bson.M) (int, error) {
+	ret := m.ctrl.Call(m, "Count", database, collection, query)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count
+func (mr *MockRepositoryMockRecorder) Count(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRepository)(nil).Count), database, collection, query)
+}
+
 // Find mocks base method
 func (m *MockRepository) Find(database, collection string, oid bson.ObjectId) (model.TagDAO, error) {
 	ret := m.ctrl.Call(m, "Find", database, collection, oid)
@@ -72,6 +100,19 @@ func (mr *MockRepositoryMockRecorder) Find(database, collection, oid interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRepository)(nil).Find), database, collection, oid)
 }
 
+// FindOne mocks base method
+func (m *MockRepository) FindOne(database, collection string, query bson.M) (model.TagDAO, error) {
+	ret := m.ctrl.Call(m, "FindOne", database, collection, query)
+	ret0, _ := ret[0].(model.TagDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOne indicates an expected call of FindOne
+func (mr *MockRepositoryMockRecorder) FindOne(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockRepository)(nil).FindOne), database, collection, query)
+}
+
 // Delete mocks base method
 func (m *MockRepository) Delete(database, collection string, oid bson.ObjectId) error {
 	ret := m.ctrl.Call(m, "Delete", database, collection, oid)
@@ -83,3 +124,397 @@ func (m *MockRepository) Delete(database, collection string, oid bson.ObjectId)
 func (mr *MockRepositoryMockRecorder) Delete(database, collection, oid interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), database, collection, oid)
 }
+
+// Update mocks base method
+func (m *MockRepository) Update(database, collection string, oid bson.ObjectId, update interface{}) error {
+	ret := m.ctrl.Call(m, "Update", database, collection, oid, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockRepositoryMockRecorder) Update(database, collection, oid, update interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), database, collection, oid, update)
+}
+
+// UpdateAll mocks base method
+func (m *MockRepository) UpdateAll(database, collection string, query bson.M, update interface{}) error {
+	ret := m.ctrl.Call(m, "UpdateAll", database, collection, query, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAll indicates an expected call of UpdateAll
+func (mr *MockRepositoryMockRecorder) UpdateAll(database, collection, query, update interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAll", reflect.TypeOf((*MockRepository)(nil).UpdateAll), database, collection, query, update)
+}
+
+// UpdateOne mocks base method
+func (m *MockRepository) UpdateOne(database, collection string, query bson.M, update interface{}) error {
+	ret := m.ctrl.Call(m, "UpdateOne", database, collection, query, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOne indicates an expected call of UpdateOne
+func (mr *MockRepositoryMockRecorder) UpdateOne(database, collection, query, update interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOne", reflect.TypeOf((*MockRepository)(nil).UpdateOne), database, collection, query, update)
+}
+
+// Modify mocks base method
+func (m *MockRepository) Modify(database, collection string, query bson.M, update interface{}) (model.TagDAO, error) {
+	ret := m.ctrl.Call(m, "Modify", database, collection, query, update)
+	ret0, _ := ret[0].(model.TagDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Modify indicates an expected call of Modify
+func (mr *MockRepositoryMockRecorder) Modify(database, collection, query, update interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Modify", reflect.TypeOf((*MockRepository)(nil).Modify), database, collection, query, update)
+}
+
+// RemoveOne mocks base method
+func (m *MockRepository) RemoveOne(database, collection string, query bson.M) error {
+	ret := m.ctrl.Call(m, "RemoveOne", database, collection, query)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveOne indicates an expected call of RemoveOne
+func (mr *MockRepositoryMockRecorder) RemoveOne(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveOne", reflect.TypeOf((*MockRepository)(nil).RemoveOne), database, collection, query)
+}
+
+// Upsert mocks base method
+func (m *MockRepository) Upsert(database, collection string, query bson.M, update interface{}) error {
+	ret := m.ctrl.Call(m, "Upsert", database, collection, query, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert
+func (mr *MockRepositoryMockRecorder) Upsert(database, collection, query, update interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockRepository)(nil).Upsert), database, collection, query, update)
+}
+
+// RemoveAll mocks base method
+func (m *MockRepository) RemoveAll(database, collection string, query bson.M) error {
+	ret := m.ctrl.Call(m, "RemoveAll", database, collection, query)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAll indicates an expected call of RemoveAll
+func (mr *MockRepositoryMockRecorder) RemoveAll(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAll", reflect.TypeOf((*MockRepository)(nil).RemoveAll), database, collection, query)
+}
+
+// EnsureIndex mocks base method
+func (m *MockRepository) EnsureIndex(database, collection string, index mgo.Index) error {
+	ret := m.ctrl.Call(m, "EnsureIndex", database, collection, index)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureIndex indicates an expected call of EnsureIndex
+func (mr *MockRepositoryMockRecorder) EnsureIndex(database, collection, index interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndex", reflect.TypeOf((*MockRepository)(nil).EnsureIndex), database, collection, index)
+}
+
+// FindAssignments mocks base method
+func (m *MockRepository) FindAssignments(database, collection string, query bson.M) ([]model.AssignmentDAO, error) {
+	ret := m.ctrl.Call(m, "FindAssignments", database, collection, query)
+	ret0, _ := ret[0].([]model.AssignmentDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAssignments indicates an expected call of FindAssignments
+func (mr *MockRepositoryMockRecorder) FindAssignments(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAssignments", reflect.TypeOf((*MockRepository)(nil).FindAssignments), database, collection, query)
+}
+
+// FindResources mocks base method
+func (m *MockRepository) FindResources(database, collection string, query, tagsQuery bson.M, limit int) ([]model.ResourceRef, error) {
+	ret := m.ctrl.Call(m, "FindResources", database, collection, query, tagsQuery, limit)
+	ret0, _ := ret[0].([]model.ResourceRef)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindResources indicates an expected call of FindResources
+func (mr *MockRepositoryMockRecorder) FindResources(database, collection, query, tagsQuery, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResources", reflect.TypeOf((*MockRepository)(nil).FindResources), database, collection, query, tagsQuery, limit)
+}
+
+// FindPalette mocks base method
+func (m *MockRepository) FindPalette(database, collection, organisationId string) (model.PaletteDAO, error) {
+	ret := m.ctrl.Call(m, "FindPalette", database, collection, organisationId)
+	ret0, _ := ret[0].(model.PaletteDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPalette indicates an expected call of FindPalette
+func (mr *MockRepositoryMockRecorder) FindPalette(database, collection, organisationId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPalette", reflect.TypeOf((*MockRepository)(nil).FindPalette), database, collection, organisationId)
+}
+
+// BulkInsert mocks base method
+func (m *MockRepository) BulkInsert(database, collection string, docs []interface{}) (map[int]error, error) {
+	ret := m.ctrl.Call(m, "BulkInsert", database, collection, docs)
+	ret0, _ := ret[0].(map[int]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkInsert indicates an expected call of BulkInsert
+func (mr *MockRepositoryMockRecorder) BulkInsert(database, collection, docs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkInsert", reflect.TypeOf((*MockRepository)(nil).BulkInsert), database, collection, docs)
+}
+
+// BulkUpdate mocks base method
+func (m *MockRepository) BulkUpdate(database, collection string, query bson.M, ids []bson.ObjectId, updates []interface{}) (map[int]error, error) {
+	ret := m.ctrl.Call(m, "BulkUpdate", database, collection, query, ids, updates)
+	ret0, _ := ret[0].(map[int]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdate indicates an expected call of BulkUpdate
+func (mr *MockRepositoryMockRecorder) BulkUpdate(database, collection, query, ids, updates interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdate", reflect.TypeOf((*MockRepository)(nil).BulkUpdate), database, collection, query, ids, updates)
+}
+
+// BulkRemove mocks base method
+func (m *MockRepository) BulkRemove(database, collection string, query bson.M, ids []bson.ObjectId) (map[int]error, error) {
+	ret := m.ctrl.Call(m, "BulkRemove", database, collection, query, ids)
+	ret0, _ := ret[0].(map[int]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkRemove indicates an expected call of BulkRemove
+func (mr *MockRepositoryMockRecorder) BulkRemove(database, collection, query, ids interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkRemove", reflect.TypeOf((*MockRepository)(nil).BulkRemove), database, collection, query, ids)
+}
+
+// FindHistory mocks base method
+func (m *MockRepository) FindHistory(database, collection string, query bson.M, limit int) ([]model.HistoryDAO, error) {
+	ret := m.ctrl.Call(m, "FindHistory", database, collection, query, limit)
+	ret0, _ := ret[0].([]model.HistoryDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHistory indicates an expected call of FindHistory
+func (mr *MockRepositoryMockRecorder) FindHistory(database, collection, query, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHistory", reflect.TypeOf((*MockRepository)(nil).FindHistory), database, collection, query, limit)
+}
+
+// FindEvents mocks base method
+func (m *MockRepository) FindEvents(database, collection string, query bson.M, limit int) ([]model.EventDAO, error) {
+	ret := m.ctrl.Call(m, "FindEvents", database, collection, query, limit)
+	ret0, _ := ret[0].([]model.EventDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEvents indicates an expected call of FindEvents
+func (mr *MockRepositoryMockRecorder) FindEvents(database, collection, query, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEvents", reflect.TypeOf((*MockRepository)(nil).FindEvents), database, collection, query, limit)
+}
+
+// FindPendingTags mocks base method
+func (m *MockRepository) FindPendingTags(database, collection string, query bson.M, limit int) ([]model.PendingTagDAO, error) {
+	ret := m.ctrl.Call(m, "FindPendingTags", database, collection, query, limit)
+	ret0, _ := ret[0].([]model.PendingTagDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingTags indicates an expected call of FindPendingTags
+func (mr *MockRepositoryMockRecorder) FindPendingTags(database, collection, query, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingTags", reflect.TypeOf((*MockRepository)(nil).FindPendingTags), database, collection, query, limit)
+}
+
+// FindWebhooks mocks base method
+func (m *MockRepository) FindWebhooks(database, collection string, query bson.M) ([]model.WebhookDAO, error) {
+	ret := m.ctrl.Call(m, "FindWebhooks", database, collection, query)
+	ret0, _ := ret[0].([]model.WebhookDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWebhooks indicates an expected call of FindWebhooks
+func (mr *MockRepositoryMockRecorder) FindWebhooks(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWebhooks", reflect.TypeOf((*MockRepository)(nil).FindWebhooks), database, collection, query)
+}
+
+// FindDeliveries mocks base method
+func (m *MockRepository) FindDeliveries(database, collection string, query bson.M, sort string, limit int) ([]model.DeliveryDAO, error) {
+	ret := m.ctrl.Call(m, "FindDeliveries", database, collection, query, sort, limit)
+	ret0, _ := ret[0].([]model.DeliveryDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeliveries indicates an expected call of FindDeliveries
+func (mr *MockRepositoryMockRecorder) FindDeliveries(database, collection, query, sort, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeliveries", reflect.TypeOf((*MockRepository)(nil).FindDeliveries), database, collection, query, sort, limit)
+}
+
+// UsageByTag mocks base method
+func (m *MockRepository) UsageByTag(database, collection string, query bson.M) ([]model.TagUsage, error) {
+	ret := m.ctrl.Call(m, "UsageByTag", database, collection, query)
+	ret0, _ := ret[0].([]model.TagUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsageByTag indicates an expected call of UsageByTag
+func (mr *MockRepositoryMockRecorder) UsageByTag(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsageByTag", reflect.TypeOf((*MockRepository)(nil).UsageByTag), database, collection, query)
+}
+
+// UsageByResourceType mocks base method
+func (m *MockRepository) UsageByResourceType(database, collection string, query bson.M) ([]model.ResourceTypeUsage, error) {
+	ret := m.ctrl.Call(m, "UsageByResourceType", database, collection, query)
+	ret0, _ := ret[0].([]model.ResourceTypeUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsageByResourceType indicates an expected call of UsageByResourceType
+func (mr *MockRepositoryMockRecorder) UsageByResourceType(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsageByResourceType", reflect.TypeOf((*MockRepository)(nil).UsageByResourceType), database, collection, query)
+}
+
+// FindKeys mocks base method
+func (m *MockRepository) FindKeys(database, collection string, query bson.M) ([]model.KeyDAO, error) {
+	ret := m.ctrl.Call(m, "FindKeys", database, collection, query)
+	ret0, _ := ret[0].([]model.KeyDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindKeys indicates an expected call of FindKeys
+func (mr *MockRepositoryMockRecorder) FindKeys(database, collection, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindKeys", reflect.TypeOf((*MockRepository)(nil).FindKeys), database, collection, query)
+}
+
+// FindSchema mocks base method
+func (m *MockRepository) FindSchema(database, collection, organisationId string) (model.SchemaDAO, error) {
+	ret := m.ctrl.Call(m, "FindSchema", database, collection, organisationId)
+	ret0, _ := ret[0].(model.SchemaDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSchema indicates an expected call of FindSchema
+func (mr *MockRepositoryMockRecorder) FindSchema(database, collection, organisationId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSchema", reflect.TypeOf((*MockRepository)(nil).FindSchema), database, collection, organisationId)
+}
+
+// FindMembership mocks base method
+func (m *MockRepository) FindMembership(database, collection, organisationId, accountId string) (model.MembershipDAO, error) {
+	ret := m.ctrl.Call(m, "FindMembership", database, collection, organisationId, accountId)
+	ret0, _ := ret[0].(model.MembershipDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMembership indicates an expected call of FindMembership
+func (mr *MockRepositoryMockRecorder) FindMembership(database, collection, organisationId, accountId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMembership", reflect.TypeOf((*MockRepository)(nil).FindMembership), database, collection, organisationId, accountId)
+}
+
+// FindMemberships mocks base method
+func (m *MockRepository) FindMemberships(database, collection, organisationId string) ([]model.MembershipDAO, error) {
+	ret := m.ctrl.Call(m, "FindMemberships", database, collection, organisationId)
+	ret0, _ := ret[0].([]model.MembershipDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMemberships indicates an expected call of FindMemberships
+func (mr *MockRepositoryMockRecorder) FindMemberships(database, collection, organisationId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMemberships", reflect.TypeOf((*MockRepository)(nil).FindMemberships), database, collection, organisationId)
+}
+
+// AcquireLease mocks base method
+func (m *MockRepository) AcquireLease(database, collection, name, owner string, duration time.Duration) (bool, error) {
+	ret := m.ctrl.Call(m, "AcquireLease", database, collection, name, owner, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireLease indicates an expected call of AcquireLease
+func (mr *MockRepositoryMockRecorder) AcquireLease(database, collection, name, owner, duration interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLease", reflect.TypeOf((*MockRepository)(nil).AcquireLease), database, collection, name, owner, duration)
+}
+
+// ReleaseLease mocks base method
+func (m *MockRepository) ReleaseLease(database, collection, name, owner string) error {
+	ret := m.ctrl.Call(m, "ReleaseLease", database, collection, name, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLease indicates an expected call of ReleaseLease
+func (mr *MockRepositoryMockRecorder) ReleaseLease(database, collection, name, owner interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLease", reflect.TypeOf((*MockRepository)(nil).ReleaseLease), database, collection, name, owner)
+}
+
+// ReserveSequence mocks base method
+func (m *MockRepository) ReserveSequence(database, collection, organisationId string, reservation bson.ObjectId) (int64, error) {
+	ret := m.ctrl.Call(m, "ReserveSequence", database, collection, organisationId, reservation)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveSequence indicates an expected call of ReserveSequence
+func (mr *MockRepositoryMockRecorder) ReserveSequence(database, collection, organisationId, reservation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSequence", reflect.TypeOf((*MockRepository)(nil).ReserveSequence), database, collection, organisationId, reservation)
+}
+
+// CompleteSequence mocks base method
+func (m *MockRepository) CompleteSequence(database, collection, organisationId string, reservation bson.ObjectId, expiredAt time.Time) error {
+	ret := m.ctrl.Call(m, "CompleteSequence", database, collection, organisationId, reservation, expiredAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteSequence indicates an expected call of CompleteSequence
+func (mr *MockRepositoryMockRecorder) CompleteSequence(database, collection, organisationId, reservation, expiredAt interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSequence", reflect.TypeOf((*MockRepository)(nil).CompleteSequence), database, collection, organisationId, reservation, expiredAt)
+}
+
+// FindSequence mocks base method
+func (m *MockRepository) FindSequence(database, collection, organisationId string) (model.SequenceDAO, error) {
+	ret := m.ctrl.Call(m, "FindSequence", database, collection, organisationId)
+	ret0, _ := ret[0].(model.SequenceDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSequence indicates an expected call of FindSequence
+func (mr *MockRepositoryMockRecorder) FindSequence(database, collection, organisationId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSequence", reflect.TypeOf((*MockRepository)(nil).FindSequence), database, collection, organisationId)
+}
+
+// ClaimDelivery mocks base method
+func (m *MockRepository) ClaimDelivery(database, collection string, query bson.M, sort string, update interface{}) (model.DeliveryDAO, error) {
+	ret := m.ctrl.Call(m, "ClaimDelivery", database, collection, query, sort, update)
+	ret0, _ := ret[0].(model.DeliveryDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDelivery indicates an expected call of ClaimDelivery
+func (mr *MockRepositoryMockRecorder) ClaimDelivery(database, collection, query, sort, update interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDelivery", reflect.TypeOf((*MockRepository)(nil).ClaimDelivery), database, collection, query, sort, update)
+}
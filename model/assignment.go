@@ -0,0 +1,44 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"time"
+)
+
+// for persistence, records that a tag has been applied to a resource owned by another service
+type AssignmentDAO struct {
+	Id             bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	OrganisationId string        `json:"organisationId" bson:"organisationId"`
+	ResourceType   string        `json:"resourceType" bson:"resourceType"`
+	ResourceId     string        `json:"resourceId" bson:"resourceId"`
+	TagId          bson.ObjectId `json:"tagId" bson:"tagId"`
+	CreatedAt      time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+type AssignTagsRequest struct {
+	TagIds []string `json:"tagIds" binding:"required"`
+}
+
+// Reference to a resource owned by another service
+type ResourceRef struct {
+	ResourceType string `json:"resourceType" bson:"resourceType"`
+	ResourceId   string `json:"resourceId" bson:"resourceId"`
+}
+
+type FindResourcesResponse struct {
+	Resources  []ResourceRef `json:"resources"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// Returns the distinct tag ids of the given assignments.
+func AssignedTagIds(assignments []AssignmentDAO) []bson.ObjectId {
+	seen := make(map[bson.ObjectId]bool)
+	ids := make([]bson.ObjectId, 0)
+	for _, assignment := range assignments {
+		if !seen[assignment.TagId] {
+			seen[assignment.TagId] = true
+			ids = append(ids, assignment.TagId)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"testing"
+)
+
+func TestAssignedTagIdsRemovesDuplicates(t *testing.T) {
+	t.Logf("Given a resource with the same tag assigned twice")
+	{
+		tagId := bson.NewObjectId()
+		otherId := bson.NewObjectId()
+		assignments := []AssignmentDAO{{TagId: tagId}, {TagId: otherId}, {TagId: tagId}}
+		ids := AssignedTagIds(assignments)
+		if len(ids) == 2 && ids[0] == tagId && ids[1] == otherId {
+			t.Logf("\t\tThe tag ids should be distinct:  \"%v\". %v", ids, CheckMark)
+		} else {
+			t.Errorf("\t\tThe tag ids should be distinct:  \"%v\". %v", ids, BallotX)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Types of the attribute fields
+const (
+	FieldString  = "string"
+	FieldNumber  = "number"
+	FieldInteger = "integer"
+	FieldBoolean = "boolean"
+)
+
+const MaxSchemaFields = 64
+
+// Message of the error response listing the invalid attribute fields
+const InvalidAttributesMessage = "invalid attributes"
+
+// for persistence, the attribute fields the tags of an organisation may carry
+type SchemaDAO struct {
+	OrganisationId   string                    `json:"organisationId" bson:"organisationId"`
+	Fields           map[string]AttributeField `json:"fields" bson:"fields"`
+	AdditionalFields bool                      `json:"additionalFields" bson:"additionalFields"`
+}
+
+// The maximum length only applies to the string fields, in characters.
+type AttributeField struct {
+	Type      string `json:"type" bson:"type"`
+	Required  bool   `json:"required,omitempty" bson:"required,omitempty"`
+	MaxLength int    `json:"maxLength,omitempty" bson:"maxLength,omitempty"`
+}
+
+// Custom is false when the organisation has not defined its own schema, in which case any attribute is allowed. The
+// fields not defined by a custom schema are only allowed along with additionalFields.
+type Schema struct {
+	Fields           map[string]AttributeField `json:"fields"`
+	AdditionalFields bool                      `json:"additionalFields"`
+	Custom           bool                      `json:"custom"`
+}
+
+type UpdateSchemaRequest struct {
+	Fields           map[string]AttributeField `json:"fields" binding:"required"`
+	AdditionalFields bool                      `json:"additionalFields"`
+}
+
+// Error of one attribute field, given by name.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// DefaultSchema is used by the organisations which have not defined their own schema.
+var DefaultSchema = Schema{Fields: map[string]AttributeField{}, AdditionalFields: true}
+
+// NewSchema validates the definition of the attribute fields.
+func NewSchema(fields map[string]AttributeField, additionalFields bool) (Schema, error) {
+	if len(fields) > MaxSchemaFields {
+		return Schema{}, fmt.Errorf("a schema may not define more than %d fields", MaxSchemaFields)
+	}
+	names := make([]string, 0)
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := fields[name]
+		if strings.TrimSpace(name) == "" {
+			return Schema{}, errors.New("a field name may not be empty")
+		}
+		switch field.Type {
+		case FieldString, FieldNumber, FieldInteger, FieldBoolean:
+		default:
+			return Schema{}, fmt.Errorf("the type of field %v must be %v, %v, %v or %v", name, FieldString, FieldNumber, FieldInteger, FieldBoolean)
+		}
+		if field.MaxLength < 0 || (field.MaxLength > 0 && field.Type != FieldString) {
+			return Schema{}, fmt.Errorf("invalid maximum length of field %v: only a string field has a positive maximum length", name)
+		}
+	}
+	return Schema{Fields: fields, AdditionalFields: additionalFields, Custom: true}, nil
+}
+
+// Validate checks the attributes of a tag against the schema, and returns the errors sorted by field.
+func (schema Schema) Validate(attributes map[string]interface{}) []FieldError {
+	errs := make([]FieldError, 0)
+	for name, field := range schema.Fields {
+		if value, ok := attributes[name]; field.Required && (!ok || value == nil) {
+			errs = append(errs, FieldError{Field: name, Message: "is required"})
+		}
+	}
+	for name, value := range attributes {
+		field, ok := schema.Fields[name]
+		if !ok {
+			if !schema.AdditionalFields {
+				errs = append(errs, FieldError{Field: name, Message: "is not defined by the schema"})
+			}
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if message := field.check(value); message != "" {
+			errs = append(errs, FieldError{Field: name, Message: message})
+		}
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+	return errs
+}
+
+// Returns why the value does not match the field, or an empty string.
+func (field AttributeField) check(value interface{}) string {
+	switch field.Type {
+	case FieldString:
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if field.MaxLength > 0 && utf8.RuneCountInString(s) > field.MaxLength {
+			return fmt.Sprintf("may not be longer than %d characters", field.MaxLength)
+		}
+	case FieldNumber:
+		if _, ok := number(value); !ok {
+			return "must be a number"
+		}
+	case FieldInteger:
+		if n, ok := number(value); !ok || n != math.Trunc(n) {
+			return "must be an integer"
+		}
+	case FieldBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	}
+	return ""
+}
+
+// MergeAttributes applies the attributes of a patch: the fields given replace the current ones, the fields given as
+// null are removed.
+func MergeAttributes(current map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for name, value := range current {
+		merged[name] = value
+	}
+	for name, value := range patch {
+		if value == nil {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = value
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// Numbers are decoded as float64 from JSON, and as integers as well from the database.
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
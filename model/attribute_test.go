@@ -0,0 +1,95 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSchema(t *testing.T) {
+	t.Logf("Given schema definitions")
+	{
+		valid := map[string]AttributeField{"owner": {Type: FieldString, Required: true, MaxLength: 40}, "priority": {Type: FieldInteger}}
+		if schema, err := NewSchema(valid, false); err == nil && schema.Custom {
+			t.Logf("\t\tThe fields should be accepted. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe fields should be accepted: %v. %v", err, BallotX)
+		}
+
+		invalid := []map[string]AttributeField{
+			{"owner": {Type: "object"}},
+			{"priority": {Type: FieldNumber, MaxLength: 3}},
+			{"owner": {Type: FieldString, MaxLength: -1}},
+			{" ": {Type: FieldString}},
+		}
+		for _, fields := range invalid {
+			if _, err := NewSchema(fields, false); err != nil {
+				t.Logf("\t\t%v should be rejected. %v", fields, CheckMark)
+			} else {
+				t.Errorf("\t\t%v should be rejected. %v", fields, BallotX)
+			}
+		}
+	}
+}
+
+func TestValidateAttributes(t *testing.T) {
+	t.Logf("Given a schema with a required owner, an integer priority and a boolean archived field")
+	{
+		schema, _ := NewSchema(map[string]AttributeField{
+			"owner":    {Type: FieldString, Required: true, MaxLength: 5},
+			"priority": {Type: FieldInteger},
+			"archived": {Type: FieldBoolean},
+		}, false)
+
+		if errs := schema.Validate(map[string]interface{}{"owner": "team", "priority": float64(2), "archived": true}); len(errs) == 0 {
+			t.Logf("\t\tValid attributes should be accepted. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tValid attributes should be accepted: %v. %v", errs, BallotX)
+		}
+
+		errs := schema.Validate(map[string]interface{}{"priority": 1.5, "archived": "no", "icon": "star"})
+		expected := []FieldError{
+			{Field: "archived", Message: "must be a boolean"},
+			{Field: "icon", Message: "is not defined by the schema"},
+			{Field: "owner", Message: "is required"},
+			{Field: "priority", Message: "must be an integer"},
+		}
+		if reflect.DeepEqual(errs, expected) {
+			t.Logf("\t\tEach invalid field should be reported. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tEach invalid field should be reported: %v. %v", errs, BallotX)
+		}
+
+		if errs := schema.Validate(map[string]interface{}{"owner": "payments"}); len(errs) == 1 && errs[0].Field == "owner" {
+			t.Logf("\t\tA string longer than the maximum length should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tA string longer than the maximum length should be rejected: %v. %v", errs, BallotX)
+		}
+	}
+
+	t.Logf("Given the default schema")
+	{
+		if errs := DefaultSchema.Validate(map[string]interface{}{"icon": "star", "nested": map[string]interface{}{"a": 1}}); len(errs) == 0 {
+			t.Logf("\t\tAny attribute should be accepted. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tAny attribute should be accepted: %v. %v", errs, BallotX)
+		}
+	}
+}
+
+func TestMergeAttributes(t *testing.T) {
+	t.Logf("Given the attributes of a tag")
+	{
+		current := map[string]interface{}{"owner": "payments", "icon": "star"}
+		merged := MergeAttributes(current, map[string]interface{}{"icon": nil, "priority": float64(1)})
+		if reflect.DeepEqual(merged, map[string]interface{}{"owner": "payments", "priority": float64(1)}) && current["icon"] == "star" {
+			t.Logf("\t\tThe patch should replace and remove fields. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe patch should replace and remove fields: %v. %v", merged, BallotX)
+		}
+		if merged := MergeAttributes(current, map[string]interface{}{"owner": nil, "icon": nil}); merged == nil {
+			t.Logf("\t\tRemoving all the fields should leave no attributes. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tRemoving all the fields should leave no attributes: %v. %v", merged, BallotX)
+		}
+	}
+}
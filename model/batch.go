@@ -0,0 +1,38 @@
+package model
+
+type BatchCreateRequest struct {
+	Tags []CreateTagRequest `json:"tags" binding:"required"`
+}
+
+// Only the fields present are updated, as with PatchTagRequest. Given the version of the ETag the tag was read with,
+// the tag is only updated while it still has that version, as with If-Match.
+type BatchUpdateItem struct {
+	Id      string `json:"id"`
+	Version *int   `json:"version,omitempty"`
+	PatchTagRequest
+}
+
+type BatchUpdateRequest struct {
+	Tags []BatchUpdateItem `json:"tags" binding:"required"`
+}
+
+// The versions of the ETags the tags were read with may be given keyed by tag id, the tags being only deleted while
+// they still have them, as with If-Match.
+type BatchDeleteRequest struct {
+	Ids      []string       `json:"ids" binding:"required"`
+	Versions map[string]int `json:"versions,omitempty"`
+}
+
+// Outcome of one item of a batch, the status is the one the single item endpoint would have returned.
+type BatchResult struct {
+	Index      int          `json:"index"`
+	Status     int          `json:"status"`
+	Id         string       `json:"id,omitempty"`
+	Message    string       `json:"message,omitempty"`
+	ExistingId string       `json:"existingId,omitempty"`
+	Errors     []FieldError `json:"errors,omitempty"`
+}
+
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
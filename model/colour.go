@@ -0,0 +1,142 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+const MaxPaletteColours = 64
+
+// for persistence, the colours an organisation allows its tags to use
+type PaletteDAO struct {
+	OrganisationId string          `json:"organisationId" bson:"organisationId"`
+	Colours        []PaletteColour `json:"colours" bson:"colours"`
+}
+
+type PaletteColour struct {
+	Name string `json:"name" bson:"name"`
+	Hex  string `json:"hex" bson:"hex"`
+}
+
+// Custom is false when the organisation has not defined its own palette, in which case any colour is allowed.
+type Palette struct {
+	Colours []PaletteColour `json:"colours"`
+	Custom  bool            `json:"custom"`
+}
+
+type UpdatePaletteRequest struct {
+	Colours []PaletteColour `json:"colours" binding:"required"`
+}
+
+// DefaultPalette is used by the organisations which have not defined their own palette.
+var DefaultPalette = Palette{Colours: []PaletteColour{
+	{Name: "Red", Hex: "#e53935"},
+	{Name: "Orange", Hex: "#fb8c00"},
+	{Name: "Yellow", Hex: "#fdd835"},
+	{Name: "Green", Hex: "#43a047"},
+	{Name: "Teal", Hex: "#00897b"},
+	{Name: "Blue", Hex: "#1e88e5"},
+	{Name: "Purple", Hex: "#8e24aa"},
+	{Name: "Pink", Hex: "#d81b60"},
+	{Name: "Brown", Hex: "#6d4c41"},
+	{Name: "Grey", Hex: "#757575"},
+	{Name: "Black", Hex: "#000000"},
+	{Name: "White", Hex: "#ffffff"},
+}}
+
+// Resolve returns the canonical form of the colour: the name of the palette entry it matches, by name or by value,
+// otherwise the colour as a lower case #rrggbb value. Colours outside of a custom palette are rejected.
+func (palette Palette) Resolve(colour string) (string, error) {
+	colour = strings.TrimSpace(colour)
+	for _, entry := range palette.Colours {
+		if strings.EqualFold(entry.Name, colour) {
+			return entry.Name, nil
+		}
+	}
+
+	hex, err := ParseColour(colour)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range palette.Colours {
+		if entry.Hex == hex {
+			return entry.Name, nil
+		}
+	}
+	if palette.Custom {
+		return "", fmt.Errorf("colour %v is not part of the organisation palette", colour)
+	}
+	return hex, nil
+}
+
+// ColourFor picks the palette colour of a tag created without colour. The same name always gets the same colour.
+func (palette Palette) ColourFor(name string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(NameKey(name)))
+	return palette.Colours[hash.Sum32()%uint32(len(palette.Colours))].Name
+}
+
+// NewPalette validates the colours of a custom palette: names must be unique and every value a valid colour.
+func NewPalette(colours []PaletteColour) (Palette, error) {
+	if len(colours) == 0 || len(colours) > MaxPaletteColours {
+		return Palette{}, fmt.Errorf("a palette must have between 1 and %d colours", MaxPaletteColours)
+	}
+
+	palette := Palette{Colours: make([]PaletteColour, 0), Custom: true}
+	names := make(map[string]bool)
+	for _, colour := range colours {
+		name := NormaliseName(colour.Name)
+		if name == "" {
+			return Palette{}, errors.New("colour name may not be empty")
+		}
+		// names must not be mistaken for colour values
+		if _, err := ParseColour(name); err == nil {
+			return Palette{}, fmt.Errorf("colour name %v may not be a colour value", name)
+		}
+		if names[NameKey(name)] {
+			return Palette{}, fmt.Errorf("duplicate colour name %v", name)
+		}
+		names[NameKey(name)] = true
+
+		hex, err := ParseColour(colour.Hex)
+		if err != nil {
+			return Palette{}, err
+		}
+		palette.Colours = append(palette.Colours, PaletteColour{Name: name, Hex: hex})
+	}
+	return palette, nil
+}
+
+// ParseColour converts #rgb, #rrggbb and rgb(r, g, b) colours to a lower case #rrggbb value.
+func ParseColour(colour string) (string, error) {
+	value := strings.ToLower(strings.Join(strings.Fields(colour), ""))
+	switch {
+	case strings.HasPrefix(value, "#") && len(value) == 4 && isHex(value[1:]):
+		return "#" + string([]byte{value[1], value[1], value[2], value[2], value[3], value[3]}), nil
+	case strings.HasPrefix(value, "#") && len(value) == 7 && isHex(value[1:]):
+		return value, nil
+	case strings.HasPrefix(value, "rgb(") && strings.HasSuffix(value, ")"):
+		parts := strings.Split(value[4:len(value)-1], ",")
+		if len(parts) != 3 {
+			break
+		}
+		hex := "#"
+		for _, part := range parts {
+			component, err := strconv.ParseUint(part, 10, 8)
+			if err != nil {
+				return "", fmt.Errorf("invalid colour: %v", colour)
+			}
+			hex += fmt.Sprintf("%02x", component)
+		}
+		return hex, nil
+	}
+	return "", fmt.Errorf("invalid colour: %v", colour)
+}
+
+func isHex(value string) bool {
+	_, err := strconv.ParseUint(value, 16, 32)
+	return err == nil
+}
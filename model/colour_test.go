@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestParseColour(t *testing.T) {
+	t.Logf("Given colours written in different forms")
+	{
+		for _, colour := range []string{"#F00", "#ff0000", " #FF0000 ", "rgb(255, 0, 0)", "RGB(255,0,0)"} {
+			if hex, err := ParseColour(colour); err == nil && hex == "#ff0000" {
+				t.Logf("\t\t\"%s\" should be converted to \"#ff0000\". %v", colour, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should be converted to \"#ff0000\":  \"%s\" %v. %v", colour, hex, err, BallotX)
+			}
+		}
+
+		for _, colour := range []string{"", "red", "#ff00", "#gg0000", "rgb(256, 0, 0)", "rgb(1, 2)"} {
+			if _, err := ParseColour(colour); err != nil {
+				t.Logf("\t\t\"%s\" should be rejected. %v", colour, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should be rejected. %v", colour, BallotX)
+			}
+		}
+	}
+}
+
+func TestResolveColour(t *testing.T) {
+	t.Logf("Given the default palette")
+	{
+		for colour, expected := range map[string]string{"red": "Red", "BLUE": "Blue", "#000": "Black", "#123456": "#123456"} {
+			if resolved, err := DefaultPalette.Resolve(colour); err == nil && resolved == expected {
+				t.Logf("\t\t\"%s\" should be resolved to \"%s\". %v", colour, expected, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should be resolved to \"%s\":  \"%s\" %v. %v", colour, expected, resolved, err, BallotX)
+			}
+		}
+	}
+
+	t.Logf("Given a custom palette")
+	{
+		palette, _ := NewPalette([]PaletteColour{{Name: "Brand", Hex: "#123456"}})
+		if resolved, err := palette.Resolve("#123456"); err == nil && resolved == "Brand" {
+			t.Logf("\t\tA palette value should be resolved to its name. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tA palette value should be resolved to its name:  \"%s\" %v. %v", resolved, err, BallotX)
+		}
+		if _, err := palette.Resolve("Red"); err != nil {
+			t.Logf("\t\tA colour outside of the palette should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tA colour outside of the palette should be rejected. %v", BallotX)
+		}
+	}
+}
+
+func TestNewPaletteValidation(t *testing.T) {
+	t.Logf("Given invalid palettes")
+	{
+		palettes := [][]PaletteColour{
+			{},
+			{{Name: " ", Hex: "#000"}},
+			{{Name: "#fff", Hex: "#fff"}},
+			{{Name: "Brand", Hex: "#000"}, {Name: "brand ", Hex: "#fff"}},
+			{{Name: "Brand", Hex: "blue"}},
+		}
+		for _, colours := range palettes {
+			if _, err := NewPalette(colours); err != nil {
+				t.Logf("\t\tThe palette should be rejected:  %v. %v", err, CheckMark)
+			} else {
+				t.Errorf("\t\tThe palette should be rejected:  %v. %v", colours, BallotX)
+			}
+		}
+	}
+}
+
+func TestColourForIsDeterministic(t *testing.T) {
+	t.Logf("Given the same name written differently")
+	{
+		colour := DefaultPalette.ColourFor("Dinner")
+		if DefaultPalette.ColourFor(" DINNER") == colour && colour != "" {
+			t.Logf("\t\tThe same colour should be picked. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe same colour should be picked. %v", BallotX)
+		}
+	}
+}
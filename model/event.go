@@ -0,0 +1,69 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"time"
+)
+
+// Types of the events published when tags change. A restored tag is created again as far as the other services are
+// concerned.
+const (
+	TagCreated = "TagCreated"
+	TagUpdated = "TagUpdated"
+	TagDeleted = "TagDeleted"
+)
+
+// for persistence, event waiting in the outbox until it is published
+type EventDAO struct {
+	Id             bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	Type           string        `json:"type" bson:"type"`
+	OrganisationId string        `json:"organisationId" bson:"organisationId"`
+	TagId          bson.ObjectId `json:"tagId" bson:"tagId"`
+	AccountId      string        `json:"accountId" bson:"accountId"`
+	Tag            TagDAO        `json:"tag" bson:"tag"`
+	Changes        []string      `json:"changes,omitempty" bson:"changes,omitempty"`
+	CreatedAt      time.Time     `json:"createdAt" bson:"createdAt"`
+	PublishedAt    *time.Time    `json:"publishedAt,omitempty" bson:"publishedAt,omitempty"`
+}
+
+// for persistence, tag written along with the events announcing its change. The events wait in the tag until they are
+// moved to the outbox, so that a change cannot be stored without them.
+type PendingTagDAO struct {
+	TagDAO        `bson:",inline"`
+	PendingEvents []EventDAO `json:"pendingEvents" bson:"pendingEvents"`
+}
+
+// Event as published, the tag is the one after the change or, once deleted, the last version of the tag.
+type Event struct {
+	Id             string    `json:"id"`
+	Type           string    `json:"type"`
+	OrganisationId string    `json:"organisationId"`
+	TagId          string    `json:"tagId"`
+	AccountId      string    `json:"accountId"`
+	Tag            Tag       `json:"tag"`
+	Changes        []string  `json:"changes,omitempty"`
+	OccurredAt     time.Time `json:"occurredAt"`
+}
+
+// NewEvent builds the event of the change recorded in the history, both share the same id.
+func NewEvent(record HistoryDAO) EventDAO {
+	event := EventDAO{Id: record.Id, OrganisationId: record.OrganisationId, TagId: record.TagId, AccountId: record.AccountId, Changes: record.Changes, CreatedAt: record.CreatedAt}
+	switch record.Operation {
+	case OperationCreate, OperationRestore:
+		event.Type = TagCreated
+	case OperationDelete:
+		event.Type = TagDeleted
+	default:
+		event.Type = TagUpdated
+	}
+	if record.After != nil && event.Type != TagDeleted {
+		event.Tag = *record.After
+	} else if record.Before != nil {
+		event.Tag = *record.Before
+	}
+	return event
+}
+
+func ConvertEvent(event EventDAO) Event {
+	return Event{Id: event.Id.Hex(), Type: event.Type, OrganisationId: event.OrganisationId, TagId: event.TagId.Hex(), AccountId: event.AccountId, Tag: ConvertToTag(event.Tag), Changes: event.Changes, OccurredAt: event.CreatedAt}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"testing"
+)
+
+func TestNewEvent(t *testing.T) {
+	t.Logf("Given the history of a tag")
+	{
+		before := TagDAO{Id: bson.NewObjectId(), OrganisationId: "org", Name: "Dinner"}
+		after := before
+		after.Name = "Supper"
+
+		expected := map[string]struct {
+			record HistoryDAO
+			name   string
+		}{
+			TagCreated: {NewHistory("user", OperationRestore, &before, &after), "Supper"},
+			TagUpdated: {NewHistory("user", OperationUpdate, &before, &after), "Supper"},
+			TagDeleted: {NewHistory("user", OperationDelete, &before, &after), "Dinner"},
+		}
+		for eventType, entry := range expected {
+			event := NewEvent(entry.record)
+			if event.Type == eventType && event.Id == entry.record.Id && event.Tag.Name == entry.name {
+				t.Logf("\t\tThe %v operation should result in a %v event. %v", entry.record.Operation, eventType, CheckMark)
+			} else {
+				t.Errorf("\t\tThe %v operation should result in a %v event:  \"%v\". %v", entry.record.Operation, eventType, event, BallotX)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"reflect"
+	"time"
+)
+
+// Operations recorded in the history of a tag
+const (
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+	OperationRestore = "restore"
+)
+
+// for persistence, audit record of a change made to a tag. The snapshots hold the tag before and after the change,
+// there is no before snapshot for a creation.
+type HistoryDAO struct {
+	Id             bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	OrganisationId string        `json:"organisationId" bson:"organisationId"`
+	TagId          bson.ObjectId `json:"tagId" bson:"tagId"`
+	AccountId      string        `json:"accountId" bson:"accountId"`
+	Operation      string        `json:"operation" bson:"operation"`
+	Changes        []string      `json:"changes,omitempty" bson:"changes,omitempty"`
+	Before         *TagDAO       `json:"before,omitempty" bson:"before,omitempty"`
+	After          *TagDAO       `json:"after,omitempty" bson:"after,omitempty"`
+	CreatedAt      time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+type HistoryEntry struct {
+	Id        string    `json:"id"`
+	TagId     string    `json:"tagId"`
+	AccountId string    `json:"accountId"`
+	Operation string    `json:"operation"`
+	Changes   []string  `json:"changes,omitempty"`
+	Before    *Tag      `json:"before,omitempty"`
+	After     *Tag      `json:"after,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type HistoryResponse struct {
+	Entries    []HistoryEntry `json:"entries"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// NewHistory builds the audit record of the given operation made by the account.
+func NewHistory(accountId string, operation string, before *TagDAO, after *TagDAO) HistoryDAO {
+	record := HistoryDAO{Id: bson.NewObjectId(), AccountId: accountId, Operation: operation, Before: before, After: after, CreatedAt: time.Now()}
+	for _, snapshot := range []*TagDAO{after, before} {
+		if snapshot != nil {
+			record.OrganisationId = snapshot.OrganisationId
+			record.TagId = snapshot.Id
+		}
+	}
+	if before != nil && after != nil {
+		record.Changes = ChangedFields(*before, *after)
+	}
+	return record
+}
+
+// ChangedFields returns the names of the fields which differ between the two versions of a tag.
+func ChangedFields(before TagDAO, after TagDAO) []string {
+	changes := make([]string, 0)
+	if before.Name != after.Name {
+		changes = append(changes, "name")
+	}
+	if before.Colour != after.Colour {
+		changes = append(changes, "colour")
+	}
+	if before.ParentId != after.ParentId {
+		changes = append(changes, "parentId")
+	}
+	if !reflect.DeepEqual(before.Attributes, after.Attributes) {
+		changes = append(changes, "attributes")
+	}
+	if !reflect.DeepEqual(before.Translations, after.Translations) {
+		changes = append(changes, "translations")
+	}
+	if before.Scope() != after.Scope() {
+		changes = append(changes, "visibility")
+	}
+	return changes
+}
+
+// Converts the audit records into the history response.
+func ConvertHistory(records []HistoryDAO) HistoryResponse {
+	entries := make([]HistoryEntry, 0)
+	for _, record := range records {
+		entry := HistoryEntry{Id: record.Id.Hex(), TagId: record.TagId.Hex(), AccountId: record.AccountId, Operation: record.Operation, Changes: record.Changes, CreatedAt: record.CreatedAt}
+		if record.Before != nil {
+			before := ConvertToTag(*record.Before)
+			entry.Before = &before
+		}
+		if record.After != nil {
+			after := ConvertToTag(*record.After)
+			entry.After = &after
+		}
+		entries = append(entries, entry)
+	}
+	return HistoryResponse{Entries: entries}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"reflect"
+	"testing"
+)
+
+func TestNewHistory(t *testing.T) {
+	t.Logf("Given a tag renamed and recoloured")
+	{
+		before := TagDAO{Id: bson.NewObjectId(), OrganisationId: "org", Name: "Dinner", Colour: "Red"}
+		after := before
+		after.Name = "Supper"
+		after.Colour = "Blue"
+
+		record := NewHistory("user", OperationUpdate, &before, &after)
+		if record.TagId == before.Id && record.OrganisationId == "org" && record.AccountId == "user" && reflect.DeepEqual(record.Changes, []string{"name", "colour"}) {
+			t.Logf("\t\tThe record should list the changed fields. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe record should list the changed fields:  \"%v\". %v", record, BallotX)
+		}
+
+		created := NewHistory("user", OperationCreate, nil, &after)
+		response := ConvertHistory([]HistoryDAO{created})
+		if len(response.Entries) == 1 && response.Entries[0].Before == nil && response.Entries[0].After.Name == "Supper" && len(created.Changes) == 0 {
+			t.Logf("\t\tThe creation should only have the after snapshot. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe creation should only have the after snapshot:  \"%v\". %v", response, BallotX)
+		}
+	}
+}
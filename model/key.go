@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"github.com/globalsign/mgo/bson"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Types of key, the values of a free-form key are not restricted
+const (
+	KeyFreeForm = "free"
+	KeyEnum     = "enum"
+)
+
+const MaxKeyLength = 64
+
+// Separates the key from the value in the name of a key/value tag
+const KeySeparator = ":"
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// for persistence, definition of a key of the key/value tags of an organisation
+type KeyDAO struct {
+	Id             bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	OrganisationId string        `json:"organisationId" bson:"organisationId"`
+	Key            string        `json:"key" bson:"key"`
+	Type           string        `json:"type" bson:"type"`
+	Values         []string      `json:"values,omitempty" bson:"values,omitempty"`
+	CreatedAt      time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+// The values are only given for an enum key.
+type CreateKeyRequest struct {
+	Key    string   `json:"key" binding:"required"`
+	Type   string   `json:"type" binding:"required"`
+	Values []string `json:"values,omitempty"`
+}
+
+type UpdateKeyRequest struct {
+	Type   string   `json:"type" binding:"required"`
+	Values []string `json:"values,omitempty"`
+}
+
+type Key struct {
+	Key    string   `json:"key"`
+	Type   string   `json:"type"`
+	Values []string `json:"values,omitempty"`
+}
+
+type GetKeysResponse struct {
+	Keys []Key `json:"keys"`
+}
+
+// ValidateKey checks the key is made of lower case letters, digits, dots, dashes and underscores.
+func ValidateKey(key string) error {
+	if len(key) > MaxKeyLength || !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid key %v: a key is made of up to %d lower case letters, digits, dots, dashes and underscores", key, MaxKeyLength)
+	}
+	return nil
+}
+
+// NewKeyValues validates the values allowed for a key of the given type, and returns them normalised.
+func NewKeyValues(keyType string, values []string) ([]string, error) {
+	switch keyType {
+	case KeyFreeForm:
+		if len(values) > 0 {
+			return nil, errors.New("a free-form key has no values")
+		}
+		return nil, nil
+	case KeyEnum:
+		if len(values) == 0 {
+			return nil, errors.New("an enum key needs at least one value")
+		}
+	default:
+		return nil, fmt.Errorf("the type of a key must be %v or %v", KeyFreeForm, KeyEnum)
+	}
+
+	normalised := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = NormaliseName(value)
+		if value == "" {
+			return nil, errors.New("a value may not be empty")
+		}
+		if seen[NameKey(value)] {
+			return nil, fmt.Errorf("duplicate value %v", value)
+		}
+		seen[NameKey(value)] = true
+		normalised = append(normalised, value)
+	}
+	return normalised, nil
+}
+
+// Allow checks the value is allowed for the key, and returns it as stored: normalised, or spelled as defined for an
+// enum key.
+func (key KeyDAO) Allow(value string) (string, error) {
+	value = NormaliseName(value)
+	if value == "" {
+		return "", errors.New("the value of a key/value tag may not be empty")
+	}
+	if key.Type != KeyEnum {
+		return value, nil
+	}
+	for _, allowed := range key.Values {
+		if NameKey(allowed) == NameKey(value) {
+			return allowed, nil
+		}
+	}
+	return "", fmt.Errorf("value %v is not allowed for key %v", value, key.Key)
+}
+
+// KeyedName returns the name of the key/value tag.
+func KeyedName(key string, value string) string {
+	return key + KeySeparator + value
+}
+
+// Converts the key definitions, sorted by key.
+func ConvertKeys(keys []KeyDAO) GetKeysResponse {
+	response := make([]Key, 0)
+	for _, key := range keys {
+		response = append(response, ConvertKey(key))
+	}
+	sort.SliceStable(response, func(i, j int) bool {
+		return strings.Compare(response[i].Key, response[j].Key) < 0
+	})
+	return GetKeysResponse{Keys: response}
+}
+
+func ConvertKey(key KeyDAO) Key {
+	return Key{Key: key.Key, Type: key.Type, Values: key.Values}
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	t.Logf("Given valid and invalid keys")
+	{
+		for _, key := range []string{"env", "team.payments", "cost-centre_2"} {
+			if err := ValidateKey(key); err == nil {
+				t.Logf("\t\t\"%s\" should be valid. %v", key, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should be valid:  \"%v\". %v", key, err, BallotX)
+			}
+		}
+		for _, key := range []string{"", "Env", "env:prod", "-env", "team payments"} {
+			if err := ValidateKey(key); err != nil {
+				t.Logf("\t\t\"%s\" should be invalid. %v", key, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should be invalid. %v", key, BallotX)
+			}
+		}
+	}
+}
+
+func TestNewKeyValues(t *testing.T) {
+	t.Logf("Given the values of keys")
+	{
+		if values, err := NewKeyValues(KeyEnum, []string{" prod", "staging"}); err == nil && len(values) == 2 && values[0] == "prod" {
+			t.Logf("\t\tThe values of an enum key should be normalised. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe values of an enum key should be normalised:  \"%v\" \"%v\". %v", values, err, BallotX)
+		}
+		if _, err := NewKeyValues(KeyEnum, []string{"prod", "PROD"}); err != nil {
+			t.Logf("\t\tDuplicate values should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tDuplicate values should be rejected. %v", BallotX)
+		}
+		if _, err := NewKeyValues(KeyEnum, nil); err != nil {
+			t.Logf("\t\tAn enum key without values should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tAn enum key without values should be rejected. %v", BallotX)
+		}
+		if _, err := NewKeyValues(KeyFreeForm, []string{"prod"}); err != nil {
+			t.Logf("\t\tA free-form key with values should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tA free-form key with values should be rejected. %v", BallotX)
+		}
+	}
+}
+
+func TestKeyAllow(t *testing.T) {
+	t.Logf("Given an enum and a free-form key")
+	{
+		enum := KeyDAO{Key: "env", Type: KeyEnum, Values: []string{"Prod", "staging"}}
+		free := KeyDAO{Key: "team", Type: KeyFreeForm}
+
+		if value, err := enum.Allow("prod "); err == nil && value == "Prod" {
+			t.Logf("\t\tAn allowed value should be spelled as defined. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tAn allowed value should be spelled as defined:  \"%v\" \"%v\". %v", value, err, BallotX)
+		}
+		if _, err := enum.Allow("dev"); err != nil {
+			t.Logf("\t\tAnother value should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tAnother value should be rejected. %v", BallotX)
+		}
+		if value, err := free.Allow(" payments "); err == nil && value == "payments" {
+			t.Logf("\t\tAny value should be allowed for a free-form key. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tAny value should be allowed for a free-form key:  \"%v\" \"%v\". %v", value, err, BallotX)
+		}
+		if _, err := free.Allow(" "); err != nil {
+			t.Logf("\t\tAn empty value should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tAn empty value should be rejected. %v", BallotX)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"github.com/globalsign/mgo/bson"
+	"time"
+)
+
+// Roles of the accounts within an organisation, each granting the permissions of the previous one
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Permissions guarding the endpoints: reading the tags, changing them, and managing the settings of the organisation
+const (
+	PermissionRead  = "tags:read"
+	PermissionWrite = "tags:write"
+	PermissionAdmin = "tags:admin"
+)
+
+// the roles from the least to the most privileged
+var roles = []string{RoleViewer, RoleEditor, RoleAdmin}
+
+var rolePermissions = map[string][]string{
+	RoleViewer: {PermissionRead},
+	RoleEditor: {PermissionRead, PermissionWrite},
+	RoleAdmin:  {PermissionRead, PermissionWrite, PermissionAdmin},
+}
+
+// for persistence, role of an account within an organisation
+type MembershipDAO struct {
+	Id             bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	OrganisationId string        `json:"organisationId" bson:"organisationId"`
+	AccountId      string        `json:"accountId" bson:"accountId"`
+	Role           string        `json:"role" bson:"role"`
+	UpdatedAt      time.Time     `json:"updatedAt" bson:"updatedAt"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type Member struct {
+	AccountId string `json:"accountId"`
+	Role      string `json:"role"`
+}
+
+type GetMembersResponse struct {
+	Members []Member `json:"members"`
+}
+
+// ValidateRole checks the role is one of the supported roles.
+func ValidateRole(role string) error {
+	if _, ok := rolePermissions[role]; !ok {
+		return fmt.Errorf("role must be one of %v, %v or %v", RoleViewer, RoleEditor, RoleAdmin)
+	}
+	return nil
+}
+
+// Grants tells whether the role grants the permission.
+func Grants(role string, permission string) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HighestRole returns the most privileged of the given roles, ignoring the unknown ones. Returns an empty string when
+// none is known.
+func HighestRole(names []string) string {
+	highest := -1
+	for _, name := range names {
+		for i, role := range roles {
+			if role == name && i > highest {
+				highest = i
+			}
+		}
+	}
+	if highest < 0 {
+		return ""
+	}
+	return roles[highest]
+}
+
+func ConvertMembers(memberships []MembershipDAO) GetMembersResponse {
+	members := make([]Member, 0)
+	for _, membership := range memberships {
+		members = append(members, Member{AccountId: membership.AccountId, Role: membership.Role})
+	}
+	return GetMembersResponse{Members: members}
+}
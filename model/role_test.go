@@ -0,0 +1,43 @@
+package model
+
+import "testing"
+
+func TestGrants(t *testing.T) {
+	t.Logf("Given the roles of the accounts")
+	{
+		for _, c := range []struct {
+			role       string
+			permission string
+			granted    bool
+		}{
+			{RoleViewer, PermissionRead, true},
+			{RoleViewer, PermissionWrite, false},
+			{RoleEditor, PermissionWrite, true},
+			{RoleEditor, PermissionAdmin, false},
+			{RoleAdmin, PermissionAdmin, true},
+			{"owner", PermissionRead, false},
+		} {
+			if Grants(c.role, c.permission) == c.granted {
+				t.Logf("\t\tThe %s role should grant %s: %v. %v", c.role, c.permission, c.granted, CheckMark)
+			} else {
+				t.Errorf("\t\tThe %s role should grant %s: %v. %v", c.role, c.permission, c.granted, BallotX)
+			}
+		}
+	}
+}
+
+func TestHighestRole(t *testing.T) {
+	t.Logf("Given the roles carried by a token")
+	{
+		if role := HighestRole([]string{"billing", RoleViewer, RoleAdmin, RoleEditor}); role == RoleAdmin {
+			t.Logf("\t\tThe most privileged role should be picked. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe most privileged role should be picked: \"%s\". %v", role, BallotX)
+		}
+		if role := HighestRole([]string{"billing"}); role == "" {
+			t.Logf("\t\tUnknown roles should be ignored. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tUnknown roles should be ignored: \"%s\". %v", role, BallotX)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Number of assignments of a tag, as counted by the database
+type TagUsage struct {
+	TagId      bson.ObjectId `json:"tagId" bson:"_id"`
+	Count      int           `json:"count" bson:"count"`
+	LastUsedAt time.Time     `json:"lastUsedAt" bson:"lastUsedAt"`
+}
+
+// Number of assignments of a tag to the resources of a type, as counted by the database
+type ResourceTypeUsage struct {
+	ResourceType string    `json:"resourceType" bson:"_id"`
+	Count        int       `json:"count" bson:"count"`
+	LastUsedAt   time.Time `json:"lastUsedAt" bson:"lastUsedAt"`
+}
+
+// The last used time is the time the tag was last assigned, it is missing for the tags never used.
+type TagStats struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	Colour     string     `json:"colour"`
+	Count      int        `json:"count"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+type TagStatsResponse struct {
+	TagStats
+	ResourceTypes []ResourceTypeUsage `json:"resourceTypes"`
+}
+
+// The most used tags come first, the unused tags are sorted by name.
+type StatsResponse struct {
+	TagCount        int        `json:"tagCount"`
+	AssignmentCount int        `json:"assignmentCount"`
+	MostUsed        []TagStats `json:"mostUsed"`
+	Unused          []TagStats `json:"unused"`
+}
+
+// Combines the tags with their usage, keeping at most limit of the most used tags. The usage of the tags which are
+// not given, e.g. deleted, is ignored.
+func ConvertStats(tags []TagDAO, usage []TagUsage, limit int) StatsResponse {
+	byId := make(map[bson.ObjectId]TagDAO)
+	for _, tag := range tags {
+		byId[tag.Id] = tag
+	}
+	response := StatsResponse{TagCount: len(tags), MostUsed: make([]TagStats, 0), Unused: make([]TagStats, 0)}
+	used := make(map[bson.ObjectId]bool)
+	for _, u := range usage {
+		tag, ok := byId[u.TagId]
+		if !ok {
+			continue
+		}
+		used[tag.Id] = true
+		response.AssignmentCount += u.Count
+		if len(response.MostUsed) < limit {
+			response.MostUsed = append(response.MostUsed, NewTagStats(tag, []ResourceTypeUsage{{Count: u.Count, LastUsedAt: u.LastUsedAt}}))
+		}
+	}
+	for _, tag := range tags {
+		if !used[tag.Id] {
+			response.Unused = append(response.Unused, NewTagStats(tag, nil))
+		}
+	}
+	sort.SliceStable(response.Unused, func(i, j int) bool {
+		return strings.ToLower(response.Unused[i].Name) < strings.ToLower(response.Unused[j].Name)
+	})
+	return response
+}
+
+// Sums the usage of the tag across resource types.
+func NewTagStats(tag TagDAO, usage []ResourceTypeUsage) TagStats {
+	stats := TagStats{Id: tag.Id.Hex(), Name: tag.Name, Colour: tag.Colour}
+	for _, u := range usage {
+		stats.Count += u.Count
+		if stats.LastUsedAt == nil || u.LastUsedAt.After(*stats.LastUsedAt) {
+			lastUsedAt := u.LastUsedAt
+			stats.LastUsedAt = &lastUsedAt
+		}
+	}
+	return stats
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"testing"
+	"time"
+)
+
+func TestConvertStats(t *testing.T) {
+	t.Logf("Given three tags, two of which have been used, and the usage of a deleted tag")
+	{
+		now := time.Now()
+		tags := []TagDAO{{Id: bson.NewObjectId(), Name: "Dinner"}, {Id: bson.NewObjectId(), Name: "Lunch"}, {Id: bson.NewObjectId(), Name: "Brunch"}}
+		usage := []TagUsage{{TagId: bson.NewObjectId(), Count: 9, LastUsedAt: now}, {TagId: tags[1].Id, Count: 5, LastUsedAt: now}, {TagId: tags[0].Id, Count: 2, LastUsedAt: now}}
+
+		t.Logf("\tWhen keeping the most used tag only")
+		{
+			response := ConvertStats(tags, usage, 1)
+			if response.TagCount == 3 && response.AssignmentCount == 7 && len(response.MostUsed) == 1 && response.MostUsed[0].Name == "Lunch" && response.MostUsed[0].Count == 5 {
+				t.Logf("\t\tThe most used tag should come first. %v", CheckMark)
+			} else {
+				t.Errorf("\t\tThe most used tag should come first:  \"%v\". %v", response, BallotX)
+			}
+			if len(response.Unused) == 1 && response.Unused[0].Name == "Brunch" && response.Unused[0].LastUsedAt == nil {
+				t.Logf("\t\tThe unused tag should be listed. %v", CheckMark)
+			} else {
+				t.Errorf("\t\tThe unused tag should be listed:  \"%v\". %v", response.Unused, BallotX)
+			}
+		}
+	}
+}
+
+func TestNewTagStats(t *testing.T) {
+	t.Logf("Given the usage of a tag by two resource types")
+	{
+		now := time.Now()
+		stats := NewTagStats(TagDAO{Id: bson.NewObjectId(), Name: "Dinner"}, []ResourceTypeUsage{{ResourceType: "note", Count: 2, LastUsedAt: now.Add(-time.Hour)}, {ResourceType: "task", Count: 3, LastUsedAt: now}})
+		if stats.Count == 5 && stats.LastUsedAt != nil && stats.LastUsedAt.Equal(now) {
+			t.Logf("\t\tThe usage should be summed. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe usage should be summed:  \"%v\". %v", stats, BallotX)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"time"
+)
+
+// Tag deleted since the last sync, to be removed by the client
+type Tombstone struct {
+	Id        string    `json:"id"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// The tags are given in their current state, the sync token is passed as the since parameter of the next sync.
+type ChangesResponse struct {
+	Tags      []Tag       `json:"tags"`
+	Deleted   []Tombstone `json:"deleted"`
+	SyncToken string      `json:"syncToken"`
+}
+
+// Converts the changed tags, the tags in the trash are given as tombstones.
+func ConvertChanges(tags []TagDAO) ChangesResponse {
+	response := ChangesResponse{Tags: make([]Tag, 0), Deleted: make([]Tombstone, 0)}
+	for _, tag := range tags {
+		if tag.DeletedAt != nil {
+			response.Deleted = append(response.Deleted, Tombstone{Id: tag.Id.Hex(), DeletedAt: *tag.DeletedAt})
+		} else {
+			response.Tags = append(response.Tags, ConvertToTag(tag))
+		}
+	}
+	return response
+}
+
+// for persistence, the counter of the writes of the tags of an organisation. Each write is stamped with the next
+// sequence, and the syncs are given the sequence up to which all the writes have completed as token.
+type SequenceDAO struct {
+	Id             bson.ObjectId    `json:"id" bson:"_id,omitempty"`
+	OrganisationId string           `json:"organisationId" bson:"organisationId"`
+	Sequence       int64            `json:"sequence" bson:"sequence"`
+	InFlight       []ReservationDAO `json:"inFlight" bson:"inFlight"`
+}
+
+// Sequence reserved by a write which has not completed yet. The floor is a sequence taken before the reservation, the
+// one reserved is above it.
+type ReservationDAO struct {
+	Id        bson.ObjectId `json:"id" bson:"_id"`
+	Floor     int64         `json:"floor" bson:"floor"`
+	StartedAt time.Time     `json:"startedAt" bson:"startedAt"`
+}
+
+// Returns the sequence up to which all the writes have completed. A write started before expiredAt and still in flight
+// is taken as failed and no longer held back.
+func (sequence SequenceDAO) Watermark(expiredAt time.Time) int64 {
+	watermark := sequence.Sequence
+	for _, reservation := range sequence.InFlight {
+		if reservation.StartedAt.After(expiredAt) && reservation.Floor < watermark {
+			watermark = reservation.Floor
+		}
+	}
+	return watermark
+}
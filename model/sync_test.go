@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"testing"
+	"time"
+)
+
+func TestConvertChanges(t *testing.T) {
+	t.Logf("Given a live and a trashed tag")
+	{
+		deletedAt := time.Now()
+		tags := []TagDAO{{Id: bson.NewObjectId(), Name: "Dinner"}, {Id: bson.NewObjectId(), Name: "Supper", DeletedAt: &deletedAt}}
+
+		response := ConvertChanges(tags)
+		if len(response.Tags) == 1 && response.Tags[0].Name == "Dinner" && len(response.Deleted) == 1 && response.Deleted[0].Id == tags[1].Id.Hex() {
+			t.Logf("\t\tThe trashed tag should be given as a tombstone. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe trashed tag should be given as a tombstone:  \"%v\". %v", response, BallotX)
+		}
+	}
+}
+
+func TestWatermark(t *testing.T) {
+	t.Logf("Given a sequence with writes in flight")
+	{
+		now := time.Now()
+		sequence := SequenceDAO{Sequence: 12, InFlight: []ReservationDAO{
+			{Id: bson.NewObjectId(), Floor: 9, StartedAt: now},
+			{Id: bson.NewObjectId(), Floor: 7, StartedAt: now.Add(-time.Hour)},
+			{Id: bson.NewObjectId(), Floor: 11, StartedAt: now},
+		}}
+
+		if watermark := sequence.Watermark(now.Add(-time.Minute)); watermark == 9 {
+			t.Logf("\t\tThe watermark should stop below the oldest write still in flight. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe watermark should stop below the oldest write still in flight:  \"%d\". %v", watermark, BallotX)
+		}
+		if watermark := (SequenceDAO{Sequence: 12}).Watermark(now); watermark == 12 {
+			t.Logf("\t\tThe watermark should be the sequence without writes in flight. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe watermark should be the sequence without writes in flight:  \"%d\". %v", watermark, BallotX)
+		}
+	}
+}
@@ -2,41 +2,97 @@ package model
 
 import (
 	"github.com/globalsign/mgo/bson"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"time"
 )
 
 // for persistence
 type TagDAO struct {
-	Id             bson.ObjectId `json:"id" bson:"_id,omitempty"`
-	AccountId      string        `json:"accountId" bson:"accountId",omitempty`
-	OrganisationId string        `json:"organisationId" bson:"organisationId",omitempty`
-	Name           string        `json:"name" bson:"name"`
-	Colour         string        `json:"colour" bson: "colour"`
+	Id             bson.ObjectId          `json:"id" bson:"_id,omitempty"`
+	AccountId      string                 `json:"accountId" bson:"accountId",omitempty`
+	OrganisationId string                 `json:"organisationId" bson:"organisationId",omitempty`
+	Name           string                 `json:"name" bson:"name"`
+	Colour         string                 `json:"colour" bson: "colour"`
+	ParentId       bson.ObjectId          `json:"parentId,omitempty" bson:"parentId,omitempty"`
+	NormalisedName string                 `json:"normalisedName,omitempty" bson:"normalisedName,omitempty"`
+	Version        int                    `json:"version" bson:"version"`
+	DeletedAt      *time.Time             `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	UpdatedAt      time.Time              `json:"updatedAt" bson:"updatedAt,omitempty"`
+	Sequence       int64                  `json:"sequence,omitempty" bson:"sequence,omitempty"`
+	Key            string                 `json:"key,omitempty" bson:"key,omitempty"`
+	Value          string                 `json:"value,omitempty" bson:"value,omitempty"`
+	Attributes     map[string]interface{} `json:"attributes,omitempty" bson:"attributes,omitempty"`
+	Translations   map[string]string      `json:"translations,omitempty" bson:"translations,omitempty"`
+	Visibility     string                 `json:"visibility,omitempty" bson:"visibility,omitempty"`
 }
 
 type CreateTagResponse struct {
 	Id string `json:Id`
 }
 
+// A colour is picked from the palette when none is given. A key/value tag is created by giving a key and a value
+// instead of a name, its name is then key:value. The translations of the name are keyed by BCP 47 language tag. The
+// tag is shared with the organisation unless another visibility is given.
 type CreateTagRequest struct {
-	Name   string `json:"name" binding:"required"`
-	Colour string `json:"colour" binding:"required"`
+	Name         string                 `json:"name,omitempty"`
+	Colour       string                 `json:"colour,omitempty"`
+	ParentId     string                 `json:"parentId,omitempty"`
+	Key          string                 `json:"key,omitempty"`
+	Value        string                 `json:"value,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Translations map[string]string      `json:"translations,omitempty"`
+	Visibility   string                 `json:"visibility,omitempty"`
+}
+
+// An empty parentId moves the tag to the root of the tree, a colour is picked from the palette when none is given.
+// The attributes and translations replace the current ones.
+type UpdateTagRequest struct {
+	Name         string                 `json:"name" binding:"required"`
+	Colour       string                 `json:"colour,omitempty"`
+	ParentId     string                 `json:"parentId,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Translations map[string]string      `json:"translations,omitempty"`
+}
+
+// Only the fields present in the payload are updated, an empty parentId moves the tag to the root of the tree. The
+// value may only be given for a key/value tag, whose name follows it. The attributes given are merged into the current
+// ones, those given as null are removed. Likewise for the translations, those given with an empty name being removed.
+type PatchTagRequest struct {
+	Name         *string                `json:"name,omitempty"`
+	Colour       *string                `json:"colour,omitempty"`
+	ParentId     *string                `json:"parentId,omitempty"`
+	Value        *string                `json:"value,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Translations map[string]string      `json:"translations,omitempty"`
 }
 
 type GetAllTagResponse struct {
-	Tags []Tag `json:tags`
+	Tags       []Tag  `json:tags`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Total      *int   `json:"total,omitempty"`
 }
 
 type Tag struct {
-	Id             string `json:"id"`
-	AccountId      string `json:"AccountId" `
-	OrganisationId string `json:"organisationId" `
-	Name           string `json:"name"`
-	Colour         string `json:"colour"`
+	Id             string                 `json:"id"`
+	AccountId      string                 `json:"AccountId" `
+	OrganisationId string                 `json:"organisationId" `
+	Name           string                 `json:"name"`
+	Colour         string                 `json:"colour"`
+	ParentId       string                 `json:"parentId,omitempty"`
+	Key            string                 `json:"key,omitempty"`
+	Value          string                 `json:"value,omitempty"`
+	Attributes     map[string]interface{} `json:"attributes,omitempty"`
+	Translations   map[string]string      `json:"translations,omitempty"`
+	Visibility     string                 `json:"visibility"`
 }
 
 type ErrorResponse struct {
-	Message string `json:"message"`
-	Code    int    `json:code`
+	Message    string       `json:"message"`
+	Code       int          `json:code`
+	ExistingId string       `json:"existingId,omitempty"`
+	Errors     []FieldError `json:"errors,omitempty"`
 }
 
 type EmptyBody struct{}
@@ -44,11 +100,30 @@ type EmptyBody struct{}
 func Convert(tags []TagDAO) GetAllTagResponse {
 	response := make([]Tag, 0)
 	for _, tag := range tags {
-		response = append(response, Tag{Id: tag.Id.Hex(), Name: tag.Name, Colour: tag.Colour, AccountId: tag.AccountId})
+		response = append(response, Tag{Id: tag.Id.Hex(), Name: tag.Name, Colour: tag.Colour, AccountId: tag.AccountId, ParentId: hex(tag.ParentId), Key: tag.Key, Value: tag.Value, Attributes: tag.Attributes, Translations: tag.Translations, Visibility: tag.Scope()})
 	}
-	return GetAllTagResponse{response}
+	return GetAllTagResponse{Tags: response}
 }
 
 func ConvertToTag(tag TagDAO) Tag {
-	return Tag{Id: tag.Id.Hex(), Name: tag.Name, Colour: tag.Colour, AccountId: tag.AccountId, OrganisationId: tag.OrganisationId}
+	return Tag{Id: tag.Id.Hex(), Name: tag.Name, Colour: tag.Colour, AccountId: tag.AccountId, OrganisationId: tag.OrganisationId, ParentId: hex(tag.ParentId), Key: tag.Key, Value: tag.Value, Attributes: tag.Attributes, Translations: tag.Translations, Visibility: tag.Scope()}
+}
+
+// NormaliseName returns the name as it is stored: trimmed and in Unicode NFC.
+func NormaliseName(name string) string {
+	return norm.NFC.String(strings.TrimSpace(name))
+}
+
+// NameKey returns the key tag names are compared with, so that "Dinner", "dinner " and "DINNER" are the same name.
+// The names are case folded rather than lower cased, so that "STRASSE" and "straße" are the same name too.
+func NameKey(name string) string {
+	return norm.NFC.String(cases.Fold().String(NormaliseName(name)))
+}
+
+// Returns the hex representation of the given id, or an empty string for an unset id.
+func hex(id bson.ObjectId) string {
+	if id == "" {
+		return ""
+	}
+	return id.Hex()
 }
@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/globalsign/mgo/bson"
+	"reflect"
 	"testing"
 )
 
@@ -12,14 +13,14 @@ const (
 
 func TestConvertWithDataReturnsGetAllTagResponse(t *testing.T) {
 	id := bson.NewObjectId()
-	tagDAOList := []TagDAO{{id, "user", "org", "tag text", "blue"}}
+	tagDAOList := []TagDAO{{Id: id, AccountId: "user", OrganisationId: "org", Name: "tag text", Colour: "blue"}}
 	t.Logf("Given a tagDAO array ")
 	{
 		response := Convert(tagDAOList)
 		if response.Tags != nil {
-			t.Logf("\t\tThe GetAllResponse is correctly converted:  \"%s\". %v", response, CheckMark)
+			t.Logf("\t\tThe GetAllResponse is correctly converted:  \"%v\". %v", response, CheckMark)
 		} else {
-			t.Errorf("\t\tThe GetAllResponse.Tags shouldn't be nil: \"%s\". %v", response, BallotX)
+			t.Errorf("\t\tThe GetAllResponse.Tags shouldn't be nil: \"%v\". %v", response, BallotX)
 		}
 	}
 }
@@ -29,9 +30,9 @@ func TestConvertEmptyList(t *testing.T) {
 	{
 		response := Convert([]TagDAO{})
 		if len(response.Tags) == 0 {
-			t.Logf("\t\tThe GetAllResponse is correctly converted:  \"%s\". %v", response, CheckMark)
+			t.Logf("\t\tThe GetAllResponse is correctly converted:  \"%v\". %v", response, CheckMark)
 		} else {
-			t.Errorf("\t\tThe GetAllResponse.Tags shouldn't be nil: \"%s\". %v", response, BallotX)
+			t.Errorf("\t\tThe GetAllResponse.Tags shouldn't be nil: \"%v\". %v", response, BallotX)
 		}
 	}
 }
@@ -40,12 +41,57 @@ func TestConvertToTag(t *testing.T) {
 	t.Logf("Given a tagDAO")
 	{
 		id := bson.NewObjectId()
-		expectedType := Tag{id.Hex(), "user", "org", "tag text", "blue"}
-		response := ConvertToTag(TagDAO{id, "user","org",  "tag text", "blue"})
-		if response == expectedType {
+		expectedType := Tag{Id: id.Hex(), AccountId: "user", OrganisationId: "org", Name: "tag text", Colour: "blue", Visibility: VisibilityOrganisation}
+		response := ConvertToTag(TagDAO{Id: id, AccountId: "user", OrganisationId: "org", Name: "tag text", Colour: "blue"})
+		if reflect.DeepEqual(response, expectedType) {
 			t.Logf("\t\tThe tag converted matches with the tagDAO:  \"%s\". %v", expectedType, CheckMark)
 		} else {
 			t.Errorf("\t\t The tagDAO and tag converted should match: \"%s\". %v", response, BallotX)
 		}
 	}
 }
+
+func TestNameKeyIgnoresCaseAndSurroundingSpaces(t *testing.T) {
+	t.Logf("Given names differing by case, surrounding spaces and Unicode composition")
+	{
+		for _, name := range []string{"Dinner", "dinner ", " DINNER", "\tDiNNeR\n"} {
+			if key := NameKey(name); key == "dinner" {
+				t.Logf("\t\t\"%s\" should have the key \"dinner\". %v", name, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should have the key \"dinner\":  \"%s\". %v", name, key, BallotX)
+			}
+		}
+
+		// the accent is a combining character in the second name
+		if NormaliseName(" Caf\u00e9 ") == "Caf\u00e9" && NameKey("Caf\u00e9") == NameKey("CAFE\u0301") {
+			t.Logf("\t\tThe names should be trimmed and composed. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe names should be trimmed and composed:  \"%s\". %v", NameKey("CAFE\u0301"), BallotX)
+		}
+	}
+}
+
+func TestNameKeyFoldsCase(t *testing.T) {
+	t.Logf("Given names only differing by the special cases of the case folding")
+	{
+		pairs := [][2]string{
+			{"STRASSE", "stra\u00dfe"},
+			{"Stra\u1e9ee", "strasse"},
+			{"\u039f\u0394\u039f\u03a3", "\u03bf\u03b4\u03bf\u03c2"},
+			{"\ufb01le", "FILE"},
+			{"K\u00e9lvin", "\u212a\u00c9LVIN"},
+		}
+		for _, pair := range pairs {
+			if NameKey(pair[0]) == NameKey(pair[1]) {
+				t.Logf("\t\t\"%s\" and \"%s\" should have the same key. %v", pair[0], pair[1], CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" and \"%s\" should have the same key:  \"%s\" \"%s\". %v", pair[0], pair[1], NameKey(pair[0]), NameKey(pair[1]), BallotX)
+			}
+		}
+		if NameKey("Dinner") != NameKey("Diner") {
+			t.Logf("\t\tDifferent names should keep different keys. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tDifferent names should keep different keys. %v", BallotX)
+		}
+	}
+}
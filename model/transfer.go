@@ -0,0 +1,155 @@
+package model
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/globalsign/mgo/bson"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Formats of the exported and imported files
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Modes of an import, deciding what happens to the rows named as an existing tag
+const (
+	ImportSkip      = "skip"
+	ImportOverwrite = "overwrite"
+	ImportFail      = "fail"
+)
+
+// Outcomes of the rows of an import
+const (
+	RowCreated  = "created"
+	RowUpdated  = "updated"
+	RowSkipped  = "skipped"
+	RowInvalid  = "invalid"
+	RowConflict = "conflict"
+)
+
+// Columns of the CSV files, the id is exported for reference and ignored on import.
+var transferColumns = []string{"id", "name", "colour", "parent"}
+
+// Tag as exported and imported, the parent is given by name so that the file can be imported in another organisation.
+type TransferTag struct {
+	Id     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Colour string `json:"colour,omitempty"`
+	Parent string `json:"parent,omitempty"`
+}
+
+// Rows are numbered from 1, not counting the CSV header.
+type ImportResult struct {
+	Row        int    `json:"row"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Id         string `json:"id,omitempty"`
+	ExistingId string `json:"existingId,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+type ImportResponse struct {
+	Created int            `json:"created"`
+	Updated int            `json:"updated"`
+	Skipped int            `json:"skipped"`
+	Failed  int            `json:"failed"`
+	Rows    []ImportResult `json:"rows"`
+}
+
+// Converts the tags for export, the parents before their children so that the file can be imported in order.
+func ConvertTransfer(tags []TagDAO) []TransferTag {
+	byId := make(map[bson.ObjectId]TagDAO)
+	for _, tag := range tags {
+		byId[tag.Id] = tag
+	}
+	depth := func(tag TagDAO) int {
+		d := 0
+		for parent, ok := byId[tag.ParentId]; ok && d < len(tags); parent, ok = byId[parent.ParentId] {
+			d++
+		}
+		return d
+	}
+
+	sorted := append([]TagDAO{}, tags...)
+	depths := make(map[bson.ObjectId]int)
+	for _, tag := range sorted {
+		depths[tag.Id] = depth(tag)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if depths[sorted[i].Id] != depths[sorted[j].Id] {
+			return depths[sorted[i].Id] < depths[sorted[j].Id]
+		}
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+
+	response := make([]TransferTag, 0)
+	for _, tag := range sorted {
+		response = append(response, TransferTag{Id: tag.Id.Hex(), Name: tag.Name, Colour: tag.Colour, Parent: byId[tag.ParentId].Name})
+	}
+	return response
+}
+
+// WriteCSV writes the tags as CSV, with a header.
+func WriteCSV(out io.Writer, tags []TransferTag) error {
+	writer := csv.NewWriter(out)
+	writer.Write(transferColumns)
+	for _, tag := range tags {
+		writer.Write([]string{tag.Id, tag.Name, tag.Colour, tag.Parent})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCSV reads the tags of a CSV file. The header must name a name column, the colour and parent columns are
+// optional and the other columns ignored.
+func ReadCSV(in io.Reader) ([]TransferTag, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("the header has no name column")
+	}
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	tags := make([]TransferTag, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return tags, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, TransferTag{Name: field(record, "name"), Colour: field(record, "colour"), Parent: field(record, "parent")})
+	}
+}
+
+// ReadJSON reads the tags of a JSON file holding an array of tags.
+func ReadJSON(in io.Reader) ([]TransferTag, error) {
+	var tags []TransferTag
+	if err := json.NewDecoder(in).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("the file is not a JSON array of tags: %v", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"bytes"
+	"github.com/globalsign/mgo/bson"
+	"strings"
+	"testing"
+)
+
+func TestConvertTransfer(t *testing.T) {
+	t.Logf("Given a child tag listed before its parent")
+	{
+		parent := TagDAO{Id: bson.NewObjectId(), Name: "Meals"}
+		tags := []TagDAO{{Id: bson.NewObjectId(), Name: "Dinner", ParentId: parent.Id}, parent, {Id: bson.NewObjectId(), Name: "Archive"}}
+
+		exported := ConvertTransfer(tags)
+		if len(exported) == 3 && exported[0].Name == "Archive" && exported[1].Name == "Meals" && exported[2].Name == "Dinner" && exported[2].Parent == "Meals" {
+			t.Logf("\t\tThe parents should come first and be given by name. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe parents should come first and be given by name:  \"%v\". %v", exported, BallotX)
+		}
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	t.Logf("Given tags written as CSV")
+	{
+		var out bytes.Buffer
+		WriteCSV(&out, []TransferTag{{Id: "1", Name: "Meals", Colour: "#ff0000"}, {Id: "2", Name: "Dinner, late", Parent: "Meals"}})
+
+		tags, err := ReadCSV(&out)
+		if err == nil && len(tags) == 2 && tags[1].Name == "Dinner, late" && tags[1].Parent == "Meals" && tags[0].Colour == "#ff0000" && tags[0].Id == "" {
+			t.Logf("\t\tThe tags should be read back without their id. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe tags should be read back without their id:  \"%v\" \"%v\". %v", tags, err, BallotX)
+		}
+	}
+
+	t.Logf("Given a CSV file with other columns and no colour")
+	{
+		tags, err := ReadCSV(strings.NewReader("Parent,Name,notes\n,Meals,x\nMeals,Dinner\n"))
+		if err == nil && len(tags) == 2 && tags[0].Name == "Meals" && tags[1].Parent == "Meals" {
+			t.Logf("\t\tThe columns should be found by name. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe columns should be found by name:  \"%v\" \"%v\". %v", tags, err, BallotX)
+		}
+	}
+
+	t.Logf("Given a CSV file without a name column")
+	{
+		if _, err := ReadCSV(strings.NewReader("colour\nred\n")); err != nil {
+			t.Logf("\t\tThe file should be rejected. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe file should be rejected. %v", BallotX)
+		}
+	}
+}
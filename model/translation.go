@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"golang.org/x/text/language"
+	"sort"
+)
+
+const MaxTranslations = 64
+
+// NormaliseTranslations validates the translations of a tag name and returns them keyed by canonical BCP 47 language
+// tag, e.g. en-GB, with the names normalised.
+func NormaliseTranslations(translations map[string]string) (map[string]string, error) {
+	if len(translations) > MaxTranslations {
+		return nil, fmt.Errorf("a tag may not have more than %d translations", MaxTranslations)
+	}
+	normalised := make(map[string]string)
+	for lang, name := range translations {
+		tag, err := language.Parse(lang)
+		if err != nil || tag == language.Und {
+			return nil, fmt.Errorf("invalid language tag: %v", lang)
+		}
+		if _, ok := normalised[tag.String()]; ok {
+			return nil, fmt.Errorf("duplicate translation: %v", tag)
+		}
+		if NormaliseName(name) == "" {
+			return nil, fmt.Errorf("the translation %v may not be empty", tag)
+		}
+		normalised[tag.String()] = NormaliseName(name)
+	}
+	if len(normalised) == 0 {
+		return nil, nil
+	}
+	return normalised, nil
+}
+
+// MergeTranslations applies the translations of a patch: the languages given replace the current translations, those
+// given with an empty name are removed.
+func MergeTranslations(current map[string]string, patch map[string]string) (map[string]string, error) {
+	merged := make(map[string]string)
+	for lang, name := range current {
+		merged[lang] = name
+	}
+	for lang, name := range patch {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return nil, fmt.Errorf("invalid language tag: %v", lang)
+		}
+		delete(merged, tag.String())
+		if name != "" {
+			merged[tag.String()] = name
+		}
+	}
+	return NormaliseTranslations(merged)
+}
+
+// PreferredLanguages returns the languages of an Accept-Language header, most preferred first. An invalid header is
+// ignored.
+func PreferredLanguages(header string) []language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	return tags
+}
+
+// Localise names the tags in the translation best matching the preferred languages, falling back to their default
+// name when no translation is close enough.
+func Localise(tags []TagDAO, preferred []language.Tag) []TagDAO {
+	if len(preferred) == 0 {
+		return tags
+	}
+	localised := make([]TagDAO, len(tags))
+	for i, tag := range tags {
+		localised[i] = tag
+		localised[i].Name = LocalisedName(tag, preferred)
+	}
+	return localised
+}
+
+// LocalisedName returns the translation of the tag name best matching the preferred languages, or its default name.
+func LocalisedName(tag TagDAO, preferred []language.Tag) string {
+	if len(tag.Translations) == 0 || len(preferred) == 0 {
+		return tag.Name
+	}
+	// the first supported language is the fallback of the matcher, it stands for the default name
+	langs := make([]string, 0)
+	for lang := range tag.Translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	supported := []language.Tag{language.Und}
+	names := []string{tag.Name}
+	for _, lang := range langs {
+		supported = append(supported, language.Make(lang))
+		names = append(names, tag.Translations[lang])
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(preferred...)
+	if confidence < language.High {
+		return tag.Name
+	}
+	return names[index]
+}
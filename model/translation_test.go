@@ -0,0 +1,61 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormaliseTranslations(t *testing.T) {
+	t.Logf("Given translations keyed by language tag")
+	{
+		translations, err := NormaliseTranslations(map[string]string{"FR": " Dîner ", "en_gb": "Dinner"})
+		if err == nil && reflect.DeepEqual(translations, map[string]string{"fr": "Dîner", "en-GB": "Dinner"}) {
+			t.Logf("\t\tThe language tags should be canonical. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe language tags should be canonical: %v %v. %v", translations, err, BallotX)
+		}
+
+		for _, invalid := range []map[string]string{{"x": "Dinner"}, {"und": "Dinner"}, {"fr": " "}, {"en-GB": "Dinner", "en_gb": "Dinner"}} {
+			if _, err := NormaliseTranslations(invalid); err != nil {
+				t.Logf("\t\t%v should be rejected. %v", invalid, CheckMark)
+			} else {
+				t.Errorf("\t\t%v should be rejected. %v", invalid, BallotX)
+			}
+		}
+	}
+}
+
+func TestMergeTranslations(t *testing.T) {
+	t.Logf("Given the translations of a tag")
+	{
+		current := map[string]string{"fr": "Dîner", "de": "Abendessen"}
+		merged, err := MergeTranslations(current, map[string]string{"DE": "", "es": "Cena"})
+		if err == nil && reflect.DeepEqual(merged, map[string]string{"fr": "Dîner", "es": "Cena"}) {
+			t.Logf("\t\tThe patch should replace and remove translations. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe patch should replace and remove translations: %v %v. %v", merged, err, BallotX)
+		}
+	}
+}
+
+func TestLocalisedName(t *testing.T) {
+	t.Logf("Given a tag translated in French, British English and Traditional Chinese")
+	{
+		tag := TagDAO{Name: "Dinner", Translations: map[string]string{"fr": "Dîner", "en-GB": "Tea", "zh-Hant": "晚餐"}}
+		for header, expected := range map[string]string{
+			"fr-CA":                 "Dîner",
+			"en-GB,en;q=0.8":        "Tea",
+			"zh-TW":                 "晚餐",
+			"de":                    "Dinner",
+			"de, fr;q=0.5":          "Dîner",
+			"":                      "Dinner",
+			"not a language header": "Dinner",
+		} {
+			if name := LocalisedName(tag, PreferredLanguages(header)); name == expected {
+				t.Logf("\t\t\"%s\" should pick \"%s\". %v", header, expected, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should pick \"%s\": \"%s\". %v", header, expected, name, BallotX)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"sort"
+	"time"
+)
+
+// Tag waiting in the trash to be restored or purged
+type TrashedTag struct {
+	Id        string    `json:"id"`
+	AccountId string    `json:"accountId"`
+	Name      string    `json:"name"`
+	Colour    string    `json:"colour"`
+	ParentId  string    `json:"parentId,omitempty"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+type TrashResponse struct {
+	Tags       []TrashedTag `json:"tags"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// Converts the trashed tags, the most recently deleted first.
+func ConvertTrash(tags []TagDAO) TrashResponse {
+	response := make([]TrashedTag, 0)
+	for _, tag := range tags {
+		if tag.DeletedAt == nil {
+			continue
+		}
+		response = append(response, TrashedTag{Id: tag.Id.Hex(), AccountId: tag.AccountId, Name: tag.Name, Colour: tag.Colour, ParentId: hex(tag.ParentId), DeletedAt: *tag.DeletedAt})
+	}
+	sort.SliceStable(response, func(i, j int) bool {
+		return response[i].DeletedAt.After(response[j].DeletedAt)
+	})
+	return TrashResponse{Tags: response}
+}
+
+// Returns the descendants of the given trashed tag which were deleted together with it, i.e. at the same time.
+func TrashedWith(tags []TagDAO, tag TagDAO) []TagDAO {
+	byId := make(map[bson.ObjectId]TagDAO)
+	for _, t := range tags {
+		byId[t.Id] = t
+	}
+
+	result := make([]TagDAO, 0)
+	for _, id := range DescendantIds(tags, tag.Id) {
+		if descendant := byId[id]; descendant.DeletedAt != nil && tag.DeletedAt != nil && descendant.DeletedAt.Equal(*tag.DeletedAt) {
+			result = append(result, descendant)
+		}
+	}
+	return result
+}
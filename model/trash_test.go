@@ -0,0 +1,34 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"testing"
+	"time"
+)
+
+func TestTrashedWith(t *testing.T) {
+	t.Logf("Given a tag deleted with one of its children, the other child having been deleted before")
+	{
+		deletedAt := time.Now()
+		before := deletedAt.Add(-time.Hour)
+		travel := TagDAO{Id: bson.NewObjectId(), Name: "Travel", DeletedAt: &deletedAt}
+		flights := TagDAO{Id: bson.NewObjectId(), Name: "Flights", ParentId: travel.Id, DeletedAt: &deletedAt}
+		business := TagDAO{Id: bson.NewObjectId(), Name: "Business", ParentId: flights.Id, DeletedAt: &deletedAt}
+		hotels := TagDAO{Id: bson.NewObjectId(), Name: "Hotels", ParentId: travel.Id, DeletedAt: &before}
+		tags := []TagDAO{travel, flights, business, hotels}
+
+		restored := TrashedWith(tags, travel)
+		if len(restored) == 2 && restored[0].Id == flights.Id && restored[1].Id == business.Id {
+			t.Logf("\t\tOnly the descendants deleted with the tag should be returned. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tOnly the descendants deleted with the tag should be returned:  \"%v\". %v", restored, BallotX)
+		}
+
+		trash := ConvertTrash(append(tags, TagDAO{Id: bson.NewObjectId(), Name: "Dinner"}))
+		if len(trash.Tags) == 4 && trash.Tags[3].Id == hotels.Id.Hex() {
+			t.Logf("\t\tThe trash should hold the deleted tags, the most recently deleted first. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe trash should hold the deleted tags, the most recently deleted first:  \"%v\". %v", trash, BallotX)
+		}
+	}
+}
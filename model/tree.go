@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"github.com/globalsign/mgo/bson"
+	"sort"
+)
+
+// Tag with its child tags
+type TagNode struct {
+	Tag
+	Children []TagNode `json:"children"`
+}
+
+type TagTreeResponse struct {
+	Tags []TagNode `json:"tags"`
+}
+
+// Builds the forest of the given tags. Tags whose parent is not part of the list are returned as roots, siblings are
+// sorted by name.
+func BuildForest(tags []TagDAO) TagTreeResponse {
+	byId := make(map[bson.ObjectId]bool)
+	for _, tag := range tags {
+		byId[tag.Id] = true
+	}
+	children := childrenByParent(tags)
+	roots := make([]TagDAO, 0)
+	for _, tag := range tags {
+		if tag.ParentId == "" || !byId[tag.ParentId] {
+			roots = append(roots, tag)
+		}
+	}
+	return TagTreeResponse{buildNodes(sortByName(roots), children, make(map[bson.ObjectId]bool))}
+}
+
+// Builds the forest of the descendants of the given tag.
+func BuildSubtree(tags []TagDAO, id bson.ObjectId) TagTreeResponse {
+	children := childrenByParent(tags)
+	return TagTreeResponse{buildNodes(children[id], children, map[bson.ObjectId]bool{id: true})}
+}
+
+// Returns the ids of all the descendants of the given tag.
+func DescendantIds(tags []TagDAO, id bson.ObjectId) []bson.ObjectId {
+	children := childrenByParent(tags)
+	visited := map[bson.ObjectId]bool{id: true}
+	ids := make([]bson.ObjectId, 0)
+	queue := []bson.ObjectId{id}
+	for len(queue) > 0 {
+		for _, child := range children[queue[0]] {
+			if !visited[child.Id] {
+				visited[child.Id] = true
+				ids = append(ids, child.Id)
+				queue = append(queue, child.Id)
+			}
+		}
+		queue = queue[1:]
+	}
+	return ids
+}
+
+// ValidateParent checks the parent exists among the tags of the organisation and that the tag would not become its
+// own ancestor. Returns the parent id.
+func ValidateParent(tags []TagDAO, id bson.ObjectId, parentId string) (bson.ObjectId, error) {
+	if !bson.IsObjectIdHex(parentId) {
+		return "", errors.New("invalid parent id")
+	}
+	oid := bson.ObjectIdHex(parentId)
+
+	found := false
+	for _, tag := range tags {
+		found = found || tag.Id == oid
+	}
+	if !found {
+		return "", errors.New("parent tag not found")
+	}
+
+	if CreatesCycle(tags, id, oid) {
+		return "", errors.New("a tag may not be moved under itself or one of its descendants")
+	}
+	return oid, nil
+}
+
+// Checks whether moving the given tag under the given parent would make the tag its own ancestor.
+func CreatesCycle(tags []TagDAO, id bson.ObjectId, parentId bson.ObjectId) bool {
+	if id == parentId {
+		return true
+	}
+	for _, descendant := range DescendantIds(tags, id) {
+		if descendant == parentId {
+			return true
+		}
+	}
+	return false
+}
+
+// Groups the tags by parent id, sorted by name.
+func childrenByParent(tags []TagDAO) map[bson.ObjectId][]TagDAO {
+	children := make(map[bson.ObjectId][]TagDAO)
+	for _, tag := range tags {
+		if tag.ParentId != "" {
+			children[tag.ParentId] = append(children[tag.ParentId], tag)
+		}
+	}
+	for parentId := range children {
+		sortByName(children[parentId])
+	}
+	return children
+}
+
+// Converts the tags into nodes, each tag is visited once so that corrupted data cannot loop forever.
+func buildNodes(tags []TagDAO, children map[bson.ObjectId][]TagDAO, visited map[bson.ObjectId]bool) []TagNode {
+	nodes := make([]TagNode, 0, len(tags))
+	for _, tag := range tags {
+		if visited[tag.Id] {
+			continue
+		}
+		visited[tag.Id] = true
+		nodes = append(nodes, TagNode{ConvertToTag(tag), buildNodes(children[tag.Id], children, visited)})
+	}
+	return nodes
+}
+
+func sortByName(tags []TagDAO) []TagDAO {
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
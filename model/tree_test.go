@@ -0,0 +1,120 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"testing"
+)
+
+// Travel > Flights > Business and Travel > Hotels, Dinner
+func taxonomy() (travel, flights, business, hotels, dinner TagDAO) {
+	travel = TagDAO{Id: bson.NewObjectId(), Name: "Travel"}
+	flights = TagDAO{Id: bson.NewObjectId(), Name: "Flights", ParentId: travel.Id}
+	business = TagDAO{Id: bson.NewObjectId(), Name: "Business", ParentId: flights.Id}
+	hotels = TagDAO{Id: bson.NewObjectId(), Name: "Hotels", ParentId: travel.Id}
+	dinner = TagDAO{Id: bson.NewObjectId(), Name: "Dinner"}
+	return
+}
+
+func TestBuildForest(t *testing.T) {
+	t.Logf("Given a taxonomy of tags")
+	{
+		travel, flights, business, hotels, dinner := taxonomy()
+		forest := BuildForest([]TagDAO{business, travel, hotels, dinner, flights})
+		if len(forest.Tags) == 2 && forest.Tags[0].Name == "Dinner" && forest.Tags[1].Name == "Travel" {
+			t.Logf("\t\tThe roots should be sorted by name. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe roots should be sorted by name:  \"%v\". %v", forest, BallotX)
+			return
+		}
+		children := forest.Tags[1].Children
+		if len(children) == 2 && children[0].Name == "Flights" && children[1].Name == "Hotels" &&
+			len(children[0].Children) == 1 && children[0].Children[0].Id == business.Id.Hex() {
+			t.Logf("\t\tThe children should be nested under their parent. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe children should be nested under their parent:  \"%v\". %v", children, BallotX)
+		}
+	}
+}
+
+func TestBuildForestWithMissingParent(t *testing.T) {
+	t.Logf("Given a tag whose parent is not part of the list")
+	{
+		orphan := TagDAO{Id: bson.NewObjectId(), Name: "Orphan", ParentId: bson.NewObjectId()}
+		forest := BuildForest([]TagDAO{orphan})
+		if len(forest.Tags) == 1 && forest.Tags[0].Id == orphan.Id.Hex() {
+			t.Logf("\t\tThe tag should be returned as a root. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe tag should be returned as a root:  \"%v\". %v", forest, BallotX)
+		}
+	}
+}
+
+func TestBuildSubtree(t *testing.T) {
+	t.Logf("Given a taxonomy of tags")
+	{
+		travel, flights, business, hotels, dinner := taxonomy()
+		subtree := BuildSubtree([]TagDAO{travel, flights, business, hotels, dinner}, flights.Id)
+		if len(subtree.Tags) == 1 && subtree.Tags[0].Id == business.Id.Hex() {
+			t.Logf("\t\tThe subtree should contain the descendants of the tag. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe subtree should contain the descendants of the tag:  \"%v\". %v", subtree, BallotX)
+		}
+	}
+}
+
+func TestDescendantIds(t *testing.T) {
+	t.Logf("Given a taxonomy of tags")
+	{
+		travel, flights, business, hotels, dinner := taxonomy()
+		ids := DescendantIds([]TagDAO{travel, flights, business, hotels, dinner}, travel.Id)
+		if len(ids) == 3 && ids[0] == flights.Id && ids[1] == hotels.Id && ids[2] == business.Id {
+			t.Logf("\t\tAll the descendants should be returned. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tAll the descendants should be returned:  \"%v\". %v", ids, BallotX)
+		}
+	}
+}
+
+func TestCreatesCycle(t *testing.T) {
+	t.Logf("Given a taxonomy of tags")
+	{
+		travel, flights, business, hotels, dinner := taxonomy()
+		tags := []TagDAO{travel, flights, business, hotels, dinner}
+		if CreatesCycle(tags, travel.Id, business.Id) && CreatesCycle(tags, travel.Id, travel.Id) {
+			t.Logf("\t\tA tag may not be moved under itself or its descendants. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tA tag may not be moved under itself or its descendants. %v", BallotX)
+		}
+		if !CreatesCycle(tags, business.Id, hotels.Id) && !CreatesCycle(tags, dinner.Id, travel.Id) {
+			t.Logf("\t\tA tag may be moved under any other tag. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tA tag may be moved under any other tag. %v", BallotX)
+		}
+	}
+}
+
+func TestValidateParent(t *testing.T) {
+	t.Logf("Given a taxonomy of tags")
+	{
+		travel, flights, business, hotels, dinner := taxonomy()
+		tags := []TagDAO{travel, flights, business, hotels, dinner}
+
+		if parentId, err := ValidateParent(tags, dinner.Id, hotels.Id.Hex()); err == nil && parentId == hotels.Id {
+			t.Logf("\t\tA tag may be moved under another branch. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tA tag may be moved under another branch:  %v. %v", err, BallotX)
+		}
+
+		for parentId, expected := range map[string]string{
+			"not-an-id":              "invalid parent id",
+			bson.NewObjectId().Hex(): "parent tag not found",
+			business.Id.Hex():        "a tag may not be moved under itself or one of its descendants",
+		} {
+			if _, err := ValidateParent(tags, travel.Id, parentId); err != nil && err.Error() == expected {
+				t.Logf("\t\tThe parent should be rejected with \"%s\". %v", expected, CheckMark)
+			} else {
+				t.Errorf("\t\tThe parent should be rejected with \"%s\":  %v. %v", expected, err, BallotX)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import "fmt"
+
+// The visibility of a tag decides who may see and change it within its organisation: a private tag is only visible to
+// its owner, an organisation tag may be read and written by every account of the organisation, a read-only tag may be
+// read by every account but only written by its owner.
+const (
+	VisibilityPrivate      = "private"
+	VisibilityOrganisation = "organisation"
+	VisibilityReadOnly     = "readOnly"
+)
+
+// the tags created before the visibility was introduced are shared with the organisation
+const DefaultVisibility = VisibilityOrganisation
+
+type UpdateVisibilityRequest struct {
+	Visibility string `json:"visibility" binding:"required"`
+}
+
+// ValidateVisibility checks the visibility is one of the supported values.
+func ValidateVisibility(visibility string) error {
+	switch visibility {
+	case VisibilityPrivate, VisibilityOrganisation, VisibilityReadOnly:
+		return nil
+	}
+	return fmt.Errorf("visibility must be one of %v, %v or %v", VisibilityPrivate, VisibilityOrganisation, VisibilityReadOnly)
+}
+
+// Scope returns the visibility of the tag, the default one when it has none.
+func (tag TagDAO) Scope() string {
+	if tag.Visibility == "" {
+		return DefaultVisibility
+	}
+	return tag.Visibility
+}
+
+// ReadableBy tells whether the account of the organisation may see the tag.
+func (tag TagDAO) ReadableBy(organisationId string, accountId string) bool {
+	if tag.OrganisationId != organisationId {
+		return false
+	}
+	return tag.Scope() != VisibilityPrivate || tag.AccountId == accountId
+}
+
+// WritableBy tells whether the account of the organisation may change or delete the tag.
+func (tag TagDAO) WritableBy(organisationId string, accountId string) bool {
+	if tag.OrganisationId != organisationId {
+		return false
+	}
+	return tag.Scope() == VisibilityOrganisation || tag.AccountId == accountId
+}
+
+// Readable returns the tags the account of the organisation may see.
+func Readable(tags []TagDAO, organisationId string, accountId string) []TagDAO {
+	readable := make([]TagDAO, 0)
+	for _, tag := range tags {
+		if tag.ReadableBy(organisationId, accountId) {
+			readable = append(readable, tag)
+		}
+	}
+	return readable
+}
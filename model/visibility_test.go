@@ -0,0 +1,56 @@
+package model
+
+import "testing"
+
+func TestTagAccess(t *testing.T) {
+	t.Logf("Given tags owned by an account of the organisation")
+	{
+		for _, c := range []struct {
+			visibility string
+			read       bool
+			write      bool
+		}{
+			{"", true, true},
+			{VisibilityOrganisation, true, true},
+			{VisibilityReadOnly, true, false},
+			{VisibilityPrivate, false, false},
+		} {
+			tag := TagDAO{AccountId: "owner", OrganisationId: "org", Visibility: c.visibility}
+			if tag.ReadableBy("org", "other") == c.read && tag.WritableBy("org", "other") == c.write {
+				t.Logf("\t\tA \"%s\" tag should be readable %v and writable %v by the other accounts. %v", c.visibility, c.read, c.write, CheckMark)
+			} else {
+				t.Errorf("\t\tA \"%s\" tag should be readable %v and writable %v by the other accounts. %v", c.visibility, c.read, c.write, BallotX)
+			}
+			if tag.ReadableBy("org", "owner") && tag.WritableBy("org", "owner") {
+				t.Logf("\t\tA \"%s\" tag should be readable and writable by its owner. %v", c.visibility, CheckMark)
+			} else {
+				t.Errorf("\t\tA \"%s\" tag should be readable and writable by its owner. %v", c.visibility, BallotX)
+			}
+			if !tag.ReadableBy("another org", "owner") && !tag.WritableBy("another org", "owner") {
+				t.Logf("\t\tA \"%s\" tag should not be accessible from another organisation. %v", c.visibility, CheckMark)
+			} else {
+				t.Errorf("\t\tA \"%s\" tag should not be accessible from another organisation. %v", c.visibility, BallotX)
+			}
+		}
+	}
+}
+
+func TestValidateVisibility(t *testing.T) {
+	t.Logf("Given visibilities")
+	{
+		for _, visibility := range []string{VisibilityPrivate, VisibilityOrganisation, VisibilityReadOnly} {
+			if err := ValidateVisibility(visibility); err == nil {
+				t.Logf("\t\t\"%s\" should be accepted. %v", visibility, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should be accepted: %v. %v", visibility, err, BallotX)
+			}
+		}
+		for _, visibility := range []string{"", "public", "Private"} {
+			if err := ValidateVisibility(visibility); err != nil {
+				t.Logf("\t\t\"%s\" should be rejected. %v", visibility, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should be rejected. %v", visibility, BallotX)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"time"
+)
+
+// Statuses of a webhook delivery. A delivery is dead once all its attempts have failed.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// for persistence, callback URL registered by an organisation. An empty list of events subscribes to all of them.
+type WebhookDAO struct {
+	Id             bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	OrganisationId string        `json:"organisationId" bson:"organisationId"`
+	Url            string        `json:"url" bson:"url"`
+	Secret         string        `json:"secret" bson:"secret"`
+	Events         []string      `json:"events,omitempty" bson:"events,omitempty"`
+	CreatedAt      time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+// The secret is generated when none is given.
+type CreateWebhookRequest struct {
+	Url    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// The secret is only returned when the webhook is created.
+type CreateWebhookResponse struct {
+	Id     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+type Webhook struct {
+	Id        string    `json:"id"`
+	Url       string    `json:"url"`
+	Events    []string  `json:"events,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type GetWebhooksResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// for persistence, delivery of an event to a webhook
+type DeliveryDAO struct {
+	Id             bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	WebhookId      bson.ObjectId `json:"webhookId" bson:"webhookId"`
+	OrganisationId string        `json:"organisationId" bson:"organisationId"`
+	Event          Event         `json:"event" bson:"event"`
+	Status         string        `json:"status" bson:"status"`
+	Attempts       int           `json:"attempts" bson:"attempts"`
+	NextAttemptAt  time.Time     `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	LastStatusCode int           `json:"lastStatusCode,omitempty" bson:"lastStatusCode,omitempty"`
+	LastError      string        `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	CreatedAt      time.Time     `json:"createdAt" bson:"createdAt"`
+	DeliveredAt    *time.Time    `json:"deliveredAt,omitempty" bson:"deliveredAt,omitempty"`
+}
+
+type Delivery struct {
+	Id             string     `json:"id"`
+	EventId        string     `json:"eventId"`
+	EventType      string     `json:"eventType"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	LastStatusCode int        `json:"lastStatusCode,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+}
+
+type GetDeliveriesResponse struct {
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// Subscribed checks whether the webhook subscribed to the given type of event.
+func (webhook WebhookDAO) Subscribed(eventType string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range webhook.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func ConvertWebhooks(webhooks []WebhookDAO) GetWebhooksResponse {
+	response := make([]Webhook, 0)
+	for _, webhook := range webhooks {
+		response = append(response, Webhook{Id: webhook.Id.Hex(), Url: webhook.Url, Events: webhook.Events, CreatedAt: webhook.CreatedAt})
+	}
+	return GetWebhooksResponse{Webhooks: response}
+}
+
+// Converts the deliveries, the next attempt is only given for the pending ones.
+func ConvertDeliveries(deliveries []DeliveryDAO) GetDeliveriesResponse {
+	response := make([]Delivery, 0)
+	for _, delivery := range deliveries {
+		entry := Delivery{Id: delivery.Id.Hex(), EventId: delivery.Event.Id, EventType: delivery.Event.Type, Status: delivery.Status, Attempts: delivery.Attempts,
+			LastStatusCode: delivery.LastStatusCode, LastError: delivery.LastError, CreatedAt: delivery.CreatedAt, DeliveredAt: delivery.DeliveredAt}
+		if delivery.Status == DeliveryPending {
+			next := delivery.NextAttemptAt
+			entry.NextAttemptAt = &next
+		}
+		response = append(response, entry)
+	}
+	return GetDeliveriesResponse{Deliveries: response}
+}
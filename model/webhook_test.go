@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"testing"
+	"time"
+)
+
+func TestWebhookSubscribed(t *testing.T) {
+	t.Logf("Given webhooks with and without an events filter")
+	{
+		all := WebhookDAO{}
+		created := WebhookDAO{Events: []string{TagCreated}}
+		if all.Subscribed(TagDeleted) && created.Subscribed(TagCreated) && !created.Subscribed(TagDeleted) {
+			t.Logf("\t\tOnly the filtered events should be subscribed. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tOnly the filtered events should be subscribed. %v", BallotX)
+		}
+	}
+}
+
+func TestConvertDeliveries(t *testing.T) {
+	t.Logf("Given a pending and a dead delivery")
+	{
+		now := time.Now()
+		deliveries := []DeliveryDAO{
+			{Id: bson.NewObjectId(), Event: Event{Id: "1", Type: TagCreated}, Status: DeliveryPending, NextAttemptAt: now},
+			{Id: bson.NewObjectId(), Event: Event{Id: "2", Type: TagDeleted}, Status: DeliveryDead, NextAttemptAt: now},
+		}
+		response := ConvertDeliveries(deliveries).Deliveries
+		if len(response) == 2 && response[0].NextAttemptAt != nil && response[1].NextAttemptAt == nil && response[1].EventType == TagDeleted {
+			t.Logf("\t\tOnly the pending delivery should have a next attempt. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tOnly the pending delivery should have a next attempt:  \"%v\". %v", response, BallotX)
+		}
+	}
+}
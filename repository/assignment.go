@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+)
+
+// AssignmentRepository interface for the tags applied to resources
+type AssignmentRepository interface {
+	FindAssignments(database string, collection string, query bson.M) ([]model.AssignmentDAO, error)
+	FindResources(database string, collection string, query bson.M, tagsQuery bson.M, limit int) ([]model.ResourceRef, error)
+	UsageByTag(database string, collection string, query bson.M) ([]model.TagUsage, error)
+	UsageByResourceType(database string, collection string, query bson.M) ([]model.ResourceTypeUsage, error)
+}
+
+// Field holding the tag ids of each resource once the assignments are grouped by resource.
+const ResourceTagsField = "tags"
+
+// Implementation of Find assignments from Mongo repository for given query
+func (repo *MongoRepository) FindAssignments(db string, collection string, query bson.M) ([]model.AssignmentDAO, error) {
+	var results []model.AssignmentDAO
+	err := repo.Session.DB(db).C(collection).Find(query).All(&results)
+	return results, classify(err)
+}
+
+// Implementation of Find resources from Mongo repository. The assignments matching the query are grouped by resource
+// and the resulting tag sets filtered with the tags query, which may also match the grouped "_id" to page through
+// the resources in order.
+func (repo *MongoRepository) FindResources(db string, collection string, query bson.M, tagsQuery bson.M, limit int) ([]model.ResourceRef, error) {
+	pipeline := []bson.M{
+		{"$match": query},
+		// bson.D keeps the _id fields ordered so that resources are compared and sorted consistently
+		{"$group": bson.M{
+			"_id":             bson.D{{Name: "resourceType", Value: "$resourceType"}, {Name: "resourceId", Value: "$resourceId"}},
+			ResourceTagsField: bson.M{"$addToSet": "$tagId"},
+		}},
+		{"$match": tagsQuery},
+		{"$sort": bson.M{"_id": 1}},
+		{"$limit": limit},
+	}
+	var groups []struct {
+		Id model.ResourceRef `bson:"_id"`
+	}
+	err := repo.Session.DB(db).C(collection).Pipe(pipeline).All(&groups)
+	results := make([]model.ResourceRef, 0, len(groups))
+	for _, group := range groups {
+		results = append(results, group.Id)
+	}
+	return results, classify(err)
+}
+
+// Implementation of Count the assignments of each tag from Mongo repository for given query, the most used tags first.
+func (repo *MongoRepository) UsageByTag(db string, collection string, query bson.M) ([]model.TagUsage, error) {
+	pipeline := []bson.M{
+		{"$match": query},
+		{"$group": bson.M{"_id": "$tagId", "count": bson.M{"$sum": 1}, "lastUsedAt": bson.M{"$max": "$createdAt"}}},
+		{"$sort": bson.D{{Name: "count", Value: -1}, {Name: "_id", Value: 1}}},
+	}
+	var results []model.TagUsage
+	err := repo.Session.DB(db).C(collection).Pipe(pipeline).All(&results)
+	return results, classify(err)
+}
+
+// Implementation of Count the assignments of each resource type from Mongo repository for given query, the most used
+// types first.
+func (repo *MongoRepository) UsageByResourceType(db string, collection string, query bson.M) ([]model.ResourceTypeUsage, error) {
+	pipeline := []bson.M{
+		{"$match": query},
+		{"$group": bson.M{"_id": "$resourceType", "count": bson.M{"$sum": 1}, "lastUsedAt": bson.M{"$max": "$createdAt"}}},
+		{"$sort": bson.D{{Name: "count", Value: -1}, {Name: "_id", Value: 1}}},
+	}
+	var results []model.ResourceTypeUsage
+	err := repo.Session.DB(db).C(collection).Pipe(pipeline).All(&results)
+	return results, classify(err)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/test"
+	"testing"
+	"time"
+)
+
+func TestMongoRepository_FindAssignments(t *testing.T) {
+	t.Logf("Given a tag has been assigned to a resource")
+	{
+		resourceId := bson.NewObjectId().Hex()
+		query := bson.M{"organisationId": test.OrgID1, "resourceType": "document", "resourceId": resourceId, "tagId": CreateTag(t)}
+		err := RepositoryUnderTest.Upsert("tags-db", "assignments", query, bson.M{"$setOnInsert": bson.M{}})
+		if err != nil {
+			t.Errorf("\t\tThe upsert should have been successful %v", test.BallotX)
+		}
+
+		t.Logf("\tWhen querying the assignments of the resource")
+		{
+			results, err := RepositoryUnderTest.FindAssignments("tags-db", "assignments", bson.M{"resourceId": resourceId})
+			if err == nil && len(results) == 1 {
+				t.Logf("\t\tThe find assignments should have returned the assignment %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find assignments should have returned the assignment %v %v", test.BallotX, results)
+			}
+		}
+
+		t.Logf("\tWhen removing the assignments of the resource")
+		{
+			err := RepositoryUnderTest.RemoveAll("tags-db", "assignments", bson.M{"resourceId": resourceId})
+			results, _ := RepositoryUnderTest.FindAssignments("tags-db", "assignments", bson.M{"resourceId": resourceId})
+			if err == nil && len(results) == 0 {
+				t.Logf("\t\tThe remove all should have been successful %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe remove all should have been successful %v", test.BallotX)
+			}
+		}
+	}
+}
+
+func TestMongoRepository_FindResources(t *testing.T) {
+	t.Logf("Given two resources tagged with the same tag")
+	{
+		resourceType := bson.NewObjectId().Hex()
+		tagId := CreateTag(t)
+		for _, resourceId := range []string{"b", "a"} {
+			query := bson.M{"organisationId": test.OrgID1, "resourceType": resourceType, "resourceId": resourceId, "tagId": tagId}
+			RepositoryUnderTest.Upsert("tags-db", "assignments", query, bson.M{"$setOnInsert": bson.M{}})
+		}
+
+		t.Logf("\tWhen querying the resources holding the tag")
+		{
+			tagsQuery := bson.M{ResourceTagsField: tagId}
+			results, err := RepositoryUnderTest.FindResources("tags-db", "assignments", bson.M{"resourceType": resourceType}, tagsQuery, 10)
+			if err == nil && len(results) == 2 && results[0].ResourceId == "a" && results[1].ResourceId == "b" {
+				t.Logf("\t\tThe resources should have been returned in order %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe resources should have been returned in order %v %v %v", test.BallotX, results, err)
+			}
+		}
+	}
+}
+
+func TestMongoRepository_UsageByTag(t *testing.T) {
+	t.Logf("Given a tag assigned to two resources")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		tagId := CreateTag(t)
+		for _, resourceId := range []string{"1", "2"} {
+			query := bson.M{"organisationId": organisationId, "resourceType": "document", "resourceId": resourceId, "tagId": tagId}
+			RepositoryUnderTest.Upsert("tags-db", "assignments", query, bson.M{"$setOnInsert": bson.M{"createdAt": time.Now()}})
+		}
+
+		t.Logf("\tWhen counting the assignments of the tags")
+		{
+			results, err := RepositoryUnderTest.UsageByTag("tags-db", "assignments", bson.M{"organisationId": organisationId})
+			if err == nil && len(results) == 1 && results[0].TagId == tagId && results[0].Count == 2 {
+				t.Logf("\t\tThe usage should have been counted %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe usage should have been counted %v %v", test.BallotX, results)
+			}
+		}
+	}
+}
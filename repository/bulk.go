@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// BulkRepository interface for the batch endpoints. The operations are sent unordered in a single bulk request, the
+// errors of the operations which failed are returned keyed by position while the others are applied. The updates and
+// removals only apply to the documents also matching the given query.
+type BulkRepository interface {
+	BulkInsert(database string, collection string, docs []interface{}) (map[int]error, error)
+	BulkUpdate(database string, collection string, query bson.M, ids []bson.ObjectId, updates []interface{}) (map[int]error, error)
+	BulkRemove(database string, collection string, query bson.M, ids []bson.ObjectId) (map[int]error, error)
+}
+
+// Implementation of Bulk insert from Mongo repository
+func (repo *MongoRepository) BulkInsert(db string, collection string, docs []interface{}) (map[int]error, error) {
+	bulk := repo.Session.DB(db).C(collection).Bulk()
+	bulk.Unordered()
+	bulk.Insert(docs...)
+	return runBulk(bulk)
+}
+
+// Implementation of Bulk update from Mongo repository, each update applies to the document with the id at the same
+// position.
+func (repo *MongoRepository) BulkUpdate(db string, collection string, query bson.M, ids []bson.ObjectId, updates []interface{}) (map[int]error, error) {
+	bulk := repo.Session.DB(db).C(collection).Bulk()
+	bulk.Unordered()
+	for i, id := range ids {
+		bulk.Update(withId(query, id), updates[i])
+	}
+	return runBulk(bulk)
+}
+
+// Implementation of Bulk remove from Mongo repository
+func (repo *MongoRepository) BulkRemove(db string, collection string, query bson.M, ids []bson.ObjectId) (map[int]error, error) {
+	bulk := repo.Session.DB(db).C(collection).Bulk()
+	bulk.Unordered()
+	for _, id := range ids {
+		bulk.Remove(withId(query, id))
+	}
+	return runBulk(bulk)
+}
+
+// Returns a copy of the query also matching the given id.
+func withId(query bson.M, id bson.ObjectId) bson.M {
+	selector := bson.M{"_id": id}
+	for field, value := range query {
+		selector[field] = value
+	}
+	return selector
+}
+
+// Runs the bulk and splits the errors of the individual operations from the failure of the whole bulk.
+func runBulk(bulk *mgo.Bulk) (map[int]error, error) {
+	failed := make(map[int]error)
+	_, err := bulk.Run()
+	bulkErr, ok := err.(*mgo.BulkError)
+	if !ok {
+		return failed, classify(err)
+	}
+	for _, errCase := range bulkErr.Cases() {
+		if errCase.Index < 0 {
+			return failed, classify(err)
+		}
+		failed[errCase.Index] = classify(errCase.Err)
+	}
+	return failed, nil
+}
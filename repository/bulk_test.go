@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"testing"
+)
+
+func TestMongoRepository_Bulk(t *testing.T) {
+	t.Logf("Given two tags inserted in bulk with the same id")
+	{
+		id := bson.NewObjectId()
+		docs := []interface{}{model.TagDAO{Id: id, Name: "Lunch"}, model.TagDAO{Id: id, Name: "Dinner"}, model.TagDAO{Id: bson.NewObjectId(), Name: "Brunch"}}
+		failed, err := RepositoryUnderTest.BulkInsert("tags-db", "bulk", docs)
+		if err == nil && len(failed) == 1 && errors.Is(failed[1], ErrConflict) {
+			t.Logf("\t\tOnly the duplicate should have failed %v", test.CheckMark)
+		} else {
+			t.Errorf("\t\tOnly the duplicate should have failed %v %v %v", test.BallotX, failed, err)
+		}
+
+		t.Logf("\tWhen updating the tag in bulk with a query it does not match")
+		{
+			failed, err := RepositoryUnderTest.BulkUpdate("tags-db", "bulk", bson.M{"organisationId": "another"}, []bson.ObjectId{id}, []interface{}{bson.M{"$set": bson.M{"name": "Supper"}}})
+			tag, _ := RepositoryUnderTest.Find("tags-db", "bulk", id)
+			if err == nil && len(failed) == 0 && tag.Name == "Lunch" {
+				t.Logf("\t\tThe tag should not have been updated %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag should not have been updated %v %v", test.BallotX, tag)
+			}
+		}
+
+		t.Logf("\tWhen updating the tag in bulk")
+		{
+			failed, err := RepositoryUnderTest.BulkUpdate("tags-db", "bulk", bson.M{}, []bson.ObjectId{id}, []interface{}{bson.M{"$set": bson.M{"name": "Supper"}}})
+			tag, _ := RepositoryUnderTest.Find("tags-db", "bulk", id)
+			if err == nil && len(failed) == 0 && tag.Name == "Supper" {
+				t.Logf("\t\tThe bulk update should have been successful %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe bulk update should have been successful %v %v", test.BallotX, tag)
+			}
+		}
+
+		t.Logf("\tWhen removing the tag in bulk")
+		{
+			failed, err := RepositoryUnderTest.BulkRemove("tags-db", "bulk", bson.M{}, []bson.ObjectId{id})
+			if _, errFind := RepositoryUnderTest.Find("tags-db", "bulk", id); err == nil && len(failed) == 0 && errFind != nil {
+				t.Logf("\t\tThe bulk remove should have been successful %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe bulk remove should have been successful %v", test.BallotX)
+			}
+		}
+	}
+}
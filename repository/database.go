@@ -41,8 +41,30 @@ type MongoRepository struct {
 type Repository interface {
 	Insert(database string, collection string, content interface{}) error
 	FindAll(database string, collection string, query bson.M) ([]model.TagDAO, error)
+	FindPage(database string, collection string, query bson.M, sort []string, limit int) ([]model.TagDAO, error)
+	Count(database string, collection string, query bson.M) (int, error)
 	Find(database string, collection string, oid bson.ObjectId) (model.TagDAO, error)
+	FindOne(database string, collection string, query bson.M) (model.TagDAO, error)
 	Delete(database string, collection string, oid bson.ObjectId) error
+	Update(database string, collection string, oid bson.ObjectId, update interface{}) error
+	UpdateAll(database string, collection string, query bson.M, update interface{}) error
+	UpdateOne(database string, collection string, query bson.M, update interface{}) error
+	Modify(database string, collection string, query bson.M, update interface{}) (model.TagDAO, error)
+	RemoveOne(database string, collection string, query bson.M) error
+	Upsert(database string, collection string, query bson.M, update interface{}) error
+	RemoveAll(database string, collection string, query bson.M) error
+	EnsureIndex(database string, collection string, index mgo.Index) error
+	AssignmentRepository
+	PaletteRepository
+	BulkRepository
+	HistoryRepository
+	OutboxRepository
+	WebhookRepository
+	KeyRepository
+	SchemaRepository
+	MembershipRepository
+	LeaseRepository
+	SequenceRepository
 }
 
 // NewRepository function to create an instance of Mongo repository
@@ -72,26 +94,91 @@ func NewRepository(config vault.Config) Repository {
 
 // Implementation of Insert into Mongo repository
 func (repo *MongoRepository) Insert(db string, collection string, body interface{}) error {
-	return repo.Session.DB(db).C(collection).Insert(&body)
+	return classify(repo.Session.DB(db).C(collection).Insert(&body))
 }
 
 // Implementation of Find all from  Mongo repository for given id
 func (repo *MongoRepository) FindAll(db string, collection string, query bson.M) ([]model.TagDAO, error) {
 	var results []model.TagDAO
 	err := repo.Session.DB(db).C(collection).Find(query).All(&results)
-	return results, err
+	return results, classify(err)
+}
+
+// Implementation of Find page, returns at most limit documents matching the query in the given sort order
+func (repo *MongoRepository) FindPage(db string, collection string, query bson.M, sort []string, limit int) ([]model.TagDAO, error) {
+	var results []model.TagDAO
+	err := repo.Session.DB(db).C(collection).Find(query).Sort(sort...).Limit(limit).All(&results)
+	return results, classify(err)
+}
+
+// Implementation of Count of the documents matching the query
+func (repo *MongoRepository) Count(db string, collection string, query bson.M) (int, error) {
+	count, err := repo.Session.DB(db).C(collection).Find(query).Count()
+	return count, classify(err)
 }
 
 // Implementation of Insert into Mongo repository
 func (repo *MongoRepository) Find(db string, collection string, oid bson.ObjectId) (model.TagDAO, error) {
 	var result model.TagDAO
 	err := repo.Session.DB(db).C(collection).FindId(oid).One(&result)
-	return result, err
+	return result, classify(err)
+}
+
+// Implementation of Find one document matching the query, ErrNotFound is returned when none matches
+func (repo *MongoRepository) FindOne(db string, collection string, query bson.M) (model.TagDAO, error) {
+	var result model.TagDAO
+	err := repo.Session.DB(db).C(collection).Find(query).One(&result)
+	return result, classify(err)
 }
 
 // Implementation of Delete
 func (repo *MongoRepository) Delete(db string, collection string, oid bson.ObjectId) error {
-	return repo.Session.DB(db).C(collection).RemoveId(oid)
+	return classify(repo.Session.DB(db).C(collection).RemoveId(oid))
+}
+
+// Implementation of Update
+func (repo *MongoRepository) Update(db string, collection string, oid bson.ObjectId, update interface{}) error {
+	return classify(repo.Session.DB(db).C(collection).UpdateId(oid, update))
+}
+
+// Implementation of Update all documents matching the query
+func (repo *MongoRepository) UpdateAll(db string, collection string, query bson.M, update interface{}) error {
+	_, err := repo.Session.DB(db).C(collection).UpdateAll(query, update)
+	return classify(err)
+}
+
+// Implementation of Update one document matching the query, ErrNotFound is returned when none matches
+func (repo *MongoRepository) UpdateOne(db string, collection string, query bson.M, update interface{}) error {
+	return classify(repo.Session.DB(db).C(collection).Update(query, update))
+}
+
+// Implementation of Modify, applies the update to the document matching the query and returns the document as updated
+func (repo *MongoRepository) Modify(db string, collection string, query bson.M, update interface{}) (model.TagDAO, error) {
+	var result model.TagDAO
+	_, err := repo.Session.DB(db).C(collection).Find(query).Apply(mgo.Change{Update: update, ReturnNew: true}, &result)
+	return result, classify(err)
+}
+
+// Implementation of Upsert, inserts the document when no document matches the query
+func (repo *MongoRepository) Upsert(db string, collection string, query bson.M, update interface{}) error {
+	_, err := repo.Session.DB(db).C(collection).Upsert(query, update)
+	return classify(err)
+}
+
+// Implementation of Remove all documents matching the query
+func (repo *MongoRepository) RemoveAll(db string, collection string, query bson.M) error {
+	_, err := repo.Session.DB(db).C(collection).RemoveAll(query)
+	return classify(err)
+}
+
+// Implementation of Remove one document matching the query, ErrNotFound is returned when none matches
+func (repo *MongoRepository) RemoveOne(db string, collection string, query bson.M) error {
+	return classify(repo.Session.DB(db).C(collection).Remove(query))
+}
+
+// Implementation of Ensure index, creates the index unless it already exists
+func (repo *MongoRepository) EnsureIndex(db string, collection string, index mgo.Index) error {
+	return classify(repo.Session.DB(db).C(collection).EnsureIndex(index))
 }
 
 // For `dev` and `prod` environment we enable TLS.
@@ -80,6 +80,27 @@ func TestMongoRepository_Find(t *testing.T) {
 	}
 }
 
+func TestMongoRepository_Update(t *testing.T) {
+	t.Logf("Given the tag service is up and running")
+	{
+		t.Logf("\tWhen Sending Update TagDAO request to endpoint:  \"%s\"", "\\tags\\id")
+		{
+			tagId := CreateTag(t)
+			err := RepositoryUnderTest.Update("tags-db", "tags", tagId, bson.M{"$set": bson.M{"name": "Dinner"}})
+			if err != nil {
+				t.Errorf("\t\tThe update should have been successful %v", test.BallotX)
+			}
+			result, _ := RepositoryUnderTest.Find("tags-db", "tags", tagId)
+			if result.Name == "Dinner" {
+				t.Logf("\t\tThe tag name should have been updated %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag name should have been updated %v", test.BallotX)
+			}
+		}
+
+	}
+}
+
 func TestNewRepository(t *testing.T) {
 	config := vault.Config{Address: "https://domain.com"}
 	os.Setenv(ENVIRONMENT, "dev")
@@ -99,3 +120,83 @@ func CreateTag(t *testing.T) bson.ObjectId {
 	}
 	return tagId
 }
+
+func TestMongoRepository_FindPage(t *testing.T) {
+	t.Logf("Given the tag service is up and running")
+	{
+		t.Logf("\tWhen Sending Find page TagDAO request to endpoint:  \"%s\"", "\\tags?limit=1")
+		{
+			CreateTag(t)
+			CreateTag(t)
+			results, err := RepositoryUnderTest.FindPage("tags-db", "tags", bson.M{}, []string{"-_id"}, 1)
+			if err == nil && len(results) == 1 {
+				t.Logf("\t\tThe find page should have returned one tag %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find page should have returned one tag %v", test.BallotX)
+			}
+			count, err := RepositoryUnderTest.Count("tags-db", "tags", bson.M{})
+			if err == nil && count >= 2 {
+				t.Logf("\t\tThe count should include all the tags %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe count should include all the tags %v", test.BallotX)
+			}
+		}
+	}
+}
+
+func TestMongoRepository_Modify(t *testing.T) {
+	t.Logf("Given a tag at version 1")
+	{
+		tagId := CreateTag(t)
+		RepositoryUnderTest.Update("tags-db", "tags", tagId, bson.M{"$set": bson.M{"version": 1}})
+
+		t.Logf("\tWhen modifying the tag with a stale version")
+		{
+			if _, err := RepositoryUnderTest.Modify("tags-db", "tags", bson.M{"_id": tagId, "version": 0}, bson.M{"$inc": bson.M{"version": 1}}); err == ErrNotFound {
+				t.Logf("\t\tThe modification should have returned not found %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe modification should have returned not found %v %v", test.BallotX, err)
+			}
+		}
+
+		t.Logf("\tWhen modifying the tag with the current version")
+		{
+			tag, err := RepositoryUnderTest.Modify("tags-db", "tags", bson.M{"_id": tagId, "version": 1}, bson.M{"$inc": bson.M{"version": 1}, "$set": bson.M{"name": "Supper"}})
+			if err == nil && tag.Id == tagId && tag.Version == 2 && tag.Name == "Supper" {
+				t.Logf("\t\tThe tag should have been returned as updated %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag should have been returned as updated %v %v %v", test.BallotX, tag, err)
+			}
+		}
+	}
+}
+
+func TestMongoRepository_UpdateOneAndRemoveOne(t *testing.T) {
+	t.Logf("Given a tag at version 1")
+	{
+		tagId := CreateTag(t)
+		RepositoryUnderTest.Update("tags-db", "tags", tagId, bson.M{"$set": bson.M{"version": 1}})
+
+		t.Logf("\tWhen updating and removing the tag with a stale version")
+		{
+			errUpdate := RepositoryUnderTest.UpdateOne("tags-db", "tags", bson.M{"_id": tagId, "version": 0}, bson.M{"$set": bson.M{"name": "Supper"}})
+			errRemove := RepositoryUnderTest.RemoveOne("tags-db", "tags", bson.M{"_id": tagId, "version": 0})
+			if errUpdate == ErrNotFound && errRemove == ErrNotFound {
+				t.Logf("\t\tThe update and remove should have returned not found %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe update and remove should have returned not found %v %v %v", test.BallotX, errUpdate, errRemove)
+			}
+		}
+
+		t.Logf("\tWhen updating and removing the tag with the current version")
+		{
+			errUpdate := RepositoryUnderTest.UpdateOne("tags-db", "tags", bson.M{"_id": tagId, "version": 1}, bson.M{"$inc": bson.M{"version": 1}})
+			errRemove := RepositoryUnderTest.RemoveOne("tags-db", "tags", bson.M{"_id": tagId, "version": 2})
+			if errUpdate == nil && errRemove == nil {
+				t.Logf("\t\tThe update and remove should have been successful %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe update and remove should have been successful %v %v %v", test.BallotX, errUpdate, errRemove)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"io"
+	"net"
+	"strings"
+)
+
+// Errors of the repository, the errors of the driver are translated to them so that the callers need not know about
+// the database. The original message is kept, the errors are tested with errors.Is.
+var (
+	// ErrNotFound is returned when no document matches
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when a write would break a unique index
+	ErrConflict = errors.New("conflict")
+
+	// ErrUnavailable is returned when the database cannot be reached, the operation may succeed when retried
+	ErrUnavailable = errors.New("database unavailable")
+
+	// ErrInvalidID is returned for an id which is not a valid object id
+	ErrInvalidID = errors.New("invalid id")
+)
+
+// error codes of the server telling it cannot serve the operation for now: host unreachable or not found, network
+// timeout, shutdown in progress, interrupted at shutdown or by a replica set change, and primary stepped down or lost
+var unavailableCodes = map[int]bool{6: true, 7: true, 89: true, 91: true, 11600: true, 11602: true, 189: true,
+	10107: true, 13435: true, 13436: true}
+
+// messages of the driver telling the servers cannot be reached
+var unavailableMessages = []string{"no reachable servers", "Closed explicitly", "not master", "node is recovering"}
+
+// ParseID returns the object id of the given hex representation, ErrInvalidID when it is not one.
+func ParseID(hex string) (bson.ObjectId, error) {
+	if !bson.IsObjectIdHex(hex) {
+		return "", fmt.Errorf("%w: %v", ErrInvalidID, hex)
+	}
+	return bson.ObjectIdHex(hex), nil
+}
+
+// Translates the error of the driver to the error of the repository.
+func classify(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case err == mgo.ErrNotFound:
+		return ErrNotFound
+	case mgo.IsDup(err):
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	case unavailable(err):
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return err
+}
+
+// Tells whether the error is caused by the database being unreachable or unable to serve the operation for now.
+func unavailable(err error) bool {
+	if err == io.EOF {
+		return true
+	}
+	switch e := err.(type) {
+	case net.Error:
+		return true
+	case *mgo.QueryError:
+		return unavailableCodes[e.Code]
+	case *mgo.LastError:
+		return unavailableCodes[e.Code]
+	}
+	for _, message := range unavailableMessages {
+		if strings.Contains(err.Error(), message) {
+			return true
+		}
+	}
+	return false
+}
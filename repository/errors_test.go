@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"io"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	t.Logf("Given the errors of the driver")
+	{
+		cases := []struct {
+			err      error
+			expected error
+		}{
+			{mgo.ErrNotFound, ErrNotFound},
+			{&mgo.LastError{Code: 11000, Err: "E11000 duplicate key error"}, ErrConflict},
+			{&mgo.QueryError{Code: 91, Message: "shutdown in progress"}, ErrUnavailable},
+			{errors.New("no reachable servers"), ErrUnavailable},
+			{io.EOF, ErrUnavailable},
+		}
+		t.Logf("\tWhen translating them to the errors of the repository")
+		{
+			for _, c := range cases {
+				if err := classify(c.err); errors.Is(err, c.expected) {
+					t.Logf("\t\t\"%v\" should be %v %v", c.err, c.expected, test.CheckMark)
+				} else {
+					t.Errorf("\t\t\"%v\" should be %v %v %v", c.err, c.expected, test.BallotX, err)
+				}
+			}
+			other := errors.New("unexpected")
+			if classify(other) == other && classify(nil) == nil {
+				t.Logf("\t\tThe other errors should be kept %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe other errors should be kept %v", test.BallotX)
+			}
+		}
+	}
+}
+
+func TestParseID(t *testing.T) {
+	t.Logf("Given an id given by a client")
+	{
+		t.Logf("\tWhen parsing it")
+		{
+			oid := bson.NewObjectId()
+			if parsed, err := ParseID(oid.Hex()); err == nil && parsed == oid {
+				t.Logf("\t\tA valid id should be parsed %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tA valid id should be parsed %v %v", test.BallotX, err)
+			}
+			if _, err := ParseID("not-an-id"); errors.Is(err, ErrInvalidID) {
+				t.Logf("\t\tA malformed id should be rejected %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tA malformed id should be rejected %v %v", test.BallotX, err)
+			}
+		}
+	}
+}
+
+func TestRepository_Errors(t *testing.T) {
+	t.Logf("Given a tag in the database")
+	{
+		tag := model.TagDAO{Id: bson.NewObjectId(), Name: "Lunch"}
+		RepositoryUnderTest.Insert("tags-db", "errors", tag)
+
+		t.Logf("\tWhen reading a missing tag and inserting the tag again")
+		{
+			if _, err := RepositoryUnderTest.Find("tags-db", "errors", bson.NewObjectId()); err == ErrNotFound {
+				t.Logf("\t\tThe missing tag should not be found %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe missing tag should not be found %v %v", test.BallotX, err)
+			}
+			if err := RepositoryUnderTest.Insert("tags-db", "errors", tag); errors.Is(err, ErrConflict) {
+				t.Logf("\t\tThe duplicate should be a conflict %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe duplicate should be a conflict %v %v", test.BallotX, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+)
+
+// HistoryRepository interface for the audit records of the changes made to the tags
+type HistoryRepository interface {
+	FindHistory(database string, collection string, query bson.M, limit int) ([]model.HistoryDAO, error)
+}
+
+// Implementation of Find history from Mongo repository for given query, the most recent records first
+func (repo *MongoRepository) FindHistory(db string, collection string, query bson.M, limit int) ([]model.HistoryDAO, error) {
+	var results []model.HistoryDAO
+	err := repo.Session.DB(db).C(collection).Find(query).Sort("-_id").Limit(limit).All(&results)
+	return results, classify(err)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"testing"
+)
+
+func TestMongoRepository_FindHistory(t *testing.T) {
+	t.Logf("Given a tag created then updated")
+	{
+		tag := model.TagDAO{Id: bson.NewObjectId(), OrganisationId: bson.NewObjectId().Hex(), Name: "Dinner"}
+		updated := tag
+		updated.Name = "Supper"
+		RepositoryUnderTest.Insert("tags-db", "history", model.NewHistory(AccountId, model.OperationCreate, nil, &tag))
+		RepositoryUnderTest.Insert("tags-db", "history", model.NewHistory(AccountId, model.OperationUpdate, &tag, &updated))
+
+		t.Logf("\tWhen finding the history of the tag")
+		{
+			results, err := RepositoryUnderTest.FindHistory("tags-db", "history", bson.M{"tagId": tag.Id}, 1)
+			if err == nil && len(results) == 1 && results[0].Operation == model.OperationUpdate {
+				t.Logf("\t\tThe find history should have returned the most recent record %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find history should have returned the most recent record %v %v", test.BallotX, results)
+			}
+		}
+	}
+}
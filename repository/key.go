@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+)
+
+// KeyRepository interface for the key definitions of the key/value tags
+type KeyRepository interface {
+	FindKeys(database string, collection string, query bson.M) ([]model.KeyDAO, error)
+}
+
+// Implementation of Find keys from Mongo repository for given query
+func (repo *MongoRepository) FindKeys(db string, collection string, query bson.M) ([]model.KeyDAO, error) {
+	var results []model.KeyDAO
+	err := repo.Session.DB(db).C(collection).Find(query).Sort("key").All(&results)
+	return results, classify(err)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"testing"
+)
+
+func TestMongoRepository_FindKeys(t *testing.T) {
+	t.Logf("Given two keys of an organisation")
+	{
+		orgId := bson.NewObjectId().Hex()
+		RepositoryUnderTest.Insert("tags-db", "keys", model.KeyDAO{Id: bson.NewObjectId(), OrganisationId: orgId, Key: "team", Type: model.KeyFreeForm})
+		RepositoryUnderTest.Insert("tags-db", "keys", model.KeyDAO{Id: bson.NewObjectId(), OrganisationId: orgId, Key: "env", Type: model.KeyEnum, Values: []string{"prod"}})
+
+		t.Logf("\tWhen finding the keys of the organisation")
+		{
+			results, err := RepositoryUnderTest.FindKeys("tags-db", "keys", bson.M{"organisationId": orgId})
+			if err == nil && len(results) == 2 && results[0].Key == "env" && results[0].Values[0] == "prod" {
+				t.Logf("\t\tThe find keys should have returned the keys sorted by key %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find keys should have returned the keys sorted by key %v %v", test.BallotX, results)
+			}
+		}
+	}
+}
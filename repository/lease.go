@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"time"
+)
+
+// LeaseRepository interface for the leases making sure a background job runs on a single instance of the service at a
+// time
+type LeaseRepository interface {
+	AcquireLease(database string, collection string, name string, owner string, duration time.Duration) (bool, error)
+	ReleaseLease(database string, collection string, name string, owner string) error
+}
+
+// Implementation of Acquire lease, takes or renews the lease for the owner unless another owner holds it and it has not
+// expired yet. Returns whether the owner holds the lease.
+func (repo *MongoRepository) AcquireLease(db string, collection string, name string, owner string, duration time.Duration) (bool, error) {
+	now := time.Now()
+	query := bson.M{"_id": name, "$or": []bson.M{{"owner": owner}, {"expiresAt": bson.M{"$lt": now}}}}
+	change := mgo.Change{Update: bson.M{"$set": bson.M{"owner": owner, "expiresAt": now.Add(duration)}}, Upsert: true}
+	_, err := repo.Session.DB(db).C(collection).Find(query).Apply(change, nil)
+	// the lease exists but is held by another owner
+	if mgo.IsDup(err) {
+		return false, nil
+	}
+	return err == nil, classify(err)
+}
+
+// Implementation of Release lease, the lease is left alone when the owner no longer holds it
+func (repo *MongoRepository) ReleaseLease(db string, collection string, name string, owner string) error {
+	err := repo.Session.DB(db).C(collection).Remove(bson.M{"_id": name, "owner": owner})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return classify(err)
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/test"
+	"testing"
+	"time"
+)
+
+func TestMongoRepository_AcquireLease(t *testing.T) {
+	t.Logf("Given a lease acquired by an instance")
+	{
+		name := bson.NewObjectId().Hex()
+		acquired, err := RepositoryUnderTest.AcquireLease("tags-db", "leases", name, "a", time.Minute)
+		if err == nil && acquired {
+			t.Logf("\t\tThe lease should have been acquired %v", test.CheckMark)
+		} else {
+			t.Errorf("\t\tThe lease should have been acquired %v %v", test.BallotX, err)
+		}
+
+		t.Logf("\tWhen another instance acquires it")
+		{
+			acquired, err := RepositoryUnderTest.AcquireLease("tags-db", "leases", name, "b", time.Minute)
+			if err == nil && !acquired {
+				t.Logf("\t\tThe lease should be held by the first instance %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe lease should be held by the first instance %v %v", test.BallotX, err)
+			}
+
+			acquired, err = RepositoryUnderTest.AcquireLease("tags-db", "leases", name, "a", time.Minute)
+			if err == nil && acquired {
+				t.Logf("\t\tThe first instance should renew the lease %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe first instance should renew the lease %v %v", test.BallotX, err)
+			}
+		}
+
+		t.Logf("\tWhen the lease has expired or has been released")
+		{
+			RepositoryUnderTest.AcquireLease("tags-db", "leases", name, "a", -time.Minute)
+			acquired, err := RepositoryUnderTest.AcquireLease("tags-db", "leases", name, "b", time.Minute)
+			if err == nil && acquired {
+				t.Logf("\t\tThe expired lease should be taken over %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe expired lease should be taken over %v %v", test.BallotX, err)
+			}
+
+			test.Ok(RepositoryUnderTest.ReleaseLease("tags-db", "leases", name, "a"), t)
+			test.Ok(RepositoryUnderTest.ReleaseLease("tags-db", "leases", name, "b"), t)
+			acquired, err = RepositoryUnderTest.AcquireLease("tags-db", "leases", name, "a", time.Minute)
+			if err == nil && acquired {
+				t.Logf("\t\tThe released lease should be acquired again %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe released lease should be acquired again %v %v", test.BallotX, err)
+			}
+		}
+	}
+}
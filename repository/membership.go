@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+)
+
+// MembershipRepository interface for the roles of the accounts within their organisation
+type MembershipRepository interface {
+	FindMembership(database string, collection string, organisationId string, accountId string) (model.MembershipDAO, error)
+	FindMemberships(database string, collection string, organisationId string) ([]model.MembershipDAO, error)
+}
+
+// Implementation of Find membership from Mongo repository for given account, ErrNotFound is returned when the
+// account has no role within the organisation.
+func (repo *MongoRepository) FindMembership(db string, collection string, organisationId string, accountId string) (model.MembershipDAO, error) {
+	var result model.MembershipDAO
+	err := repo.Session.DB(db).C(collection).Find(bson.M{"organisationId": organisationId, "accountId": accountId}).One(&result)
+	return result, classify(err)
+}
+
+// Implementation of Find memberships from Mongo repository for given organisation, sorted by account
+func (repo *MongoRepository) FindMemberships(db string, collection string, organisationId string) ([]model.MembershipDAO, error) {
+	var results []model.MembershipDAO
+	err := repo.Session.DB(db).C(collection).Find(bson.M{"organisationId": organisationId}).Sort("accountId").All(&results)
+	return results, classify(err)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/test"
+	"testing"
+)
+
+func TestMongoRepository_FindMembership(t *testing.T) {
+	t.Logf("Given an organisation without members")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		if _, err := RepositoryUnderTest.FindMembership("tags-db", "memberships", organisationId, "account"); err == ErrNotFound {
+			t.Logf("\t\tThe find membership should have returned not found %v", test.CheckMark)
+		} else {
+			t.Errorf("\t\tThe find membership should have returned not found %v %v", test.BallotX, err)
+		}
+
+		t.Logf("\tWhen giving roles to two accounts")
+		{
+			RepositoryUnderTest.Upsert("tags-db", "memberships", bson.M{"organisationId": organisationId, "accountId": "b"}, bson.M{"$set": bson.M{"role": "viewer"}})
+			RepositoryUnderTest.Upsert("tags-db", "memberships", bson.M{"organisationId": organisationId, "accountId": "a"}, bson.M{"$set": bson.M{"role": "admin"}})
+			result, err := RepositoryUnderTest.FindMembership("tags-db", "memberships", organisationId, "b")
+			if err == nil && result.Role == "viewer" {
+				t.Logf("\t\tThe find membership should have returned the role %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find membership should have returned the role %v %v", test.BallotX, result)
+			}
+			results, err := RepositoryUnderTest.FindMemberships("tags-db", "memberships", organisationId)
+			if err == nil && len(results) == 2 && results[0].AccountId == "a" && results[1].AccountId == "b" {
+				t.Logf("\t\tThe find memberships should have returned the accounts in order %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find memberships should have returned the accounts in order %v %v", test.BallotX, results)
+			}
+		}
+	}
+}
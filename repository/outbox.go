@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+)
+
+// OutboxRepository interface for the events waiting to be published
+type OutboxRepository interface {
+	FindEvents(database string, collection string, query bson.M, limit int) ([]model.EventDAO, error)
+	FindPendingTags(database string, collection string, query bson.M, limit int) ([]model.PendingTagDAO, error)
+}
+
+// Implementation of Find events from Mongo repository for given query, in the order they were written
+func (repo *MongoRepository) FindEvents(db string, collection string, query bson.M, limit int) ([]model.EventDAO, error) {
+	var results []model.EventDAO
+	err := repo.Session.DB(db).C(collection).Find(query).Sort("_id").Limit(limit).All(&results)
+	return results, classify(err)
+}
+
+// Implementation of Find pending tags from Mongo repository for given query, the tags holding the oldest events first
+func (repo *MongoRepository) FindPendingTags(db string, collection string, query bson.M, limit int) ([]model.PendingTagDAO, error) {
+	var results []model.PendingTagDAO
+	err := repo.Session.DB(db).C(collection).Find(query).Sort("pendingEvents._id").Limit(limit).All(&results)
+	return results, classify(err)
+}
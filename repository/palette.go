@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+)
+
+// PaletteRepository interface for the colours defined by each organisation
+type PaletteRepository interface {
+	FindPalette(database string, collection string, organisationId string) (model.PaletteDAO, error)
+}
+
+// Implementation of Find palette from Mongo repository for given organisation, ErrNotFound is returned when the
+// organisation has not defined its palette.
+func (repo *MongoRepository) FindPalette(db string, collection string, organisationId string) (model.PaletteDAO, error) {
+	var result model.PaletteDAO
+	err := repo.Session.DB(db).C(collection).Find(bson.M{"organisationId": organisationId}).One(&result)
+	return result, classify(err)
+}
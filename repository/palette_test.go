@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"testing"
+)
+
+func TestMongoRepository_FindPalette(t *testing.T) {
+	t.Logf("Given an organisation without palette")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		if _, err := RepositoryUnderTest.FindPalette("tags-db", "palettes", organisationId); err == ErrNotFound {
+			t.Logf("\t\tThe find palette should have returned not found %v", test.CheckMark)
+		} else {
+			t.Errorf("\t\tThe find palette should have returned not found %v %v", test.BallotX, err)
+		}
+
+		t.Logf("\tWhen storing the palette of the organisation")
+		{
+			colours := []model.PaletteColour{{Name: "Brand", Hex: "#123456"}}
+			RepositoryUnderTest.Upsert("tags-db", "palettes", bson.M{"organisationId": organisationId}, bson.M{"$set": bson.M{"colours": colours}})
+			result, err := RepositoryUnderTest.FindPalette("tags-db", "palettes", organisationId)
+			if err == nil && len(result.Colours) == 1 && result.Colours[0] == colours[0] {
+				t.Logf("\t\tThe find palette should have returned the colours %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find palette should have returned the colours %v %v", test.BallotX, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+)
+
+// SchemaRepository interface for the attribute schema defined by each organisation
+type SchemaRepository interface {
+	FindSchema(database string, collection string, organisationId string) (model.SchemaDAO, error)
+}
+
+// Implementation of Find schema from Mongo repository for given organisation, ErrNotFound is returned when the
+// organisation has not defined its schema.
+func (repo *MongoRepository) FindSchema(db string, collection string, organisationId string) (model.SchemaDAO, error) {
+	var result model.SchemaDAO
+	err := repo.Session.DB(db).C(collection).Find(bson.M{"organisationId": organisationId}).One(&result)
+	return result, classify(err)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"testing"
+)
+
+func TestMongoRepository_FindSchema(t *testing.T) {
+	t.Logf("Given an organisation without schema")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		if _, err := RepositoryUnderTest.FindSchema("tags-db", "schemas", organisationId); err == ErrNotFound {
+			t.Logf("\t\tThe find schema should have returned not found %v", test.CheckMark)
+		} else {
+			t.Errorf("\t\tThe find schema should have returned not found %v %v", test.BallotX, err)
+		}
+
+		t.Logf("\tWhen storing the schema of the organisation")
+		{
+			fields := map[string]model.AttributeField{"owner": {Type: model.FieldString, Required: true, MaxLength: 20}}
+			RepositoryUnderTest.Upsert("tags-db", "schemas", bson.M{"organisationId": organisationId}, bson.M{"$set": bson.M{"fields": fields}})
+			result, err := RepositoryUnderTest.FindSchema("tags-db", "schemas", organisationId)
+			if err == nil && len(result.Fields) == 1 && result.Fields["owner"] == fields["owner"] {
+				t.Logf("\t\tThe find schema should have returned the fields %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find schema should have returned the fields %v %v", test.BallotX, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"time"
+)
+
+// SequenceRepository interface for the counters stamping the writes of the tags of each organisation
+type SequenceRepository interface {
+	ReserveSequence(database string, collection string, organisationId string, reservation bson.ObjectId) (int64, error)
+	CompleteSequence(database string, collection string, organisationId string, reservation bson.ObjectId, expiredAt time.Time) error
+	FindSequence(database string, collection string, organisationId string) (model.SequenceDAO, error)
+}
+
+// Implementation of Reserve sequence, increments the counter of the organisation and records the reservation as in
+// flight until it is completed. Returns the sequence reserved.
+func (repo *MongoRepository) ReserveSequence(db string, collection string, organisationId string, reservation bson.ObjectId) (int64, error) {
+	c := repo.Session.DB(db).C(collection)
+	var current model.SequenceDAO
+	if err := c.Find(bson.M{"organisationId": organisationId}).One(&current); err != nil && err != mgo.ErrNotFound {
+		return 0, classify(err)
+	}
+	// the counter only grows, so the sequence read above is below the one reserved
+	inFlight := model.ReservationDAO{Id: reservation, Floor: current.Sequence, StartedAt: time.Now()}
+	change := mgo.Change{Update: bson.M{"$inc": bson.M{"sequence": 1}, "$push": bson.M{"inFlight": inFlight}}, Upsert: true, ReturnNew: true}
+	var reserved model.SequenceDAO
+	_, err := c.Find(bson.M{"organisationId": organisationId}).Apply(change, &reserved)
+	// the counter of the organisation has been created concurrently
+	if mgo.IsDup(err) {
+		_, err = c.Find(bson.M{"organisationId": organisationId}).Apply(change, &reserved)
+	}
+	return reserved.Sequence, classify(err)
+}
+
+// Implementation of Complete sequence, removes the reservation from the writes in flight along with the reservations
+// started before expiredAt, whose writes have failed.
+func (repo *MongoRepository) CompleteSequence(db string, collection string, organisationId string, reservation bson.ObjectId, expiredAt time.Time) error {
+	completed := bson.M{"$or": []bson.M{{"_id": reservation}, {"startedAt": bson.M{"$lt": expiredAt}}}}
+	err := repo.Session.DB(db).C(collection).Update(bson.M{"organisationId": organisationId}, bson.M{"$pull": bson.M{"inFlight": completed}})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return classify(err)
+}
+
+// Implementation of Find sequence from Mongo repository for given organisation, ErrNotFound is returned when the
+// organisation has never written a tag.
+func (repo *MongoRepository) FindSequence(db string, collection string, organisationId string) (model.SequenceDAO, error) {
+	var result model.SequenceDAO
+	err := repo.Session.DB(db).C(collection).Find(bson.M{"organisationId": organisationId}).One(&result)
+	return result, classify(err)
+}
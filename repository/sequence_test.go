@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/test"
+	"testing"
+	"time"
+)
+
+func TestMongoRepository_ReserveSequence(t *testing.T) {
+	t.Logf("Given two writes of an organisation in flight")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		first, second := bson.NewObjectId(), bson.NewObjectId()
+		firstSequence, err := RepositoryUnderTest.ReserveSequence("tags-db", "sequences", organisationId, first)
+		test.Ok(err, t)
+		secondSequence, err := RepositoryUnderTest.ReserveSequence("tags-db", "sequences", organisationId, second)
+		test.Ok(err, t)
+		if firstSequence == 1 && secondSequence == 2 {
+			t.Logf("\t\tThe sequences should follow each other %v", test.CheckMark)
+		} else {
+			t.Errorf("\t\tThe sequences should follow each other %v %d %d", test.BallotX, firstSequence, secondSequence)
+		}
+
+		sequence, err := RepositoryUnderTest.FindSequence("tags-db", "sequences", organisationId)
+		if err == nil && len(sequence.InFlight) == 2 && sequence.Watermark(time.Now().Add(-time.Minute)) == 0 {
+			t.Logf("\t\tNo write should have completed %v", test.CheckMark)
+		} else {
+			t.Errorf("\t\tNo write should have completed %v %v %v", test.BallotX, sequence, err)
+		}
+
+		t.Logf("\tWhen the writes complete")
+		{
+			test.Ok(RepositoryUnderTest.CompleteSequence("tags-db", "sequences", organisationId, first, time.Now().Add(-time.Minute)), t)
+			sequence, err := RepositoryUnderTest.FindSequence("tags-db", "sequences", organisationId)
+			if err == nil && len(sequence.InFlight) == 1 && sequence.Watermark(time.Now().Add(-time.Minute)) == firstSequence {
+				t.Logf("\t\tThe watermark should stop below the write still in flight %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe watermark should stop below the write still in flight %v %v %v", test.BallotX, sequence, err)
+			}
+
+			test.Ok(RepositoryUnderTest.CompleteSequence("tags-db", "sequences", organisationId, second, time.Now().Add(-time.Minute)), t)
+			sequence, err = RepositoryUnderTest.FindSequence("tags-db", "sequences", organisationId)
+			if err == nil && len(sequence.InFlight) == 0 && sequence.Watermark(time.Now().Add(-time.Minute)) == secondSequence {
+				t.Logf("\t\tThe watermark should reach the last sequence %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe watermark should reach the last sequence %v %v %v", test.BallotX, sequence, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package repository
+
+import (
+	"errors"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"strings"
+	"time"
+)
+
+// OrganisationField holds the organisation owning a document, in every collection
+const OrganisationField = "organisationId"
+
+var (
+	// ErrNoTenant is returned when the tenant has no organisation
+	ErrNoTenant = errors.New("no organisation given")
+
+	// ErrOtherTenant is returned when a query or a document names another organisation than the one of the tenant
+	ErrOtherTenant = errors.New("the data belongs to another organisation")
+)
+
+// Tenant of a request: the organisation owning the data and the account acting within it
+type Tenant struct {
+	OrganisationId string
+	AccountId      string
+}
+
+// TenantRepository interface, bound to a tenant. The organisation of the tenant is added to the query of every read
+// and write, so that the documents of the other organisations cannot be reached, and the documents written must
+// belong to it.
+type TenantRepository interface {
+	Tenant() Tenant
+	Insert(database string, collection string, content interface{}) error
+	FindAll(database string, collection string, query bson.M) ([]model.TagDAO, error)
+	FindPage(database string, collection string, query bson.M, sort []string, limit int) ([]model.TagDAO, error)
+	Count(database string, collection string, query bson.M) (int, error)
+	Find(database string, collection string, oid bson.ObjectId) (model.TagDAO, error)
+	Update(database string, collection string, oid bson.ObjectId, update interface{}) error
+	UpdateAll(database string, collection string, query bson.M, update interface{}) error
+	UpdateOne(database string, collection string, query bson.M, update interface{}) error
+	Modify(database string, collection string, query bson.M, update interface{}) (model.TagDAO, error)
+	RemoveOne(database string, collection string, query bson.M) error
+	Upsert(database string, collection string, query bson.M, update interface{}) error
+	RemoveAll(database string, collection string, query bson.M) error
+	BulkInsert(database string, collection string, docs []interface{}) (map[int]error, error)
+	BulkUpdate(database string, collection string, query bson.M, ids []bson.ObjectId, updates []interface{}) (map[int]error, error)
+	FindAssignments(database string, collection string, query bson.M) ([]model.AssignmentDAO, error)
+	FindResources(database string, collection string, query bson.M, tagsQuery bson.M, limit int) ([]model.ResourceRef, error)
+	UsageByTag(database string, collection string, query bson.M) ([]model.TagUsage, error)
+	UsageByResourceType(database string, collection string, query bson.M) ([]model.ResourceTypeUsage, error)
+	FindHistory(database string, collection string, query bson.M, limit int) ([]model.HistoryDAO, error)
+	FindEvents(database string, collection string, query bson.M, limit int) ([]model.EventDAO, error)
+	FindWebhooks(database string, collection string, query bson.M) ([]model.WebhookDAO, error)
+	FindDeliveries(database string, collection string, query bson.M, sort string, limit int) ([]model.DeliveryDAO, error)
+	FindKeys(database string, collection string, query bson.M) ([]model.KeyDAO, error)
+	FindPalette(database string, collection string) (model.PaletteDAO, error)
+	FindSchema(database string, collection string) (model.SchemaDAO, error)
+	FindMembership(database string, collection string) (model.MembershipDAO, error)
+	FindMemberships(database string, collection string) ([]model.MembershipDAO, error)
+	ReserveSequence(database string, collection string, reservation bson.ObjectId) (int64, error)
+	CompleteSequence(database string, collection string, reservation bson.ObjectId, expiredAt time.Time) error
+	FindSequence(database string, collection string) (model.SequenceDAO, error)
+}
+
+// tenantRepository scopes the operations of the underlying repository to its tenant
+type tenantRepository struct {
+	repo   Repository
+	tenant Tenant
+}
+
+// NewTenantRepository function to bind the repository to the given tenant
+func NewTenantRepository(repo Repository, tenant Tenant) TenantRepository {
+	return &tenantRepository{repo, tenant}
+}
+
+func (scoped *tenantRepository) Tenant() Tenant {
+	return scoped.tenant
+}
+
+func (scoped *tenantRepository) Insert(db string, collection string, content interface{}) error {
+	if err := scoped.owns(content); err != nil {
+		return err
+	}
+	return scoped.repo.Insert(db, collection, content)
+}
+
+func (scoped *tenantRepository) FindAll(db string, collection string, query bson.M) ([]model.TagDAO, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindAll(db, collection, query)
+}
+
+func (scoped *tenantRepository) FindPage(db string, collection string, query bson.M, sort []string, limit int) ([]model.TagDAO, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindPage(db, collection, query, sort, limit)
+}
+
+func (scoped *tenantRepository) Count(db string, collection string, query bson.M) (int, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return 0, err
+	}
+	return scoped.repo.Count(db, collection, query)
+}
+
+// Find returns ErrNotFound for the documents of the other organisations, as for the missing ones
+func (scoped *tenantRepository) Find(db string, collection string, oid bson.ObjectId) (model.TagDAO, error) {
+	query, err := scoped.scope(bson.M{"_id": oid})
+	if err != nil {
+		return model.TagDAO{}, err
+	}
+	return scoped.repo.FindOne(db, collection, query)
+}
+
+// Update returns ErrNotFound for the documents of the other organisations, as for the missing ones
+func (scoped *tenantRepository) Update(db string, collection string, oid bson.ObjectId, update interface{}) error {
+	return scoped.UpdateOne(db, collection, bson.M{"_id": oid}, update)
+}
+
+func (scoped *tenantRepository) UpdateAll(db string, collection string, query bson.M, update interface{}) error {
+	query, err := scoped.scopeUpdate(query, update)
+	if err != nil {
+		return err
+	}
+	return scoped.repo.UpdateAll(db, collection, query, update)
+}
+
+func (scoped *tenantRepository) UpdateOne(db string, collection string, query bson.M, update interface{}) error {
+	query, err := scoped.scopeUpdate(query, update)
+	if err != nil {
+		return err
+	}
+	return scoped.repo.UpdateOne(db, collection, query, update)
+}
+
+func (scoped *tenantRepository) Modify(db string, collection string, query bson.M, update interface{}) (model.TagDAO, error) {
+	query, err := scoped.scopeUpdate(query, update)
+	if err != nil {
+		return model.TagDAO{}, err
+	}
+	return scoped.repo.Modify(db, collection, query, update)
+}
+
+func (scoped *tenantRepository) RemoveOne(db string, collection string, query bson.M) error {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return err
+	}
+	return scoped.repo.RemoveOne(db, collection, query)
+}
+
+// Upsert inserts the document within the organisation of the tenant, which is part of the query
+func (scoped *tenantRepository) Upsert(db string, collection string, query bson.M, update interface{}) error {
+	query, err := scoped.scopeUpdate(query, update)
+	if err != nil {
+		return err
+	}
+	return scoped.repo.Upsert(db, collection, query, update)
+}
+
+func (scoped *tenantRepository) RemoveAll(db string, collection string, query bson.M) error {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return err
+	}
+	return scoped.repo.RemoveAll(db, collection, query)
+}
+
+// BulkInsert reports the documents of the other organisations as failed, the others are inserted
+func (scoped *tenantRepository) BulkInsert(db string, collection string, docs []interface{}) (map[int]error, error) {
+	if scoped.tenant.OrganisationId == "" {
+		return nil, ErrNoTenant
+	}
+	failed := make(map[int]error)
+	positions := make([]int, 0)
+	owned := make([]interface{}, 0)
+	for i, doc := range docs {
+		if err := scoped.owns(doc); err != nil {
+			failed[i] = err
+			continue
+		}
+		positions = append(positions, i)
+		owned = append(owned, doc)
+	}
+	if len(owned) == 0 {
+		return failed, nil
+	}
+	bulkFailed, err := scoped.repo.BulkInsert(db, collection, owned)
+	for p, errCase := range bulkFailed {
+		failed[positions[p]] = errCase
+	}
+	return failed, err
+}
+
+// BulkUpdate only applies the updates to the documents of the organisation of the tenant matching the query, the
+// updates moving a document to another organisation are reported as failed
+func (scoped *tenantRepository) BulkUpdate(db string, collection string, query bson.M, ids []bson.ObjectId, updates []interface{}) (map[int]error, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	failed := make(map[int]error)
+	positions := make([]int, 0)
+	allowedIds := make([]bson.ObjectId, 0)
+	allowed := make([]interface{}, 0)
+	for i, update := range updates {
+		if err := scoped.keeps(update); err != nil {
+			failed[i] = err
+			continue
+		}
+		positions = append(positions, i)
+		allowedIds = append(allowedIds, ids[i])
+		allowed = append(allowed, update)
+	}
+	if len(allowed) == 0 {
+		return failed, nil
+	}
+	bulkFailed, err := scoped.repo.BulkUpdate(db, collection, query, allowedIds, allowed)
+	for p, errCase := range bulkFailed {
+		failed[positions[p]] = errCase
+	}
+	return failed, err
+}
+
+func (scoped *tenantRepository) FindAssignments(db string, collection string, query bson.M) ([]model.AssignmentDAO, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindAssignments(db, collection, query)
+}
+
+// FindResources scopes the assignments grouped by resource. The tags query matches the groups, holding only the resource
+// and its tag ids, which come from the assignments of the organisation already.
+func (scoped *tenantRepository) FindResources(db string, collection string, query bson.M, tagsQuery bson.M, limit int) ([]model.ResourceRef, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindResources(db, collection, query, tagsQuery, limit)
+}
+
+func (scoped *tenantRepository) UsageByTag(db string, collection string, query bson.M) ([]model.TagUsage, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.UsageByTag(db, collection, query)
+}
+
+func (scoped *tenantRepository) UsageByResourceType(db string, collection string, query bson.M) ([]model.ResourceTypeUsage, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.UsageByResourceType(db, collection, query)
+}
+
+func (scoped *tenantRepository) FindHistory(db string, collection string, query bson.M, limit int) ([]model.HistoryDAO, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindHistory(db, collection, query, limit)
+}
+
+func (scoped *tenantRepository) FindEvents(db string, collection string, query bson.M, limit int) ([]model.EventDAO, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindEvents(db, collection, query, limit)
+}
+
+func (scoped *tenantRepository) FindWebhooks(db string, collection string, query bson.M) ([]model.WebhookDAO, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindWebhooks(db, collection, query)
+}
+
+func (scoped *tenantRepository) FindDeliveries(db string, collection string, query bson.M, sort string, limit int) ([]model.DeliveryDAO, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindDeliveries(db, collection, query, sort, limit)
+}
+
+func (scoped *tenantRepository) FindKeys(db string, collection string, query bson.M) ([]model.KeyDAO, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return scoped.repo.FindKeys(db, collection, query)
+}
+
+func (scoped *tenantRepository) FindPalette(db string, collection string) (model.PaletteDAO, error) {
+	if scoped.tenant.OrganisationId == "" {
+		return model.PaletteDAO{}, ErrNoTenant
+	}
+	return scoped.repo.FindPalette(db, collection, scoped.tenant.OrganisationId)
+}
+
+func (scoped *tenantRepository) FindSchema(db string, collection string) (model.SchemaDAO, error) {
+	if scoped.tenant.OrganisationId == "" {
+		return model.SchemaDAO{}, ErrNoTenant
+	}
+	return scoped.repo.FindSchema(db, collection, scoped.tenant.OrganisationId)
+}
+
+// FindMembership returns the membership of the account of the tenant
+func (scoped *tenantRepository) FindMembership(db string, collection string) (model.MembershipDAO, error) {
+	if scoped.tenant.OrganisationId == "" {
+		return model.MembershipDAO{}, ErrNoTenant
+	}
+	return scoped.repo.FindMembership(db, collection, scoped.tenant.OrganisationId, scoped.tenant.AccountId)
+}
+
+func (scoped *tenantRepository) FindMemberships(db string, collection string) ([]model.MembershipDAO, error) {
+	if scoped.tenant.OrganisationId == "" {
+		return nil, ErrNoTenant
+	}
+	return scoped.repo.FindMemberships(db, collection, scoped.tenant.OrganisationId)
+}
+
+func (scoped *tenantRepository) ReserveSequence(db string, collection string, reservation bson.ObjectId) (int64, error) {
+	if scoped.tenant.OrganisationId == "" {
+		return 0, ErrNoTenant
+	}
+	return scoped.repo.ReserveSequence(db, collection, scoped.tenant.OrganisationId, reservation)
+}
+
+func (scoped *tenantRepository) CompleteSequence(db string, collection string, reservation bson.ObjectId, expiredAt time.Time) error {
+	if scoped.tenant.OrganisationId == "" {
+		return ErrNoTenant
+	}
+	return scoped.repo.CompleteSequence(db, collection, scoped.tenant.OrganisationId, reservation, expiredAt)
+}
+
+func (scoped *tenantRepository) FindSequence(db string, collection string) (model.SequenceDAO, error) {
+	if scoped.tenant.OrganisationId == "" {
+		return model.SequenceDAO{}, ErrNoTenant
+	}
+	return scoped.repo.FindSequence(db, collection, scoped.tenant.OrganisationId)
+}
+
+// Returns a copy of the query restricted to the organisation of the tenant. A query naming another organisation is
+// rejected rather than silently narrowed.
+func (scoped *tenantRepository) scope(query bson.M) (bson.M, error) {
+	if scoped.tenant.OrganisationId == "" {
+		return nil, ErrNoTenant
+	}
+	result := bson.M{}
+	for field, value := range query {
+		result[field] = value
+	}
+	if value, ok := result[OrganisationField]; ok && value != scoped.tenant.OrganisationId {
+		return nil, ErrOtherTenant
+	}
+	result[OrganisationField] = scoped.tenant.OrganisationId
+	return result, nil
+}
+
+// Scopes the query of an update, which may not move the documents to another organisation.
+func (scoped *tenantRepository) scopeUpdate(query bson.M, update interface{}) (bson.M, error) {
+	query, err := scoped.scope(query)
+	if err != nil {
+		return nil, err
+	}
+	return query, scoped.keeps(update)
+}
+
+// Checks the document belongs to the organisation of the tenant.
+func (scoped *tenantRepository) owns(doc interface{}) error {
+	if scoped.tenant.OrganisationId == "" {
+		return ErrNoTenant
+	}
+	fields, err := fieldsOf(doc)
+	if err != nil {
+		return err
+	}
+	if fields[OrganisationField] != scoped.tenant.OrganisationId {
+		return ErrOtherTenant
+	}
+	return nil
+}
+
+// Checks the update, made of operators or replacing the whole document, leaves the organisation of the documents
+// unchanged.
+func (scoped *tenantRepository) keeps(update interface{}) error {
+	fields, err := fieldsOf(update)
+	if err != nil {
+		return err
+	}
+	for field, value := range fields {
+		if !strings.HasPrefix(field, "$") {
+			return scoped.owns(update)
+		}
+		operands, ok := value.(bson.M)
+		if !ok {
+			continue
+		}
+		if organisation, ok := operands[OrganisationField]; ok && organisation != scoped.tenant.OrganisationId {
+			return ErrOtherTenant
+		}
+	}
+	return nil
+}
+
+// Returns the top level fields of the document as stored.
+func fieldsOf(doc interface{}) (bson.M, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	fields := bson.M{}
+	err = bson.Unmarshal(data, &fields)
+	return fields, err
+}
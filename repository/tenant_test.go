@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"testing"
+)
+
+func TestTenantRepository_Reads(t *testing.T) {
+	t.Logf("Given a tag in each of two organisations")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		ours := model.TagDAO{Id: bson.NewObjectId(), Name: "Lunch", OrganisationId: organisationId}
+		theirs := model.TagDAO{Id: bson.NewObjectId(), Name: "Lunch", OrganisationId: bson.NewObjectId().Hex()}
+		RepositoryUnderTest.Insert("tags-db", "tenant", ours)
+		RepositoryUnderTest.Insert("tags-db", "tenant", theirs)
+		scoped := NewTenantRepository(RepositoryUnderTest, Tenant{OrganisationId: organisationId, AccountId: AccountId})
+
+		t.Logf("\tWhen reading the tags of the tenant")
+		{
+			if _, err := scoped.Find("tags-db", "tenant", theirs.Id); err == ErrNotFound {
+				t.Logf("\t\tThe tag of the other organisation should not be found %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag of the other organisation should not be found %v %v", test.BallotX, err)
+			}
+			if tag, err := scoped.Find("tags-db", "tenant", ours.Id); err == nil && tag.Id == ours.Id {
+				t.Logf("\t\tThe tag of the organisation should be found %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag of the organisation should be found %v %v", test.BallotX, err)
+			}
+			if tags, err := scoped.FindAll("tags-db", "tenant", bson.M{"name": "Lunch"}); err == nil && len(tags) == 1 && tags[0].Id == ours.Id {
+				t.Logf("\t\tOnly the tag of the organisation should be listed %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tOnly the tag of the organisation should be listed %v %v", test.BallotX, tags)
+			}
+		}
+
+		t.Logf("\tWhen the query names the other organisation")
+		{
+			if _, err := scoped.FindAll("tags-db", "tenant", bson.M{"organisationId": theirs.OrganisationId}); err == ErrOtherTenant {
+				t.Logf("\t\tThe query should be rejected %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe query should be rejected %v %v", test.BallotX, err)
+			}
+		}
+
+		t.Logf("\tWhen the tenant has no organisation")
+		{
+			if _, err := NewTenantRepository(RepositoryUnderTest, Tenant{}).FindAll("tags-db", "tenant", bson.M{}); err == ErrNoTenant {
+				t.Logf("\t\tThe query should be rejected %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe query should be rejected %v %v", test.BallotX, err)
+			}
+		}
+	}
+}
+
+func TestTenantRepository_Writes(t *testing.T) {
+	t.Logf("Given a tag in each of two organisations")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		ours := model.TagDAO{Id: bson.NewObjectId(), Name: "Lunch", OrganisationId: organisationId}
+		theirs := model.TagDAO{Id: bson.NewObjectId(), Name: "Lunch", OrganisationId: bson.NewObjectId().Hex()}
+		RepositoryUnderTest.Insert("tags-db", "tenant", ours)
+		RepositoryUnderTest.Insert("tags-db", "tenant", theirs)
+		scoped := NewTenantRepository(RepositoryUnderTest, Tenant{OrganisationId: organisationId, AccountId: AccountId})
+
+		t.Logf("\tWhen updating and removing the tags")
+		{
+			rename := bson.M{"$set": bson.M{"name": "Dinner"}}
+			if err := scoped.Update("tags-db", "tenant", theirs.Id, rename); err == ErrNotFound {
+				t.Logf("\t\tThe tag of the other organisation should not be updated %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag of the other organisation should not be updated %v %v", test.BallotX, err)
+			}
+			scoped.UpdateAll("tags-db", "tenant", bson.M{"name": "Lunch"}, rename)
+			scoped.BulkUpdate("tags-db", "tenant", bson.M{}, []bson.ObjectId{theirs.Id}, []interface{}{rename})
+			scoped.RemoveAll("tags-db", "tenant", bson.M{"name": "Lunch"})
+			if tag, err := RepositoryUnderTest.Find("tags-db", "tenant", theirs.Id); err == nil && tag.Name == "Lunch" {
+				t.Logf("\t\tThe tag of the other organisation should be unchanged %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe tag of the other organisation should be unchanged %v %v", test.BallotX, tag)
+			}
+		}
+
+		t.Logf("\tWhen moving the tag to the other organisation")
+		{
+			err := scoped.Update("tags-db", "tenant", ours.Id, bson.M{"$set": bson.M{"organisationId": theirs.OrganisationId}})
+			failed, _ := scoped.BulkUpdate("tags-db", "tenant", bson.M{}, []bson.ObjectId{ours.Id}, []interface{}{bson.M{"$unset": bson.M{"organisationId": ""}}})
+			if err == ErrOtherTenant && failed[0] == ErrOtherTenant {
+				t.Logf("\t\tThe updates should be rejected %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe updates should be rejected %v %v %v", test.BallotX, err, failed)
+			}
+		}
+
+		t.Logf("\tWhen inserting tags of both organisations")
+		{
+			err := scoped.Insert("tags-db", "tenant", model.TagDAO{Id: bson.NewObjectId(), OrganisationId: theirs.OrganisationId})
+			docs := []interface{}{&model.TagDAO{Id: bson.NewObjectId(), OrganisationId: theirs.OrganisationId}, &model.TagDAO{Id: bson.NewObjectId(), OrganisationId: organisationId}}
+			failed, errBulk := scoped.BulkInsert("tags-db", "tenant", docs)
+			if err == ErrOtherTenant && errBulk == nil && len(failed) == 1 && failed[0] == ErrOtherTenant {
+				t.Logf("\t\tOnly the tags of the organisation should be inserted %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tOnly the tags of the organisation should be inserted %v %v %v", test.BallotX, err, failed)
+			}
+		}
+
+		t.Logf("\tWhen upserting a document")
+		{
+			scoped.Upsert("tags-db", "tenant", bson.M{"name": "Brunch"}, bson.M{"$set": bson.M{"colour": "Red"}})
+			if tags, err := RepositoryUnderTest.FindAll("tags-db", "tenant", bson.M{"name": "Brunch"}); err == nil && len(tags) == 1 && tags[0].OrganisationId == organisationId {
+				t.Logf("\t\tThe document should be inserted within the organisation %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe document should be inserted within the organisation %v %v", test.BallotX, tags)
+			}
+		}
+	}
+}
+
+func TestTenantRepository_FindResources(t *testing.T) {
+	t.Logf("Given a resource tagged in each of two organisations")
+	{
+		organisationId := bson.NewObjectId().Hex()
+		resourceType := bson.NewObjectId().Hex()
+		tagId := bson.NewObjectId()
+		for _, orgId := range []string{organisationId, bson.NewObjectId().Hex()} {
+			query := bson.M{"organisationId": orgId, "resourceType": resourceType, "resourceId": orgId, "tagId": tagId}
+			RepositoryUnderTest.Upsert("tags-db", "assignments", query, bson.M{"$setOnInsert": bson.M{}})
+		}
+		scoped := NewTenantRepository(RepositoryUnderTest, Tenant{OrganisationId: organisationId, AccountId: AccountId})
+
+		t.Logf("\tWhen finding the resources holding the tag")
+		{
+			tagsQuery := bson.M{ResourceTagsField: tagId}
+			results, err := scoped.FindResources("tags-db", "assignments", bson.M{"resourceType": resourceType}, tagsQuery, 10)
+			if err == nil && len(results) == 1 && results[0].ResourceId == organisationId {
+				t.Logf("\t\tOnly the resource of the organisation should be found %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tOnly the resource of the organisation should be found %v %v %v", test.BallotX, results, err)
+			}
+		}
+	}
+}
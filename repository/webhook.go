@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+)
+
+// WebhookRepository interface for the webhooks and their deliveries
+type WebhookRepository interface {
+	FindWebhooks(database string, collection string, query bson.M) ([]model.WebhookDAO, error)
+	FindDeliveries(database string, collection string, query bson.M, sort string, limit int) ([]model.DeliveryDAO, error)
+	ClaimDelivery(database string, collection string, query bson.M, sort string, update interface{}) (model.DeliveryDAO, error)
+}
+
+// Implementation of Find webhooks from Mongo repository for given query
+func (repo *MongoRepository) FindWebhooks(db string, collection string, query bson.M) ([]model.WebhookDAO, error) {
+	var results []model.WebhookDAO
+	err := repo.Session.DB(db).C(collection).Find(query).Sort("_id").All(&results)
+	return results, classify(err)
+}
+
+// Implementation of Find deliveries from Mongo repository for given query, in the given order
+func (repo *MongoRepository) FindDeliveries(db string, collection string, query bson.M, sort string, limit int) ([]model.DeliveryDAO, error) {
+	var results []model.DeliveryDAO
+	err := repo.Session.DB(db).C(collection).Find(query).Sort(sort).Limit(limit).All(&results)
+	return results, classify(err)
+}
+
+// Implementation of Claim delivery from Mongo repository, applies the update to the first delivery matching the query
+// in the given order and returns it as updated. A delivery is only claimed once, ErrNotFound is returned when none
+// matches.
+func (repo *MongoRepository) ClaimDelivery(db string, collection string, query bson.M, sort string, update interface{}) (model.DeliveryDAO, error) {
+	var result model.DeliveryDAO
+	_, err := repo.Session.DB(db).C(collection).Find(query).Sort(sort).Apply(mgo.Change{Update: update, ReturnNew: true}, &result)
+	return result, classify(err)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"errors"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tag-service/model"
+	"github.com/tag-service/test"
+	"testing"
+	"time"
+)
+
+func TestMongoRepository_FindDeliveries(t *testing.T) {
+	t.Logf("Given two deliveries due at different times")
+	{
+		webhookId := bson.NewObjectId()
+		now := time.Now()
+		RepositoryUnderTest.Insert("tags-db", "deliveries", model.DeliveryDAO{Id: bson.NewObjectId(), WebhookId: webhookId, Status: model.DeliveryPending, NextAttemptAt: now.Add(time.Minute)})
+		RepositoryUnderTest.Insert("tags-db", "deliveries", model.DeliveryDAO{Id: bson.NewObjectId(), WebhookId: webhookId, Status: model.DeliveryPending, NextAttemptAt: now})
+
+		t.Logf("\tWhen finding the deliveries of the webhook by next attempt")
+		{
+			results, err := RepositoryUnderTest.FindDeliveries("tags-db", "deliveries", bson.M{"webhookId": webhookId}, "nextAttemptAt", 1)
+			if err == nil && len(results) == 1 && !results[0].NextAttemptAt.After(now) {
+				t.Logf("\t\tThe find deliveries should have returned the first delivery due %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe find deliveries should have returned the first delivery due %v %v", test.BallotX, results)
+			}
+		}
+	}
+}
+
+func TestMongoRepository_ClaimDelivery(t *testing.T) {
+	t.Logf("Given two deliveries due at different times")
+	{
+		webhookId := bson.NewObjectId()
+		now := time.Now()
+		first := model.DeliveryDAO{Id: bson.NewObjectId(), WebhookId: webhookId, Status: model.DeliveryPending, NextAttemptAt: now.Add(-time.Minute)}
+		RepositoryUnderTest.Insert("tags-db", "deliveries", first)
+		RepositoryUnderTest.Insert("tags-db", "deliveries", model.DeliveryDAO{Id: bson.NewObjectId(), WebhookId: webhookId, Status: model.DeliveryPending, NextAttemptAt: now})
+
+		t.Logf("\tWhen claiming the deliveries due one at a time")
+		{
+			due := bson.M{"webhookId": webhookId, "nextAttemptAt": bson.M{"$lte": now}}
+			claim := bson.M{"$set": bson.M{"nextAttemptAt": now.Add(time.Minute)}}
+			claimed, err := RepositoryUnderTest.ClaimDelivery("tags-db", "deliveries", due, "nextAttemptAt", claim)
+			if err == nil && claimed.Id == first.Id && claimed.NextAttemptAt.After(now) {
+				t.Logf("\t\tThe first delivery due should have been claimed %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe first delivery due should have been claimed %v %v %v", test.BallotX, claimed, err)
+			}
+
+			claimed, err = RepositoryUnderTest.ClaimDelivery("tags-db", "deliveries", due, "nextAttemptAt", claim)
+			if err == nil && claimed.Id != first.Id {
+				t.Logf("\t\tThe second delivery should have been claimed %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tThe second delivery should have been claimed %v %v %v", test.BallotX, claimed, err)
+			}
+
+			_, err = RepositoryUnderTest.ClaimDelivery("tags-db", "deliveries", due, "nextAttemptAt", claim)
+			if errors.Is(err, ErrNotFound) {
+				t.Logf("\t\tNo delivery should be left to claim %v", test.CheckMark)
+			} else {
+				t.Errorf("\t\tNo delivery should be left to claim %v %v", test.BallotX, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package search
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Relevance of a match, the higher the better. Fuzzy matches score between FuzzyMatch and SubstringMatch depending
+// on the number of edits.
+const (
+	ExactMatch      = 5.0
+	PrefixMatch     = 4.0
+	WordPrefixMatch = 3.0
+	SubstringMatch  = 2.0
+	FuzzyMatch      = 1.0
+
+	// queries shorter than this are only matched on prefix and substring, a single typo would match anything
+	MinFuzzyLength = 3
+)
+
+// Result is the position of a matching candidate together with its relevance.
+type Result struct {
+	Index int
+	Score float64
+	Match string
+}
+
+// Fold returns the form of the text used for comparison: width variants folded, accents removed and lower cased,
+// so that "Café", "CAFE" and "ｃａｆｅ" compare equal.
+func Fold(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(width.Fold.String(text)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// Score returns the relevance of the candidate for the query, 0 when it does not match. Both are expected to be
+// folded already.
+func Score(query string, candidate string) float64 {
+	if query == "" || candidate == "" {
+		return 0
+	}
+	switch {
+	case candidate == query:
+		return ExactMatch
+	case strings.HasPrefix(candidate, query):
+		return PrefixMatch
+	case wordPrefix(query, candidate):
+		return WordPrefixMatch
+	case strings.Contains(candidate, query):
+		return SubstringMatch
+	}
+	return fuzzy(query, candidate)
+}
+
+// Rank scores the names of every candidate against the query and returns the matching candidates, most relevant
+// first. A candidate may have several names, the best matching one is retained. Ties are ordered by name. At most
+// limit results are returned.
+func Rank(query string, candidates [][]string, limit int) []Result {
+	query = Fold(query)
+	results := make([]Result, 0)
+	for i, names := range candidates {
+		best := Result{Index: i}
+		for _, name := range names {
+			folded := Fold(name)
+			if score := Score(query, folded); score > best.Score || (score == best.Score && score > 0 && folded < best.Match) {
+				best.Score = score
+				best.Match = folded
+			}
+		}
+		if best.Score > 0 {
+			results = append(results, best)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Match < results[j].Match
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// Checks whether one of the words of the candidate, other than the first one, starts with the query.
+func wordPrefix(query string, candidate string) bool {
+	for i, word := range strings.FieldsFunc(candidate, separator) {
+		if i > 0 && strings.HasPrefix(word, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scores the candidate by the edit distance between the query and the closest prefix of the candidate or of one of
+// its words, so that typos are tolerated while typing. One edit is allowed per four characters of the query.
+func fuzzy(query string, candidate string) float64 {
+	q := []rune(query)
+	if len(q) < MinFuzzyLength {
+		return 0
+	}
+	allowed := 1 + len(q)/4
+
+	best := allowed + 1
+	for _, word := range append([]string{candidate}, strings.FieldsFunc(candidate, separator)...) {
+		w := []rune(word)
+		// compare against the prefixes around the length of the query
+		for n := len(q) - allowed; n <= len(q)+allowed; n++ {
+			if n <= 0 || n > len(w) {
+				continue
+			}
+			if d := distance(q, w[:n]); d < best {
+				best = d
+			}
+		}
+	}
+	if best > allowed {
+		return 0
+	}
+	return FuzzyMatch + (SubstringMatch-FuzzyMatch)*float64(allowed+1-best)/float64(allowed+2)
+}
+
+// Returns the optimal string alignment distance between a and b, i.e. the Levenshtein distance where swapping two
+// adjacent characters counts as a single edit.
+func distance(a []rune, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(min(rows[i-1][j]+1, rows[i][j-1]+1), rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+func separator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r)
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"testing"
+)
+
+const (
+	CheckMark = "✓"
+	BallotX   = "✗"
+)
+
+func TestFoldIgnoresCaseAccentsAndWidth(t *testing.T) {
+	t.Logf("Given names differing by case, accents and width")
+	{
+		for _, name := range []string{"Café", "CAFE", "café", "ｃａｆｅ", "  cafe "} {
+			if folded := Fold(name); folded == "cafe" {
+				t.Logf("\t\t\"%s\" should fold to \"cafe\". %v", name, CheckMark)
+			} else {
+				t.Errorf("\t\t\"%s\" should fold to \"cafe\":  \"%s\". %v", name, folded, BallotX)
+			}
+		}
+	}
+}
+
+func TestScoreOrdering(t *testing.T) {
+	t.Logf("Given the query \"fin\"")
+	{
+		exact := Score("fin", "fin")
+		prefix := Score("fin", "finance")
+		word := Score("fin", "personal finance")
+		substring := Score("fin", "define")
+		fuzzy := Score("fin", "fni")
+		if exact > prefix && prefix > word && word > substring && substring > fuzzy && fuzzy > 0 {
+			t.Logf("\t\tThe matches should be ranked exact, prefix, word prefix, substring, fuzzy. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe matches should be ranked exact, prefix, word prefix, substring, fuzzy:  %v %v %v %v %v. %v", exact, prefix, word, substring, fuzzy, BallotX)
+		}
+
+		if score := Score("fin", "travel"); score == 0 {
+			t.Logf("\t\tAn unrelated name should not match. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tAn unrelated name should not match:  %v. %v", score, BallotX)
+		}
+	}
+}
+
+func TestRankToleratesTypos(t *testing.T) {
+	t.Logf("Given a misspelt query")
+	{
+		candidates := [][]string{{"Travel"}, {"Accounting"}, {"Accomodation"}, {"Accommodation"}}
+		results := Rank("acommodation", candidates, 10)
+		if len(results) == 2 && results[0].Index == 3 && results[1].Index == 2 {
+			t.Logf("\t\tThe closest names should be returned first. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe closest names should be returned first:  %v. %v", results, BallotX)
+		}
+	}
+}
+
+func TestRankLimitsAndOrdersTies(t *testing.T) {
+	t.Logf("Given several names starting with the query")
+	{
+		candidates := [][]string{{"Urgent"}, {"Finance"}, {"Fines"}, {"Final"}}
+		results := Rank("FIN", candidates, 2)
+		if len(results) == 2 && results[0].Index == 3 && results[1].Index == 1 {
+			t.Logf("\t\tThe names should be ordered alphabetically and limited. %v", CheckMark)
+		} else {
+			t.Errorf("\t\tThe names should be ordered alphabetically and limited:  %v. %v", results, BallotX)
+		}
+	}
+}
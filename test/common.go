@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
 	"github.com/tag-service/model"
 	"net/http"
 	"net/http/httptest"
@@ -17,6 +18,9 @@ const (
 	// AccountID2 embedded in Token2
 	AccountID2 = "5a3922ac86da0c1d779a776"
 
+	// AccountID3 embedded in Token3
+	AccountID3 = "1D3344ac86da0c1d779a881"
+
 	// OrgID1 Organisation Id1 used in integration test.
 	OrgID1 = "494010c0-5e76-11e8-9c2d-fa7ae01bbebc"
 
@@ -74,6 +78,11 @@ func CreateTag(body model.CreateTagRequest, router *gin.Engine, t *testing.T, to
 	return response.Id
 }
 
+// UniqueName helper, tag names are unique within an organisation and the integration tests share the same database.
+func UniqueName(name string) string {
+	return name + " " + bson.NewObjectId().Hex()
+}
+
 // HttpRequest helper
 func HttpRequest(jsonReq interface{}, endpoint string, method string, token string, orgId string) (*http.Request, error) {
 	req, err := http.NewRequest(method, endpoint, RequestBody(jsonReq))